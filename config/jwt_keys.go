@@ -0,0 +1,269 @@
+package config
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTKeySet menyimpan key untuk menandatangani dan memverifikasi JWT.
+//
+// Jika JWT_KEYS_DIR diset, setiap file <kid>.pem berisi private key RSA/Ed25519 dan file <kid>.pub.pem
+// berisi public key yang hanya dipakai untuk verifikasi (key lama yang sedang dirotasi keluar).
+// Token ditandatangani dengan key JWT_SIGNING_KID (RS256 atau EdDSA) dan header "kid".
+// Jika JWT_SECRET diset, token HS256 lama tetap diterima; tanpa JWT_KEYS_DIR token baru juga ditandatangani dengan HS256.
+type JWTKeySet struct {
+	mu         sync.RWMutex
+	dir        string
+	signingKid string
+	signing    crypto.Signer
+	verify     map[string]crypto.PublicKey
+	secret     []byte
+}
+
+// LoadJWTKeySet membaca konfigurasi key dari env JWT_KEYS_DIR, JWT_SIGNING_KID dan JWT_SECRET
+func LoadJWTKeySet() (*JWTKeySet, error) {
+	ks := &JWTKeySet{dir: os.Getenv("JWT_KEYS_DIR")}
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Reload membaca ulang key dari disk (dipakai saat rotasi key tanpa restart)
+func (ks *JWTKeySet) Reload() error {
+	secret := []byte(os.Getenv("JWT_SECRET"))
+	signingKid := os.Getenv("JWT_SIGNING_KID")
+
+	var signing crypto.Signer
+	verify := make(map[string]crypto.PublicKey)
+
+	if ks.dir != "" {
+		privates, publics, err := readKeyDir(ks.dir)
+		if err != nil {
+			return err
+		}
+		for kid, pub := range publics {
+			verify[kid] = pub
+		}
+		for kid, priv := range privates {
+			verify[kid] = priv.Public()
+		}
+
+		if signingKid == "" {
+			if len(privates) != 1 {
+				return fmt.Errorf("JWT_SIGNING_KID must be set when JWT_KEYS_DIR contains %d private keys", len(privates))
+			}
+			for kid := range privates {
+				signingKid = kid
+			}
+		}
+		signing = privates[signingKid]
+		if signing == nil {
+			return fmt.Errorf("private key for JWT_SIGNING_KID %q not found in %s", signingKid, ks.dir)
+		}
+	} else {
+		if len(secret) == 0 {
+			return errors.New("either JWT_KEYS_DIR or JWT_SECRET must be set")
+		}
+		signingKid = ""
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.signingKid = signingKid
+	ks.signing = signing
+	ks.verify = verify
+	ks.secret = secret
+	return nil
+}
+
+// Sign menandatangani claims dengan key aktif dan menambahkan header "kid"
+func (ks *JWTKeySet) Sign(claims jwt.Claims) (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if ks.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.secret)
+	}
+
+	token := jwt.NewWithClaims(signingMethodFor(ks.signing.Public()), claims)
+	token.Header["kid"] = ks.signingKid
+	return token.SignedString(ks.signing)
+}
+
+// Keyfunc dipakai jwt.Parse untuk memilih key verifikasi berdasarkan header "kid" dan algoritma token
+func (ks *JWTKeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if len(ks.secret) == 0 {
+			return nil, errors.New("HMAC tokens are not accepted")
+		}
+		return ks.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	pub, ok := ks.verify[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != signingMethodFor(pub).Alg() {
+		return nil, fmt.Errorf("algorithm %s does not match key %q", token.Method.Alg(), kid)
+	}
+	return pub, nil
+}
+
+// ValidMethods mengembalikan algoritma yang diterima saat verifikasi
+func (ks *JWTKeySet) ValidMethods() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	methods := []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+	if len(ks.secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	return methods
+}
+
+// JWK adalah representasi public key dalam format JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKS mengembalikan seluruh public key verifikasi (diurutkan berdasarkan kid)
+func (ks *JWTKeySet) JWKS() []JWK {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	kids := make([]string, 0, len(ks.verify))
+	for kid := range ks.verify {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	keys := make([]JWK, 0, len(kids))
+	for _, kid := range kids {
+		switch pub := ks.verify[kid].(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, JWK{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				Alg: jwt.SigningMethodRS256.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			keys = append(keys, JWK{
+				Kty: "OKP",
+				Kid: kid,
+				Use: "sig",
+				Alg: jwt.SigningMethodEdDSA.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	return keys
+}
+
+func signingMethodFor(pub crypto.PublicKey) jwt.SigningMethod {
+	if _, ok := pub.(ed25519.PublicKey); ok {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+// readKeyDir membaca <kid>.pem (private key) dan <kid>.pub.pem (public key) dari direktori
+func readKeyDir(dir string) (map[string]crypto.Signer, map[string]crypto.PublicKey, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list JWT keys: %w", err)
+	}
+
+	privates := make(map[string]crypto.Signer)
+	publics := make(map[string]crypto.PublicKey)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read JWT key %s: %w", file, err)
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, nil, fmt.Errorf("invalid PEM in %s", file)
+		}
+
+		name := filepath.Base(file)
+		if strings.HasSuffix(name, ".pub.pem") {
+			pub, err := parsePublicKey(block)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid public key %s: %w", file, err)
+			}
+			publics[strings.TrimSuffix(name, ".pub.pem")] = pub
+			continue
+		}
+
+		priv, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid private key %s: %w", file, err)
+		}
+		privates[strings.TrimSuffix(name, ".pem")] = priv
+	}
+	return privates, publics, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("unsupported private key type %T (use RSA or Ed25519)", key)
+}
+
+func parsePublicKey(block *pem.Block) (crypto.PublicKey, error) {
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return k, nil
+	case ed25519.PublicKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type %T (use RSA or Ed25519)", key)
+}
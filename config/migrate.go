@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"warehouse-inventory-server/migrations"
+
+	"gorm.io/gorm"
+)
+
+// RunMigrations menjalankan file migrasi yang belum pernah dijalankan dan mencatatnya di tabel schema_migrations
+func RunMigrations(db *gorm.DB) error {
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version VARCHAR(255) PRIMARY KEY,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`).Error; err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	files, err := fs.Glob(migrations.FS, "*.sql")
+	if err != nil {
+		return fmt.Errorf("failed to list migration files: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, name := range files {
+		var count int64
+		if err := db.Table("schema_migrations").Where("version = ?", name).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", name, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		script, err := fs.ReadFile(migrations.FS, name)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(string(script)).Error; err != nil {
+				return err
+			}
+			return tx.Exec("INSERT INTO schema_migrations (version) VALUES (?)", name).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
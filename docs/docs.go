@@ -20,53 +20,47 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/auth/login": {
-            "post": {
-                "description": "Authenticate user and return JWT token",
-                "consumes": [
-                    "application/json"
-                ],
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public key untuk memverifikasi JWT yang diterbitkan server ini (dipakai service internal lain)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Login user",
-                "parameters": [
-                    {
-                        "description": "Login Request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.LoginRequest"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
+                }
+            }
+        },
+        "/api/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Daftar API key beserta prefix, scope, masa berlaku dan waktu terakhir dipakai",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Key"
                 ],
+                "summary": "Get all API keys (Admin only)",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.LoginResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/middleware.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "$ref": "#/definitions/middleware.ErrorResponse"
+                            "$ref": "#/definitions/models.APIKeyResponse"
                         }
                     },
                     "500": {
@@ -76,16 +70,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/auth/register": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Register a new staff user. Only admin can perform this action.",
+                "description": "Membuat API key untuk integrasi machine-to-machine. Key lengkap hanya ditampilkan sekali",
                 "consumes": [
                     "application/json"
                 ],
@@ -93,17 +85,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "API Key"
                 ],
-                "summary": "Register new user (Admin only)",
+                "summary": "Create API key (Admin only)",
                 "parameters": [
                     {
-                        "description": "Register Request",
+                        "description": "API Key Request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.RegisterRequest"
+                            "$ref": "#/definitions/models.APIKeyRequest"
                         }
                     }
                 ],
@@ -111,7 +103,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.RegisterResponse"
+                            "$ref": "#/definitions/models.CreatedAPIKeyResponse"
                         }
                     },
                     "400": {
@@ -135,66 +127,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/barang": {
-            "get": {
+        "/api/api-keys/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mendapatkan daftar seluruh barang",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Mencabut API key sehingga tidak bisa dipakai lagi",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Barang"
+                    "API Key"
                 ],
-                "summary": "Get all barang",
+                "summary": "Revoke API key (Admin only)",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Search by name",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.BarangResponse"
+                            "$ref": "#/definitions/models.APIKeyResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/middleware.ValidationError"
+                            "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/auth/2fa/activate": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Membuat barang baru",
+                "description": "Memverifikasi kode TOTP pertama, mengaktifkan 2FA dan mengembalikan backup code (hanya ditampilkan sekali)",
                 "consumes": [
                     "application/json"
                 ],
@@ -202,25 +194,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Barang"
+                    "Auth"
                 ],
-                "summary": "Create new barang",
+                "summary": "Activate 2FA",
                 "parameters": [
                     {
-                        "description": "Barang Request",
+                        "description": "TOTP Code",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.BarangRequest"
+                            "$ref": "#/definitions/models.TwoFactorCodeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CreatedBarangResponse"
+                            "$ref": "#/definitions/models.TwoFactorActivateResponse"
                         }
                     },
                     "400": {
@@ -229,12 +221,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/middleware.SpecificErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "$ref": "#/definitions/middleware.ValidationError"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -244,55 +230,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/barang/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Mendapatkan detail barang berdasarkan ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Barang"
-                ],
-                "summary": "Get barang by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Barang ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.BarangResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
+        "/api/auth/2fa/backup-codes": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Memperbarui detail barang berdasarkan ID",
+                "description": "Mengganti seluruh backup code lama dengan yang baru (hanya ditampilkan sekali). Membutuhkan kode TOTP atau backup code",
                 "consumes": [
                     "application/json"
                 ],
@@ -300,24 +245,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Barang"
+                    "Auth"
                 ],
-                "summary": "Update barang by ID",
+                "summary": "Regenerate 2FA backup codes",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Barang ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Barang Request",
+                        "description": "TOTP Code",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.BarangRequest"
+                            "$ref": "#/definitions/models.TwoFactorCodeRequest"
                         }
                     }
                 ],
@@ -325,7 +263,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.BarangResponse"
+                            "$ref": "#/definitions/models.TwoFactorActivateResponse"
                         }
                     },
                     "400": {
@@ -334,16 +272,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/middleware.SpecificErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "423": {
+                        "description": "Locked",
                         "schema": {
-                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                            "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
-                            "$ref": "#/definitions/middleware.ValidationError"
+                            "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     },
                     "500": {
@@ -353,14 +291,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/auth/2fa/disable": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Menghapus barang berdasarkan ID",
+                "description": "Menonaktifkan 2FA milik user yang sedang login dengan kode TOTP atau backup code. Tidak bisa dilakukan jika 2FA diwajibkan",
                 "consumes": [
                     "application/json"
                 ],
@@ -368,39 +308,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Barang"
+                    "Auth"
                 ],
-                "summary": "Delete barang by ID",
+                "summary": "Disable 2FA",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Barang ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "TOTP Code",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorCodeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DeleteBarangResponse"
+                            "$ref": "#/definitions/models.TwoFactorMessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request (Stock \u003e 0)",
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "423": {
+                        "description": "Locked",
                         "schema": {
                             "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/middleware.ErrorResponse"
                         }
@@ -414,40 +362,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/history-stok": {
-            "get": {
+        "/api/auth/2fa/enroll": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of stock history with pagination",
+                "description": "Membuat secret TOTP baru dan mengembalikan otpauth URI. 2FA baru aktif setelah kode diverifikasi di /api/auth/2fa/activate",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "History Stok"
-                ],
-                "summary": "Get all stock history",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
-                    }
+                    "Auth"
                 ],
+                "summary": "Start 2FA enrollment",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.HistoryStokResponse"
+                            "$ref": "#/definitions/models.TwoFactorEnrollResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     },
                     "500": {
@@ -459,47 +399,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/history-stok/{barang_id}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/api/auth/forgot-password": {
+            "post": {
+                "description": "Mengirim email berisi token reset password (sekali pakai dan kedaluwarsa) di background. Response dan waktunya selalu sama agar tidak membocorkan email terdaftar. Dibatasi per IP (429) dan per email (token baru tidak dikirim selama token sebelumnya masih aktif dan berumur kurang dari PASSWORD_RESET_COOLDOWN_MINUTES)",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get stock history for a specific barang with pagination",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "History Stok"
+                    "Auth"
                 ],
-                "summary": "Get stock history by barang ID",
+                "summary": "Request password reset",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Barang ID",
-                        "name": "barang_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Forgot Password Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ForgotPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.HistoryStokResponse"
+                            "$ref": "#/definitions/models.PasswordResetResponse"
                         }
                     },
                     "400": {
@@ -508,52 +436,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/middleware.SpecificErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/middleware.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/pembelian": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get a list of all purchase transactions",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Pembelian"
-                ],
-                "summary": "Get all purchases",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.PembelianResponse"
-                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
-                            "$ref": "#/definitions/middleware.ValidationError"
+                            "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/auth/login": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Create a new purchase transaction",
+                "description": "Authenticate user and return JWT token",
                 "consumes": [
                     "application/json"
                 ],
@@ -561,116 +461,175 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Pembelian"
+                    "Auth"
                 ],
-                "summary": "Create new purchase",
+                "summary": "Login user",
                 "parameters": [
                     {
-                        "description": "Purchase Request",
+                        "description": "Login Request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.BeliHeaderRequest"
+                            "$ref": "#/definitions/models.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PembelianResponse"
+                            "$ref": "#/definitions/models.LoginResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/middleware.ValidationError"
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
-                            "$ref": "#/definitions/middleware.ValidationError"
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/middleware.ValidationError"
+                            "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/pembelian/{id}": {
+        "/api/auth/login-audit": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get details of a specific purchase transaction",
+                "description": "Daftar percobaan login (sukses dan gagal) beserta IP dan user agent",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Pembelian"
+                    "Auth"
                 ],
-                "summary": "Get purchase by ID",
+                "summary": "Get login audit (Admin only)",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by email",
+                        "name": "email",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Purchase ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PembelianResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.LoginAudit"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/penjualan": {
-            "get": {
+        "/api/auth/login/2fa": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of all sale transactions",
+                "description": "Menukar challenge token (dikirim sebagai Bearer token) dan kode TOTP atau backup code dengan JWT akses",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Penjualan"
+                    "Auth"
+                ],
+                "summary": "Complete login with 2FA code",
+                "parameters": [
+                    {
+                        "description": "2FA Code",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorCodeRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all sales",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PenjualanResponse"
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
                         }
                     },
                     "500": {
@@ -680,14 +639,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/auth/register": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new sale transaction",
+                "description": "Register a new staff user. Only admin can perform this action.",
                 "consumes": [
                     "application/json"
                 ],
@@ -695,17 +656,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Penjualan"
+                    "Auth"
                 ],
-                "summary": "Create new sale",
+                "summary": "Register new user (Admin only)",
                 "parameters": [
                     {
-                        "description": "Sale Request",
+                        "description": "Register Request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.JualHeaderRequest"
+                            "$ref": "#/definitions/models.RegisterRequest"
                         }
                     }
                 ],
@@ -713,13 +674,13 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.PenjualanResponse"
+                            "$ref": "#/definitions/models.RegisterResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/middleware.ErrorResponse"
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
                         }
                     },
                     "422": {
@@ -737,35 +698,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/penjualan/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/api/auth/reset-password": {
+            "post": {
+                "description": "Mengganti password menggunakan token dari email reset password. Token hanya bisa dipakai sekali",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get details of a specific sale transaction",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Penjualan"
+                    "Auth"
                 ],
-                "summary": "Get sale by ID",
+                "summary": "Reset password",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Sale ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Reset Password Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResetPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PenjualanResponse"
+                            "$ref": "#/definitions/models.PasswordResetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
                         }
                     },
                     "422": {
@@ -783,57 +750,90 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/stok": {
-            "get": {
+        "/api/auth/users/{id}/2fa-requirement": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of all stock items",
+                "description": "Mewajibkan atau membebaskan user dari 2FA. Jika wajib, user harus enrollment 2FA saat login berikutnya",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stok"
+                    "Auth"
+                ],
+                "summary": "Set 2FA requirement for a user (Admin only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Requirement",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorRequirementRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all stock",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MstokResponse"
+                            "$ref": "#/definitions/models.TwoFactorMessageResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/middleware.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/stok/{barang_id}": {
-            "get": {
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/auth/users/{id}/unlock": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get stock details for a specific barang",
+                "description": "Membuka kunci akun yang terkunci karena terlalu banyak gagal login",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stok"
+                    "Auth"
                 ],
-                "summary": "Get stock by barang ID",
+                "summary": "Unlock user account (Admin only)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Barang ID",
-                        "name": "barang_id",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -842,14 +842,281 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MstokResponse"
+                            "$ref": "#/definitions/models.UnlockUserResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/barang": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Mendapatkan daftar seluruh barang",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Get all barang",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cari kode, nama dan deskripsi (full-text + toleran salah ketik), hasil diurutkan berdasarkan relevansi",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter satuan (pisahkan dengan koma untuk lebih dari satu)",
+                        "name": "satuan",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Harga jual minimum",
+                        "name": "min_harga",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Harga jual maksimum",
+                        "name": "max_harga",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "in_stock, low atau out",
+                        "name": "stock_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam,ukuran:xl)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv atau xlsx. csv/xlsx mengunduh seluruh hasil filter tanpa paginasi",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (mode page/limit)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor dari meta.next_cursor; kirim kosong (?cursor=) untuk halaman pertama mode cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BarangResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Membuat barang baru",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Create new barang",
+                "parameters": [
+                    {
+                        "description": "Barang Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BarangRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatedBarangResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
                         }
                     },
                     "422": {
                         "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/middleware.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/api/barang/harga-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Riwayat perubahan harga beli/jual barang (siapa, kapan, sumber perubahan), terbaru dahulu",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Get price change history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter barang",
+                        "name": "barang_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "manual, bulk atau import",
+                        "name": "sumber",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HargaHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -859,479 +1126,5869 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "middleware.ErrorResponse": {
+        },
+        "/api/barang/harga/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Mengubah harga beli dan/atau harga jual banyak barang sekaligus (Admin only). Barang dipilih dengan tepat satu dari barang_ids, filter (search, satuan, stock_status, kategori_id, brand_id) atau supplier (barang yang pernah dibeli dari supplier tersebut). Perubahan persen atau nominal, lalu dibulatkan ke kelipatan pembulatan. dry_run=true menampilkan preview tanpa menyimpan. Setiap perubahan dicatat di riwayat harga",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Bulk update harga barang",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Preview tanpa menyimpan",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Bulk Harga Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkHargaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkHargaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/barang/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Membuat atau memperbarui barang secara massal dari file CSV/XLSX (Admin only). Baris dicocokkan berdasarkan kode_barang: kode yang sudah ada diperbarui, kode baru atau kosong dibuat (kode kosong mendapat kode otomatis; kode baru berformat kode otomatis BRGxxx untuk ID yang belum ada ditolak). Saat update, deskripsi/kategori/brand/harga_beli/harga_jual yang kosong atau kolomnya tidak ada tidak mengubah nilai lama. Kolom: kode_barang, nama_barang, deskripsi, satuan, kategori, brand, harga_beli, harga_jual, stok_awal (sama dengan kolom export). kategori dan brand berisi nama yang sudah terdaftar (tidak case-sensitive). stok_awal hanya untuk barang baru dan dicatat sebagai history adjustment. Validasi sama seperti create barang; jika ada baris yang gagal, tidak ada data yang disimpan. dry_run=true hanya mengembalikan laporan validasi",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Import barang from CSV/XLSX",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "File .csv atau .xlsx (baris pertama header)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validasi saja tanpa menyimpan",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BarangImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.BarangImportResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/barang/labels": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Membuat label barang (barcode, nama, harga jual) sebagai PDF untuk kertas label A4 atau ZPL untuk printer thermal, sesuai format template. Simbologi code128 dan qr memakai kode_barang; ean13 memakai barcode EAN-13 pertama yang terdaftar pada barang",
+                "produces": [
+                    "application/pdf",
+                    "text/plain"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Generate barang labels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID barang, pisahkan dengan koma",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nama template (default a4-3x8), lihat /api/barang/labels/templates",
+                        "name": "template",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "code128 (default), ean13 atau qr",
+                        "name": "symbology",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah salinan per barang (default 1). Jumlah barang x copies maksimal 2000 label",
+                        "name": "copies",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tampilkan nama barang (default sesuai template)",
+                        "name": "show_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tampilkan harga jual (default sesuai template)",
+                        "name": "show_price",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "PDF atau ZPL",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/barang/labels/templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Daftar template label bawaan dan dari LABEL_TEMPLATES_FILE",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "List label templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LabelTemplate"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/barang/scan/{barcode}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Mencari barang berdasarkan barcode (EAN/UPC) atau kode_barang, beserta stok saat ini",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Scan barcode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Barcode atau kode barang",
+                        "name": "barcode",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BarangResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/barang/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Mendapatkan detail barang berdasarkan ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Get barang by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barang ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BarangResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Memperbarui detail barang berdasarkan ID. kategori_id, brand_id dan atribut hanya diubah jika dikirim (null untuk mengosongkan)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Update barang by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barang ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Barang Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BarangRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BarangResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Menghapus barang berdasarkan ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Delete barang by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barang ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteBarangResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request (Stock \u003e 0)",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/barang/{id}/barcodes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Menambahkan barcode EAN-8, UPC-A, EAN-13 atau GTIN-14 ke barang (Admin only). Check digit divalidasi; barcode disimpan sebagai GTIN-14 (diawali nol) dan harus unik, sehingga UPC-A dan EAN-13 untuk produk yang sama ditolak dengan 409",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Add barcode to barang",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barang ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Barcode Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BarcodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.BarangResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/barang/{id}/barcodes/{barcode}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Menghapus barcode dari barang (Admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Delete barcode from barang",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barang ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Barcode",
+                        "name": "barcode",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteBarangResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/barang/{id}/harga-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Riwayat perubahan harga satu barang, terbaru dahulu",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barang"
+                ],
+                "summary": "Get price change history by barang ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barang ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "manual, bulk atau import",
+                        "name": "sumber",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HargaHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/brand": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Daftar seluruh brand (urut nama) beserta jumlah barangnya",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Brand"
+                ],
+                "summary": "Get all brand",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BrandResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Membuat brand baru (Admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Brand"
+                ],
+                "summary": "Create brand",
+                "parameters": [
+                    {
+                        "description": "Brand Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BrandRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Brand"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/brand/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Mengganti nama brand (Admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Brand"
+                ],
+                "summary": "Update brand",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Brand ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Brand Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BrandRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Brand"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Menghapus brand (Admin only). Brand yang masih dipakai barang tidak dapat dihapus",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Brand"
+                ],
+                "summary": "Delete brand",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Brand ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteBrandResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/dashboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "KPI halaman utama: total penjualan/pembelian hari ini \u0026 bulan ini, nilai stok, jumlah barang stok rendah, top 5 barang terlaris bulan ini dan pergerakan stok terbaru. Filter kategori/brand/atribut membatasi semua KPI ke barang yang cocok (total transaksi dihitung dari subtotal detail barang tersebut); group_by menambahkan nilai stok per kelompok di field groups. Data di-cache 30 detik per kombinasi filter",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dashboard"
+                ],
+                "summary": "Dashboard summary",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nilai stok per kategori, brand atau atribut (di field groups)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "group_by kategori: level kategori (default 1 = kategori utama)",
+                        "name": "kategori_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "group_by atribut: nama atribut",
+                        "name": "atribut_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DashboardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/history-stok": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a list of stock history with filters, sorting and pagination",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "History Stok"
+                ],
+                "summary": "Get all stock history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (mode page/limit)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor dari meta.next_cursor; kirim kosong (?cursor=) untuk halaman pertama mode cursor (hanya sort created_at)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv atau xlsx. csv/xlsx mengunduh seluruh hasil filter tanpa paginasi",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "masuk, keluar, adjustment (pisahkan dengan koma untuk lebih dari satu)",
+                        "name": "jenis_transaksi",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cari di keterangan / no faktur",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), jumlah, jenis_transaksi, barang_id",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "desc (default) atau asc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HistoryStokResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/history-stok/{barang_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get stock history for a specific barang with filters, sorting and pagination",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "History Stok"
+                ],
+                "summary": "Get stock history by barang ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barang ID",
+                        "name": "barang_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (mode page/limit)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor dari meta.next_cursor; kirim kosong (?cursor=) untuk halaman pertama mode cursor (hanya sort created_at)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv atau xlsx. csv/xlsx mengunduh seluruh hasil filter tanpa paginasi",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "masuk, keluar, adjustment (pisahkan dengan koma untuk lebih dari satu)",
+                        "name": "jenis_transaksi",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cari di keterangan / no faktur",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), jumlah, jenis_transaksi, barang_id",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "desc (default) atau asc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HistoryStokResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/kategori": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Daftar seluruh kategori barang (bertingkat) diurutkan berdasarkan nama lengkap, beserta level dan jumlah barang termasuk sub kategori",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kategori"
+                ],
+                "summary": "Get all kategori",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.KategoriResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Membuat kategori baru (Admin only). parent_id kosong untuk kategori utama",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kategori"
+                ],
+                "summary": "Create kategori",
+                "parameters": [
+                    {
+                        "description": "Kategori Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.KategoriRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.KategoriResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/kategori/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Detail kategori beserta level, nama lengkap dan jumlah barang termasuk sub kategori",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kategori"
+                ],
+                "summary": "Get kategori by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Kategori ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KategoriResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Mengganti nama dan/atau parent kategori (Admin only). Sub kategori ikut pindah bersama kategorinya",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kategori"
+                ],
+                "summary": "Update kategori",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Kategori ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Kategori Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.KategoriRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KategoriResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Menghapus kategori (Admin only). Kategori yang masih memiliki sub kategori atau barang tidak dapat dihapus",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kategori"
+                ],
+                "summary": "Delete kategori",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Kategori ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteKategoriResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/pembelian": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of purchase transaction headers (tanpa detail). Detail diambil lewat GET /api/pembelian/{id} atau include=details",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Pembelian"
+                ],
+                "summary": "Get all purchases",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (mode page/limit)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, maksimal 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor dari meta.next_cursor; kirim kosong (?cursor=) untuk halaman pertama mode cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv atau xlsx. csv/xlsx mengunduh seluruh hasil filter tanpa paginasi",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter supplier (sebagian nama)",
+                        "name": "supplier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter status (draft, selesai)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter no faktur (sebagian)",
+                        "name": "no_faktur",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "details: setiap item berisi header dan details (bentuk response lama). Tanpa page/limit/cursor, seluruh data dikembalikan tanpa paginasi",
+                        "name": "include",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BeliHeaderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new purchase transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pembelian"
+                ],
+                "summary": "Create new purchase",
+                "parameters": [
+                    {
+                        "description": "Purchase Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BeliHeaderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PembelianResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/pembelian/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get details of a specific purchase transaction",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pembelian"
+                ],
+                "summary": "Get purchase by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PembelianResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/pembelian/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Mengubah pembelian berstatus \"draft\" (misal dari saran pembelian) menjadi \"selesai\" dan menambahkan stok.\nPembuat draft dan created_at tidak berubah; user dan waktu konfirmasi dicatat di confirmed_by dan confirmed_at.\nDitolak (409) jika harga di draft sudah tidak sama dengan harga beli master barang",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pembelian"
+                ],
+                "summary": "Confirm draft purchase",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PembelianResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/penjualan": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of sale transaction headers (tanpa detail). Detail diambil lewat GET /api/penjualan/{id} atau include=details",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Penjualan"
+                ],
+                "summary": "Get all sales",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (mode page/limit)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, maksimal 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor dari meta.next_cursor; kirim kosong (?cursor=) untuk halaman pertama mode cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv atau xlsx. csv/xlsx mengunduh seluruh hasil filter tanpa paginasi",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter customer (sebagian nama)",
+                        "name": "customer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter status (draft, selesai)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter no faktur (sebagian)",
+                        "name": "no_faktur",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "details: setiap item berisi header dan details (bentuk response lama). Tanpa page/limit/cursor, seluruh data dikembalikan tanpa paginasi",
+                        "name": "include",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JualHeaderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new sale transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Penjualan"
+                ],
+                "summary": "Create new sale",
+                "parameters": [
+                    {
+                        "description": "Sale Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.JualHeaderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PenjualanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/penjualan/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get details of a specific sale transaction",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Penjualan"
+                ],
+                "summary": "Get sale by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sale ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PenjualanResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/replenishment/draft": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Menghitung saran pembelian dan membuat satu pembelian berstatus \"draft\" per supplier. Draft dikonfirmasi lewat POST /api/pembelian/{id}/confirm",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Replenishment"
+                ],
+                "summary": "Create draft purchases from suggestions",
+                "parameters": [
+                    {
+                        "description": "Draft Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReplenishmentDraftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReplenishmentDraftResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/replenishment/lead-times": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Daftar lead time (hari) per supplier. Supplier yang tidak terdaftar memakai REPLENISHMENT_LEAD_TIME_DAYS",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Replenishment"
+                ],
+                "summary": "Get supplier lead times",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierLeadTime"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Membuat atau memperbarui lead time untuk supplier (Admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Replenishment"
+                ],
+                "summary": "Set supplier lead time",
+                "parameters": [
+                    {
+                        "description": "Lead Time Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierLeadTimeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierLeadTime"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/replenishment/lead-times/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Menghapus lead time supplier sehingga kembali memakai lead time default (Admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Replenishment"
+                ],
+                "summary": "Delete supplier lead time",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Lead Time ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/replenishment/suggestions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Saran pembelian berdasarkan rata-rata barang keluar harian, stok akhir, lead time supplier dan safety stock. Hanya barang yang stoknya sudah mencapai reorder point yang ditampilkan, kecuali all=true",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Replenishment"
+                ],
+                "summary": "Purchase suggestions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Periode rata-rata pemakaian dalam hari (default REPLENISHMENT_WINDOW_DAYS)",
+                        "name": "window_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Safety stock dalam hari pemakaian (default REPLENISHMENT_SAFETY_DAYS)",
+                        "name": "safety_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hari pemakaian yang ditutup satu kali pesan (default REPLENISHMENT_COVER_DAYS)",
+                        "name": "cover_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hanya barang dari supplier ini",
+                        "name": "supplier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tampilkan semua barang termasuk yang belum perlu dipesan",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReplenishmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/abc": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Klasifikasi barang A/B/C berdasarkan kontribusi nilai penjualan dalam rentang tanggal. Barang masuk kelas A selama persentase kumulatif sebelum barang tersebut di bawah batas_a, kelas B di bawah batas_b, sisanya (termasuk barang tanpa penjualan) kelas C",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "ABC analysis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD (default awal bulan ini)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Batas kumulatif kelas A dalam persen (default 80)",
+                        "name": "batas_a",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Batas kumulatif kelas B dalam persen (default 95)",
+                        "name": "batas_b",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Total per kategori, brand atau atribut (di field groups)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "group_by kategori: level kategori (default 1 = kategori utama)",
+                        "name": "kategori_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "group_by atribut: nama atribut",
+                        "name": "atribut_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ABCReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/aging": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Umur stok berdasarkan hari sejak pembelian terakhir per barang, dikelompokkan ke bucket 0-30, 31-60, 61-90, 91-180, \u003e180 hari dan tanpa_pembelian",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Stock aging report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Total per kategori, brand atau atribut (di field groups)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "group_by kategori: level kategori (default 1 = kategori utama)",
+                        "name": "kategori_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "group_by atribut: nama atribut",
+                        "name": "atribut_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockAgingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/dead-stock": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Barang yang masih memiliki stok tetapi tidak ada pergerakan \"keluar\" di history stok selama N hari terakhir",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Dead stock report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Jumlah hari tanpa barang keluar (default 90)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Total per kategori, brand atau atribut (di field groups)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "group_by kategori: level kategori (default 1 = kategori utama)",
+                        "name": "kategori_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "group_by atribut: nama atribut",
+                        "name": "atribut_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeadStockResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/margin": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Penjualan, HPP, margin dan persentase margin per barang, customer, faktur penjualan, kategori, brand atau atribut barang dalam rentang tanggal",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Gross margin report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "barang (default), customer, invoice, kategori, brand, atribut",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD (default awal bulan ini)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter barang",
+                        "name": "barang_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter customer (sebagian nama)",
+                        "name": "customer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "group_by kategori: level kategori (default 1 = kategori utama)",
+                        "name": "kategori_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "group_by atribut: nama atribut",
+                        "name": "atribut_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MarginReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/pembelian": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Total pembelian (jumlah transaksi, qty, nilai) per periode, barang, supplier, user, kategori, brand atau atribut barang dalam rentang tanggal",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Purchase report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "day, week, month (default), barang, supplier, user, kategori, brand, atribut",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD (default awal bulan ini)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter barang",
+                        "name": "barang_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter supplier (sebagian nama)",
+                        "name": "supplier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "group_by kategori: level kategori (default 1 = kategori utama)",
+                        "name": "kategori_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "group_by atribut: nama atribut",
+                        "name": "atribut_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/penjualan": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Total penjualan (jumlah transaksi, qty, nilai) per periode, barang, customer, user, kategori, brand atau atribut barang dalam rentang tanggal",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Sales report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "day, week, month (default), barang, customer, user, kategori, brand, atribut",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD (default awal bulan ini)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter barang",
+                        "name": "barang_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter customer (sebagian nama)",
+                        "name": "customer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "group_by kategori: level kategori (default 1 = kategori utama)",
+                        "name": "kategori_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "group_by atribut: nama atribut",
+                        "name": "atribut_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/turnover": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "HPP (COGS), rata-rata stok tertimbang waktu dari history stok, turnover ratio dan days of supply per barang dalam rentang tanggal",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Inventory turnover report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD (default awal bulan ini)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter barang",
+                        "name": "barang_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "turnover (default), days_of_supply, hpp, rata_rata_stok, kode_barang",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "desc (default) atau asc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Turnover per kategori, brand atau atribut (di field groups)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "group_by kategori: level kategori (default 1 = kategori utama)",
+                        "name": "kategori_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "group_by atribut: nama atribut",
+                        "name": "atribut_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TurnoverReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stok": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a list of all stock items. Dengan as_of, stok direkonstruksi dari snapshot dan history stok pada waktu tersebut. Dengan group_by, setiap baris mendapat group_key/group_label dan response berisi total stok dan nilai stok (harga beli) per kelompok di field groups",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Stok"
+                ],
+                "summary": "Get all stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tanggal YYYY-MM-DD (stok akhir hari itu) atau waktu RFC3339",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv atau xlsx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Total per kategori, brand atau atribut (di field groups)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "group_by kategori: level kategori (default 1 = kategori utama)",
+                        "name": "kategori_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "group_by atribut: nama atribut",
+                        "name": "atribut_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MstokResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stok/alerts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Daftar stock alert yang dibuat ketika penjualan atau adjustment membuat stok turun melewati titik reorder",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stok"
+                ],
+                "summary": "Get stock alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "open (default) atau all",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Total per kategori, brand atau atribut (di field groups)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "group_by kategori: level kategori (default 1 = kategori utama)",
+                        "name": "kategori_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "group_by atribut: nama atribut",
+                        "name": "atribut_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockAlertResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stok/alerts/{id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Menandai stock alert sebagai sudah ditangani (Admin only). Alert juga otomatis ditutup ketika stok kembali di atas titik reorder",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stok"
+                ],
+                "summary": "Resolve stock alert",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock Alert ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stok/low": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Daftar barang dengan stok di bawah atau sama dengan titik reorder (stok_minimum, atau LOW_STOCK_THRESHOLD jika belum diset) beserta saran jumlah pesan. Dengan group_by, response berisi jumlah barang dan saran pesan per kelompok di field groups",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stok"
+                ],
+                "summary": "Get low stock items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "kategori_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter brand",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)",
+                        "name": "atribut",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Total per kategori, brand atau atribut (di field groups)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "group_by kategori: level kategori (default 1 = kategori utama)",
+                        "name": "kategori_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "group_by atribut: nama atribut",
+                        "name": "atribut_key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LowStockResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stok/reconcile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Rekonsiliasi ledger tanpa perubahan data (Admin only): chain break (stok_sebelum tidak sama dengan stok_sesudah baris sebelumnya), row mismatch (stok_sesudah tidak sama dengan stok_sebelum +/- jumlah) dan drift (stok_akhir tidak sama dengan history terakhir)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stok"
+                ],
+                "summary": "Check stock ledger",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hanya barang ini",
+                        "name": "barang_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerReconcileResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Rekonsiliasi ledger dan membuat history \"adjustment\" sebagai koreksi untuk chain break dan drift (Admin only). Row mismatch hanya dilaporkan",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stok"
+                ],
+                "summary": "Repair stock ledger",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hanya barang ini",
+                        "name": "barang_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerReconcileResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stok/snapshots": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Menyimpan snapshot stok seluruh barang sekarang (Admin only). Snapshot juga dibuat otomatis setiap STOCK_SNAPSHOT_INTERVAL_HOURS dan dipakai untuk mempercepat query as_of",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stok"
+                ],
+                "summary": "Create stock snapshot",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockSnapshotResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stok/{barang_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get stock details for a specific barang",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stok"
+                ],
+                "summary": "Get stock by barang ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barang ID",
+                        "name": "barang_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal YYYY-MM-DD (stok akhir hari itu) atau waktu RFC3339",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MstokResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stok/{barang_id}/adjustment": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Koreksi stok manual (stock opname). Jumlah positif menambah stok, negatif mengurangi stok. Dicatat di history stok sebagai \"adjustment\" (Admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stok"
+                ],
+                "summary": "Adjust stock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barang ID",
+                        "name": "barang_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stok Adjustment Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StokAdjustmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.HistoryStokResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stok/{barang_id}/kartu": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Kartu stok barang dalam rentang tanggal: saldo awal, setiap pergerakan dengan dokumen pembelian/penjualan dan saldo berjalan, serta saldo akhir. format=csv atau format=pdf untuk unduhan",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Stok"
+                ],
+                "summary": "Stock card (kartu stok)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barang ID",
+                        "name": "barang_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal YYYY-MM-DD (default awal bulan ini)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv atau pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KartuStokResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.SpecificErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stok/{barang_id}/reorder": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Mengatur stok minimum (titik reorder), stok maksimum dan reorder qty untuk barang (Admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stok"
+                ],
+                "summary": "Update reorder level",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barang ID",
+                        "name": "barang_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reorder Level Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReorderLevelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MstokResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ValidationError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "middleware.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "middleware.SpecificErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "middleware.ValidationError": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ABCClassSummary": {
+            "type": "object",
+            "properties": {
+                "jumlah_item": {
+                    "type": "integer"
+                },
+                "kelas": {
+                    "type": "string"
+                },
+                "persen": {
+                    "type": "number"
+                },
+                "total_nilai": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ABCReportResponse": {
+            "type": "object",
+            "properties": {
+                "batas_a": {
+                    "type": "number"
+                },
+                "batas_b": {
+                    "type": "number"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "groups": {
+                    "description": "total_qty dan total_nilai penjualan per kelompok",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockReportGroup"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ABCReportRow"
+                    }
+                },
+                "summary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ABCClassSummary"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ABCReportRow": {
+            "type": "object",
+            "properties": {
+                "barang_id": {
+                    "type": "integer"
+                },
+                "group_key": {
+                    "description": "diisi jika group_by kategori, brand atau atribut",
+                    "type": "string"
+                },
+                "group_label": {
+                    "type": "string"
+                },
+                "kelas": {
+                    "description": "A, B atau C",
+                    "type": "string"
+                },
+                "kode_barang": {
+                    "type": "string"
+                },
+                "nama_barang": {
+                    "type": "string"
+                },
+                "persen": {
+                    "description": "kontribusi terhadap total penjualan",
+                    "type": "number"
+                },
+                "persen_kumulatif": {
+                    "description": "kumulatif setelah barang ini",
+                    "type": "number"
+                },
+                "total_nilai": {
+                    "type": "number"
+                },
+                "total_qty": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.APIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "format RFC3339, opsional",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "description": "opsional, default user admin yang membuat",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserSimpleResponse"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BarangImportResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BarangImportRowResult"
+                    }
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BarangImportRowResult": {
+            "type": "object",
+            "properties": {
+                "aksi": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "kode_barang": {
+                    "type": "string"
+                },
+                "nama_barang": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BarangPembelianResponse": {
+            "type": "object",
+            "properties": {
+                "kode_barang": {
+                    "type": "string"
+                },
+                "nama_barang": {
+                    "type": "string"
+                },
+                "satuan": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BarangPenjualanResponse": {
+            "type": "object",
+            "properties": {
+                "kode_barang": {
+                    "type": "string"
+                },
+                "nama_barang": {
+                    "type": "string"
+                },
+                "satuan": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BarangRequest": {
+            "type": "object",
+            "properties": {
+                "atribut": {
+                    "description": "misal {\"warna\": \"hitam\", \"voltase\": \"220v\"}; nama dan nilai disimpan dalam huruf kecil",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "brand_id": {
+                    "type": "integer"
+                },
+                "deskripsi": {
+                    "type": "string"
+                },
+                "harga_beli": {
+                    "type": "number"
+                },
+                "harga_jual": {
+                    "type": "number"
+                },
+                "kategori_id": {
+                    "type": "integer"
+                },
+                "nama_barang": {
+                    "type": "string"
+                },
+                "satuan": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BarangResponse": {
+            "type": "object",
+            "properties": {
+                "atribut": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "barcodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "brand": {
+                    "type": "string"
+                },
+                "brand_id": {
+                    "type": "integer"
+                },
+                "deskripsi": {
+                    "type": "string"
+                },
+                "harga_beli": {
+                    "type": "number"
+                },
+                "harga_jual": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kategori": {
+                    "type": "string"
+                },
+                "kategori_id": {
+                    "type": "integer"
+                },
+                "kode_barang": {
+                    "type": "string"
+                },
+                "nama_barang": {
+                    "type": "string"
+                },
+                "satuan": {
+                    "type": "string"
+                },
+                "stok": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BarangSimpleResponse": {
+            "type": "object",
+            "properties": {
+                "kode_barang": {
+                    "type": "string"
+                },
+                "nama_barang": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BarangStokResponse": {
+            "type": "object",
+            "properties": {
+                "harga_jual": {
+                    "type": "number"
+                },
+                "kode_barang": {
+                    "type": "string"
+                },
+                "nama_barang": {
+                    "type": "string"
+                },
+                "satuan": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BarcodeRequest": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BeliDetailRequest": {
+            "type": "object",
+            "properties": {
+                "barang_id": {
+                    "type": "integer"
+                },
+                "barcode": {
+                    "description": "alternatif barang_id: barcode atau kode_barang; jika keduanya dikirim harus menunjuk barang yang sama",
+                    "type": "string"
+                },
+                "harga": {
+                    "type": "number"
+                },
+                "qty": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BeliDetailResponse": {
+            "type": "object",
+            "properties": {
+                "barang": {
+                    "$ref": "#/definitions/models.BarangPembelianResponse"
+                },
+                "barang_id": {
+                    "type": "integer"
+                },
+                "harga": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "qty": {
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BeliHeaderRequest": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BeliDetailRequest"
+                    }
+                },
+                "supplier": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BeliHeaderResponse": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "integer"
+                },
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "confirmed_by": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jumlah_item": {
+                    "description": "hanya pada list",
+                    "type": "integer"
+                },
+                "no_faktur": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "supplier": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserSimpleResponse"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Brand": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "nama": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BrandRequest": {
+            "type": "object",
+            "properties": {
+                "nama": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BrandResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "jumlah_barang": {
+                    "type": "integer"
+                },
+                "nama": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BulkHargaFilter": {
+            "type": "object",
+            "properties": {
+                "brand_id": {
+                    "type": "integer"
+                },
+                "kategori_id": {
+                    "description": "termasuk sub kategori",
+                    "type": "integer"
+                },
+                "satuan": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "search": {
+                    "type": "string"
+                },
+                "stock_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BulkHargaRequest": {
+            "type": "object",
+            "properties": {
+                "arah_pembulatan": {
+                    "description": "terdekat (default), atas atau bawah",
+                    "type": "string"
+                },
+                "barang_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "filter": {
+                    "$ref": "#/definitions/models.BulkHargaFilter"
+                },
+                "jenis": {
+                    "description": "persen atau nominal",
+                    "type": "string"
+                },
+                "keterangan": {
+                    "type": "string"
+                },
+                "nilai": {
+                    "description": "persen (8 = naik 8%) atau nominal (boleh negatif)",
+                    "type": "number"
+                },
+                "pembulatan": {
+                    "description": "kelipatan pembulatan, mis. 100; 0 = 2 desimal",
+                    "type": "number"
+                },
+                "supplier": {
+                    "description": "barang yang pernah dibeli dari supplier ini",
+                    "type": "string"
+                },
+                "target": {
+                    "description": "harga_beli, harga_jual atau keduanya",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BulkHargaResponse": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "jumlah_barang": {
+                    "type": "integer"
+                },
+                "perubahan": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PerubahanHarga"
+                    }
+                }
+            }
+        },
+        "models.CreatedAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "hanya ditampilkan sekali saat dibuat",
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserSimpleResponse"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreatedBarangResponse": {
+            "type": "object",
+            "properties": {
+                "atribut": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "brand_id": {
+                    "type": "integer"
+                },
+                "deskripsi": {
+                    "type": "string"
+                },
+                "harga_beli": {
+                    "type": "number"
+                },
+                "harga_jual": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kategori_id": {
+                    "type": "integer"
+                },
+                "kode_barang": {
+                    "type": "string"
+                },
+                "nama_barang": {
+                    "type": "string"
+                },
+                "satuan": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DashboardResponse": {
+            "type": "object",
+            "properties": {
+                "generated_at": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "groups": {
+                    "description": "jumlah barang, total stok dan nilai stok per kelompok",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockReportGroup"
+                    }
+                },
+                "history_terbaru": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HistoryStokResponse"
+                    }
+                },
+                "jumlah_stok_rendah": {
+                    "type": "integer"
+                },
+                "pembelian_bulan_ini": {
+                    "$ref": "#/definitions/models.DashboardTransactionTotal"
+                },
+                "pembelian_hari_ini": {
+                    "$ref": "#/definitions/models.DashboardTransactionTotal"
+                },
+                "penjualan_bulan_ini": {
+                    "$ref": "#/definitions/models.DashboardTransactionTotal"
+                },
+                "penjualan_hari_ini": {
+                    "$ref": "#/definitions/models.DashboardTransactionTotal"
+                },
+                "top_barang": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DashboardTopBarang"
+                    }
+                },
+                "total_nilai_stok": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.DashboardTopBarang": {
+            "type": "object",
+            "properties": {
+                "barang_id": {
+                    "type": "integer"
+                },
+                "kode_barang": {
+                    "type": "string"
+                },
+                "nama_barang": {
+                    "type": "string"
+                },
+                "total_nilai": {
+                    "type": "number"
+                },
+                "total_qty": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DashboardTransactionTotal": {
+            "type": "object",
+            "properties": {
+                "jumlah_transaksi": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.DeadStockResponse": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "groups": {
+                    "description": "total_qty dan total_nilai stok per kelompok",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockReportGroup"
+                    }
+                },
+                "jumlah_item": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeadStockRow"
+                    }
+                },
+                "total_nilai_stok": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.DeadStockRow": {
+            "type": "object",
+            "properties": {
+                "barang_id": {
+                    "type": "integer"
+                },
+                "group_key": {
+                    "type": "string"
+                },
+                "group_label": {
+                    "type": "string"
+                },
+                "hari_tanpa_keluar": {
+                    "type": "integer"
+                },
+                "kode_barang": {
+                    "type": "string"
+                },
+                "nama_barang": {
+                    "type": "string"
+                },
+                "nilai_stok": {
+                    "type": "number"
+                },
+                "satuan": {
+                    "type": "string"
+                },
+                "stok_akhir": {
+                    "type": "integer"
+                },
+                "terakhir_keluar": {
+                    "description": "nil jika belum pernah keluar",
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeleteBarangResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeleteBrandResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeleteKategoriResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ForgotPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HargaHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "integer"
+                },
+                "barang": {
+                    "$ref": "#/definitions/models.BarangSimpleResponse"
+                },
+                "barang_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "harga_beli_baru": {
+                    "type": "number"
+                },
+                "harga_beli_lama": {
+                    "type": "number"
+                },
+                "harga_jual_baru": {
+                    "type": "number"
+                },
+                "harga_jual_lama": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "keterangan": {
+                    "type": "string"
+                },
+                "sumber": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserSimpleResponse"
+                }
+            }
+        },
+        "models.HistoryStokResponse": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "integer"
+                },
+                "barang": {
+                    "$ref": "#/definitions/models.BarangSimpleResponse"
+                },
+                "barang_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jenis_transaksi": {
+                    "type": "string"
+                },
+                "jumlah": {
+                    "type": "integer"
+                },
+                "keterangan": {
+                    "type": "string"
+                },
+                "ref_id": {
+                    "type": "integer"
+                },
+                "ref_type": {
+                    "type": "string"
+                },
+                "stok_sebelum": {
+                    "type": "integer"
+                },
+                "stok_sesudah": {
+                    "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserSimpleResponse"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.JualDetailRequest": {
+            "type": "object",
+            "properties": {
+                "barang_id": {
+                    "type": "integer"
+                },
+                "barcode": {
+                    "description": "alternatif barang_id: barcode atau kode_barang; jika keduanya dikirim harus menunjuk barang yang sama",
+                    "type": "string"
+                },
+                "harga": {
+                    "type": "number"
+                },
+                "qty": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.JualDetailResponse": {
+            "type": "object",
+            "properties": {
+                "barang": {
+                    "$ref": "#/definitions/models.BarangPenjualanResponse"
+                },
+                "barang_id": {
+                    "type": "integer"
+                },
+                "harga": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "qty": {
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.JualHeaderRequest": {
+            "type": "object",
+            "properties": {
+                "customer": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JualDetailRequest"
+                    }
+                }
+            }
+        },
+        "models.JualHeaderResponse": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jumlah_item": {
+                    "description": "hanya pada list",
+                    "type": "integer"
+                },
+                "no_faktur": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserSimpleResponse"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.KartuStokResponse": {
+            "type": "object",
+            "properties": {
+                "barang": {
+                    "$ref": "#/definitions/models.BarangStokResponse"
+                },
+                "barang_id": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "mutasi": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KartuStokRow"
+                    }
+                },
+                "saldo_akhir": {
+                    "type": "integer"
+                },
+                "saldo_awal": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total_keluar": {
+                    "type": "integer"
+                },
+                "total_masuk": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.KartuStokRow": {
+            "type": "object",
+            "properties": {
+                "dokumen_id": {
+                    "type": "integer"
+                },
+                "history_id": {
+                    "type": "integer"
+                },
+                "jenis_dokumen": {
+                    "description": "pembelian / penjualan, kosong untuk adjustment",
+                    "type": "string"
+                },
+                "jenis_transaksi": {
+                    "type": "string"
+                },
+                "keluar": {
+                    "type": "integer"
+                },
+                "keterangan": {
+                    "type": "string"
+                },
+                "masuk": {
+                    "type": "integer"
+                },
+                "no_dokumen": {
+                    "type": "string"
+                },
+                "pihak": {
+                    "description": "supplier / customer",
+                    "type": "string"
+                },
+                "saldo": {
+                    "type": "integer"
+                },
+                "tanggal": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KategoriRequest": {
+            "type": "object",
+            "properties": {
+                "nama": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "kosong = kategori utama",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.KategoriResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "jumlah_barang": {
+                    "type": "integer"
+                },
+                "level": {
+                    "description": "1 = kategori utama",
+                    "type": "integer"
+                },
+                "nama": {
+                    "type": "string"
+                },
+                "nama_lengkap": {
+                    "description": "misal \"Elektronik \u003e Peripherals \u003e Mouse\"",
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LabelTemplate": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "integer"
+                },
+                "dpi": {
+                    "description": "zpl: 203 atau 300",
+                    "type": "integer"
+                },
+                "font_size": {
+                    "description": "pt",
+                    "type": "number"
+                },
+                "format": {
+                    "description": "pdf atau zpl",
+                    "type": "string"
+                },
+                "gap_x": {
+                    "type": "number"
+                },
+                "gap_y": {
+                    "type": "number"
+                },
+                "label_height": {
+                    "type": "number"
+                },
+                "label_width": {
+                    "type": "number"
+                },
+                "margin_left": {
+                    "type": "number"
+                },
+                "margin_top": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "page_height": {
+                    "type": "number"
+                },
+                "page_width": {
+                    "type": "number"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "show_name": {
+                    "type": "boolean"
+                },
+                "show_price": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.LedgerIssue": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expected": {
+                    "type": "integer"
+                },
+                "fix_history_id": {
+                    "description": "history adjustment yang dibuat sebagai koreksi",
+                    "type": "integer"
+                },
+                "fixed": {
+                    "type": "boolean"
+                },
+                "history_id": {
+                    "type": "integer"
+                },
+                "keterangan": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LedgerReconcileResponse": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "fix": {
+                    "type": "boolean"
+                },
+                "jumlah_barang": {
+                    "description": "barang yang bermasalah",
+                    "type": "integer"
+                },
+                "jumlah_fixed": {
+                    "type": "integer"
+                },
+                "jumlah_issue": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LedgerReconcileResult"
+                    }
+                }
+            }
+        },
+        "models.LedgerReconcileResult": {
+            "type": "object",
+            "properties": {
+                "barang_id": {
+                    "type": "integer"
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LedgerIssue"
+                    }
+                },
+                "kode_barang": {
+                    "type": "string"
+                },
+                "nama_barang": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginAudit": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "\"success\", \"invalid_credentials\", \"invalid_2fa\", \"locked\", \"ip_blocked\", \"too_fast\"",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "setup_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "two_factor_required": {
+                    "description": "Diisi jika akun memakai 2FA: kirim ChallengeToken sebagai Bearer token + kode ke /api/auth/login/2fa",
+                    "type": "boolean"
+                },
+                "two_factor_setup_required": {
+                    "description": "Diisi jika 2FA wajib tetapi belum diaktifkan: SetupToken hanya berlaku untuk endpoint /api/auth/2fa",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.LowStockResponse": {
+            "type": "object",
+            "properties": {
+                "barang": {
+                    "$ref": "#/definitions/models.BarangStokResponse"
+                },
+                "barang_id": {
+                    "type": "integer"
+                },
+                "group_key": {
+                    "type": "string"
+                },
+                "group_label": {
+                    "type": "string"
+                },
+                "reorder_point": {
+                    "type": "integer"
+                },
+                "reorder_qty": {
+                    "type": "integer"
+                },
+                "saran_pesan": {
+                    "description": "jumlah yang disarankan untuk dipesan",
+                    "type": "integer"
+                },
+                "stok_akhir": {
+                    "type": "integer"
+                },
+                "stok_maksimum": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MarginReportResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MarginReportRow"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.MarginReportSummary"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MarginReportRow": {
+            "type": "object",
+            "properties": {
+                "hpp": {
+                    "type": "number"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "margin": {
+                    "type": "number"
+                },
+                "margin_persen": {
+                    "type": "number"
+                },
+                "penjualan": {
+                    "type": "number"
+                },
+                "total_qty": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MarginReportSummary": {
+            "type": "object",
+            "properties": {
+                "hpp": {
+                    "type": "number"
+                },
+                "margin": {
+                    "type": "number"
+                },
+                "margin_persen": {
+                    "type": "number"
+                },
+                "penjualan": {
+                    "type": "number"
+                },
+                "total_qty": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MstokResponse": {
+            "type": "object",
+            "properties": {
+                "barang": {
+                    "$ref": "#/definitions/models.BarangStokResponse"
+                },
+                "barang_id": {
+                    "type": "integer"
+                },
+                "group_key": {
+                    "type": "string"
+                },
+                "group_label": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reorder_qty": {
+                    "type": "integer"
+                },
+                "stok_akhir": {
+                    "type": "integer"
+                },
+                "stok_maksimum": {
+                    "type": "integer"
+                },
+                "stok_minimum": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PasswordResetResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
-                },
-                "status": {
-                    "type": "string"
                 }
             }
         },
-        "middleware.SpecificErrorResponse": {
+        "models.PembelianResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BeliDetailResponse"
                     }
+                },
+                "header": {
+                    "$ref": "#/definitions/models.BeliHeaderResponse"
                 }
             }
         },
-        "middleware.ValidationError": {
+        "models.PenjualanResponse": {
             "type": "object",
             "properties": {
-                "errors": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JualDetailResponse"
                     }
                 },
-                "message": {
-                    "type": "string"
+                "header": {
+                    "$ref": "#/definitions/models.JualHeaderResponse"
                 }
             }
         },
-        "models.BarangPembelianResponse": {
+        "models.PerubahanHarga": {
             "type": "object",
             "properties": {
+                "barang_id": {
+                    "type": "integer"
+                },
+                "harga_beli_baru": {
+                    "type": "number"
+                },
+                "harga_beli_lama": {
+                    "type": "number"
+                },
+                "harga_jual_baru": {
+                    "type": "number"
+                },
+                "harga_jual_lama": {
+                    "type": "number"
+                },
                 "kode_barang": {
                     "type": "string"
                 },
                 "nama_barang": {
                     "type": "string"
+                }
+            }
+        },
+        "models.RegisterRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
                 },
-                "satuan": {
+                "full_name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "models.BarangPenjualanResponse": {
+        "models.RegisterResponse": {
             "type": "object",
             "properties": {
-                "kode_barang": {
+                "email": {
                     "type": "string"
                 },
-                "nama_barang": {
+                "full_name": {
                     "type": "string"
                 },
-                "satuan": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "models.BarangRequest": {
+        "models.ReorderLevelRequest": {
             "type": "object",
             "properties": {
-                "deskripsi": {
-                    "type": "string"
+                "reorder_qty": {
+                    "type": "integer"
                 },
-                "harga_beli": {
-                    "type": "number"
+                "stok_maksimum": {
+                    "type": "integer"
                 },
-                "harga_jual": {
-                    "type": "number"
+                "stok_minimum": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReplenishmentDraftRequest": {
+            "type": "object",
+            "properties": {
+                "barang_ids": {
+                    "description": "opsional, hanya barang tertentu",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "nama_barang": {
+                "cover_days": {
+                    "type": "integer"
+                },
+                "default_supplier": {
+                    "description": "supplier untuk barang yang belum pernah dibeli",
                     "type": "string"
                 },
-                "satuan": {
+                "safety_days": {
+                    "type": "integer"
+                },
+                "supplier": {
+                    "description": "hanya saran untuk supplier ini",
                     "type": "string"
+                },
+                "window_days": {
+                    "type": "integer"
                 }
             }
         },
-        "models.BarangResponse": {
+        "models.ReplenishmentDraftResponse": {
             "type": "object",
             "properties": {
-                "deskripsi": {
-                    "type": "string"
+                "drafts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PembelianResponse"
+                    }
                 },
-                "harga_beli": {
-                    "type": "number"
+                "skipped": {
+                    "description": "saran tanpa supplier (isi default_supplier)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReplenishmentSuggestion"
+                    }
+                }
+            }
+        },
+        "models.ReplenishmentParams": {
+            "type": "object",
+            "properties": {
+                "cover_days": {
+                    "description": "jumlah hari pemakaian yang ditutup oleh satu kali pesan",
+                    "type": "integer"
                 },
-                "harga_jual": {
-                    "type": "number"
+                "default_lead_time": {
+                    "description": "lead time untuk supplier yang belum diset",
+                    "type": "integer"
                 },
-                "id": {
+                "safety_days": {
+                    "description": "safety stock dalam hari pemakaian",
+                    "type": "integer"
+                },
+                "supplier": {
+                    "type": "string"
+                },
+                "window_days": {
+                    "description": "periode perhitungan rata-rata pemakaian harian",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReplenishmentResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReplenishmentSuggestion"
+                    }
+                },
+                "params": {
+                    "$ref": "#/definitions/models.ReplenishmentParams"
+                }
+            }
+        },
+        "models.ReplenishmentSuggestion": {
+            "type": "object",
+            "properties": {
+                "barang_id": {
                     "type": "integer"
                 },
+                "harga_beli": {
+                    "type": "number"
+                },
                 "kode_barang": {
                     "type": "string"
                 },
+                "lead_time_days": {
+                    "type": "integer"
+                },
                 "nama_barang": {
                     "type": "string"
                 },
+                "rata_rata_harian": {
+                    "type": "number"
+                },
+                "reorder_point": {
+                    "type": "integer"
+                },
+                "safety_stock": {
+                    "type": "integer"
+                },
+                "saran_qty": {
+                    "type": "integer"
+                },
                 "satuan": {
                     "type": "string"
                 },
-                "stok": {
+                "stok_akhir": {
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "supplier": {
+                    "type": "string"
+                },
+                "target_stok": {
+                    "type": "integer"
+                },
+                "total_keluar": {
                     "type": "integer"
                 }
             }
         },
-        "models.BarangSimpleResponse": {
+        "models.ResetPasswordRequest": {
             "type": "object",
             "properties": {
-                "kode_barang": {
+                "new_password": {
                     "type": "string"
                 },
-                "nama_barang": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "models.BarangStokResponse": {
+        "models.StockAgingBucket": {
             "type": "object",
             "properties": {
-                "harga_jual": {
+                "bucket": {
+                    "type": "string"
+                },
+                "jumlah_item": {
+                    "type": "integer"
+                },
+                "nilai_stok": {
                     "type": "number"
                 },
+                "total_qty": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockAgingResponse": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockAgingBucket"
+                    }
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "groups": {
+                    "description": "total_qty dan total_nilai stok per kelompok",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockReportGroup"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockAgingRow"
+                    }
+                }
+            }
+        },
+        "models.StockAgingRow": {
+            "type": "object",
+            "properties": {
+                "barang_id": {
+                    "type": "integer"
+                },
+                "bucket": {
+                    "type": "string"
+                },
+                "group_key": {
+                    "type": "string"
+                },
+                "group_label": {
+                    "type": "string"
+                },
                 "kode_barang": {
                     "type": "string"
                 },
                 "nama_barang": {
                     "type": "string"
                 },
+                "nilai_stok": {
+                    "type": "number"
+                },
+                "penerimaan_terakhir": {
+                    "description": "pembelian terakhir, nil jika belum pernah dibeli",
+                    "type": "string"
+                },
                 "satuan": {
                     "type": "string"
+                },
+                "stok_akhir": {
+                    "type": "integer"
+                },
+                "umur_hari": {
+                    "type": "integer"
                 }
             }
         },
-        "models.BeliDetailRequest": {
+        "models.StockAlertResponse": {
             "type": "object",
             "properties": {
+                "barang": {
+                    "$ref": "#/definitions/models.BarangSimpleResponse"
+                },
                 "barang_id": {
                     "type": "integer"
                 },
-                "harga": {
-                    "type": "number"
+                "created_at": {
+                    "type": "string"
+                },
+                "group_key": {
+                    "type": "string"
+                },
+                "group_label": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "keterangan": {
+                    "type": "string"
+                },
+                "reorder_point": {
+                    "type": "integer"
+                },
+                "resolved_at": {
+                    "type": "string"
                 },
-                "qty": {
+                "stok_sebelum": {
+                    "type": "integer"
+                },
+                "stok_sesudah": {
                     "type": "integer"
                 }
             }
         },
-        "models.BeliDetailResponse": {
+        "models.StockReportGroup": {
             "type": "object",
             "properties": {
-                "barang": {
-                    "$ref": "#/definitions/models.BarangPembelianResponse"
-                },
-                "barang_id": {
+                "jumlah_item": {
                     "type": "integer"
                 },
-                "harga": {
-                    "type": "number"
-                },
-                "id": {
-                    "type": "integer"
+                "key": {
+                    "type": "string"
                 },
-                "qty": {
-                    "type": "integer"
+                "label": {
+                    "type": "string"
                 },
-                "subtotal": {
+                "total_nilai": {
                     "type": "number"
+                },
+                "total_qty": {
+                    "type": "integer"
                 }
             }
         },
-        "models.BeliHeaderRequest": {
+        "models.StockSnapshotResponse": {
             "type": "object",
             "properties": {
-                "details": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.BeliDetailRequest"
-                    }
+                "jumlah_item": {
+                    "type": "integer"
                 },
-                "supplier": {
+                "snapshot_at": {
                     "type": "string"
                 }
             }
         },
-        "models.BeliHeaderResponse": {
+        "models.StokAdjustmentRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
+                "jumlah": {
+                    "description": "positif menambah stok, negatif mengurangi stok",
                     "type": "integer"
                 },
-                "no_faktur": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "supplier": {
+                "keterangan": {
                     "type": "string"
-                },
-                "total": {
-                    "type": "number"
-                },
-                "user": {
-                    "$ref": "#/definitions/models.UserSimpleResponse"
-                },
-                "user_id": {
-                    "type": "integer"
                 }
             }
         },
-        "models.CreatedBarangResponse": {
+        "models.SupplierLeadTime": {
             "type": "object",
             "properties": {
-                "deskripsi": {
-                    "type": "string"
-                },
-                "harga_beli": {
-                    "type": "number"
-                },
-                "harga_jual": {
-                    "type": "number"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "kode_barang": {
-                    "type": "string"
+                "lead_time_days": {
+                    "type": "integer"
                 },
-                "nama_barang": {
+                "supplier": {
                     "type": "string"
                 },
-                "satuan": {
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.DeleteBarangResponse": {
+        "models.SupplierLeadTimeRequest": {
             "type": "object",
             "properties": {
-                "message": {
+                "lead_time_days": {
+                    "type": "integer"
+                },
+                "supplier": {
                     "type": "string"
                 }
             }
         },
-        "models.HistoryStokResponse": {
+        "models.TransactionReportResponse": {
             "type": "object",
             "properties": {
-                "barang": {
-                    "$ref": "#/definitions/models.BarangSimpleResponse"
-                },
-                "barang_id": {
-                    "type": "integer"
+                "from": {
+                    "type": "string"
                 },
-                "created_at": {
+                "group_by": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionReportRow"
+                    }
                 },
-                "jenis_transaksi": {
-                    "type": "string"
+                "summary": {
+                    "$ref": "#/definitions/models.TransactionReportSummary"
                 },
-                "jumlah": {
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransactionReportRow": {
+            "type": "object",
+            "properties": {
+                "jumlah_transaksi": {
                     "type": "integer"
                 },
-                "keterangan": {
+                "key": {
                     "type": "string"
                 },
-                "stok_sebelum": {
-                    "type": "integer"
-                },
-                "stok_sesudah": {
-                    "type": "integer"
+                "label": {
+                    "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/models.UserSimpleResponse"
+                "total_nilai": {
+                    "type": "number"
                 },
-                "user_id": {
+                "total_qty": {
                     "type": "integer"
                 }
             }
         },
-        "models.JualDetailRequest": {
+        "models.TransactionReportSummary": {
             "type": "object",
             "properties": {
-                "barang_id": {
+                "jumlah_transaksi": {
                     "type": "integer"
                 },
-                "harga": {
+                "total_nilai": {
                     "type": "number"
                 },
-                "qty": {
+                "total_qty": {
                     "type": "integer"
                 }
             }
         },
-        "models.JualDetailResponse": {
+        "models.TurnoverReportGroup": {
             "type": "object",
             "properties": {
-                "barang": {
-                    "$ref": "#/definitions/models.BarangPenjualanResponse"
-                },
-                "barang_id": {
-                    "type": "integer"
+                "days_of_supply": {
+                    "type": "number"
                 },
-                "harga": {
+                "hpp": {
                     "type": "number"
                 },
-                "id": {
+                "jumlah_item": {
                     "type": "integer"
                 },
-                "qty": {
-                    "type": "integer"
+                "key": {
+                    "type": "string"
                 },
-                "subtotal": {
+                "label": {
+                    "type": "string"
+                },
+                "rata_rata_nilai_stok": {
+                    "type": "number"
+                },
+                "turnover": {
                     "type": "number"
                 }
             }
         },
-        "models.JualHeaderRequest": {
+        "models.TurnoverReportResponse": {
             "type": "object",
             "properties": {
-                "customer": {
+                "days": {
+                    "type": "number"
+                },
+                "from": {
                     "type": "string"
                 },
-                "details": {
+                "group_by": {
+                    "type": "string"
+                },
+                "groups": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.JualDetailRequest"
+                        "$ref": "#/definitions/models.TurnoverReportGroup"
+                    }
+                },
+                "order": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TurnoverReportRow"
                     }
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.TurnoverReportSummary"
+                },
+                "to": {
+                    "type": "string"
                 }
             }
         },
-        "models.JualHeaderResponse": {
+        "models.TurnoverReportRow": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "barang_id": {
+                    "type": "integer"
+                },
+                "days_of_supply": {
+                    "description": "hari periode / turnover, nil jika tidak ada penjualan",
+                    "type": "number"
+                },
+                "group_key": {
                     "type": "string"
                 },
-                "customer": {
+                "group_label": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "hpp": {
+                    "description": "COGS dalam periode",
+                    "type": "number"
                 },
-                "no_faktur": {
+                "kode_barang": {
                     "type": "string"
                 },
-                "status": {
+                "nama_barang": {
                     "type": "string"
                 },
-                "total": {
+                "qty_terjual": {
+                    "type": "integer"
+                },
+                "rata_rata_nilai_stok": {
+                    "description": "rata_rata_stok x harga_beli",
                     "type": "number"
                 },
-                "user": {
-                    "$ref": "#/definitions/models.UserSimpleResponse"
+                "rata_rata_stok": {
+                    "description": "rata-rata qty stok tertimbang waktu",
+                    "type": "number"
                 },
-                "user_id": {
-                    "type": "integer"
+                "satuan": {
+                    "type": "string"
+                },
+                "turnover": {
+                    "description": "hpp / rata_rata_nilai_stok, nil jika tidak ada stok",
+                    "type": "number"
                 }
             }
         },
-        "models.LoginRequest": {
+        "models.TurnoverReportSummary": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "days_of_supply": {
+                    "type": "number"
                 },
-                "password": {
-                    "type": "string"
+                "hpp": {
+                    "type": "number"
+                },
+                "rata_rata_nilai_stok": {
+                    "type": "number"
+                },
+                "turnover": {
+                    "type": "number"
                 }
             }
         },
-        "models.LoginResponse": {
+        "models.TwoFactorActivateResponse": {
             "type": "object",
             "properties": {
-                "token": {
+                "backup_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "models.MstokResponse": {
+        "models.TwoFactorCodeRequest": {
             "type": "object",
             "properties": {
-                "barang": {
-                    "$ref": "#/definitions/models.BarangStokResponse"
-                },
-                "barang_id": {
-                    "type": "integer"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "stok_akhir": {
-                    "type": "integer"
-                },
-                "updated_at": {
+                "code": {
+                    "description": "kode TOTP 6 digit (atau backup code saat login)",
                     "type": "string"
                 }
             }
         },
-        "models.PembelianResponse": {
+        "models.TwoFactorEnrollResponse": {
             "type": "object",
             "properties": {
-                "details": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.BeliDetailResponse"
-                    }
+                "otpauth_uri": {
+                    "type": "string"
                 },
-                "header": {
-                    "$ref": "#/definitions/models.BeliHeaderResponse"
+                "secret": {
+                    "type": "string"
                 }
             }
         },
-        "models.PenjualanResponse": {
+        "models.TwoFactorMessageResponse": {
             "type": "object",
             "properties": {
-                "details": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.JualDetailResponse"
-                    }
-                },
-                "header": {
-                    "$ref": "#/definitions/models.JualHeaderResponse"
+                "message": {
+                    "type": "string"
                 }
             }
         },
-        "models.RegisterRequest": {
+        "models.TwoFactorRequirementRequest": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "full_name": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                },
-                "username": {
-                    "type": "string"
+                "required": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.RegisterResponse": {
+        "models.UnlockUserResponse": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "full_name": {
-                    "type": "string"
-                },
-                "username": {
+                "message": {
                     "type": "string"
                 }
             }
@@ -1349,6 +7006,11 @@ const docTemplate = `{
         }
     },
     "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
         "BearerAuth": {
             "type": "apiKey",
             "name": "Authorization",
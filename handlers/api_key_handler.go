@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/repositories"
+	"warehouse-inventory-server/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type APIKeyHandler struct {
+	repo     *repositories.APIKeyRepository
+	userRepo *repositories.UserRepository
+}
+
+func NewAPIKeyHandler(repo *repositories.APIKeyRepository, userRepo *repositories.UserRepository) *APIKeyHandler {
+	return &APIKeyHandler{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// RegisterRoute mendaftarkan seluruh endpoint "/api/api-keys" (admin only, JWT only)
+func (h *APIKeyHandler) RegisterRoute(r fiber.Router) {
+	r.Get("/", h.GetAllAPIKey)
+	r.Post("/", h.CreateAPIKey)
+	r.Delete("/:id", h.RevokeAPIKey)
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key (Admin only)
+// @Description Membuat API key untuk integrasi machine-to-machine. Key lengkap hanya ditampilkan sekali
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Param body body models.APIKeyRequest true "API Key Request"
+// @Success 201 {object} models.CreatedAPIKeyResponse "Created"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Router /api/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	var req models.APIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	errMap := make(map[string]string)
+
+	if strings.TrimSpace(req.Name) == "" {
+		errMap["name"] = "nama API key tidak boleh kosong"
+	}
+
+	if len(req.Scopes) == 0 {
+		errMap["scopes"] = "scopes tidak boleh kosong"
+	} else {
+		for _, scope := range req.Scopes {
+			if !slices.Contains(models.APIKeyScopes, scope) {
+				errMap["scopes"] = "scope tidak dikenal: " + scope + ". Pilihan: " + strings.Join(models.APIKeyScopes, ", ")
+				break
+			}
+		}
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil && *req.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, *req.ExpiresAt)
+		if err != nil {
+			errMap["expires_at"] = "format expires_at harus RFC3339, misal 2026-12-31T23:59:59Z"
+		} else if t.Before(time.Now()) {
+			errMap["expires_at"] = "expires_at harus di masa depan"
+		} else {
+			expiresAt = &t
+		}
+	}
+
+	creatorID := claimsUserID(c)
+	userID := req.UserID
+	if userID == 0 {
+		userID = creatorID
+	}
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil {
+		errMap["user_id"] = "user tidak ditemukan"
+	} else if user.Role != "admin" && slices.Contains(req.Scopes, "admin") {
+		errMap["scopes"] = "scope admin hanya untuk API key milik user admin"
+	}
+
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	raw, prefix, hash, err := utils.GenerateAPIKey()
+	if err != nil {
+		log.Println("Error generating API key:", err.Error(), "api_key_handler.go:CreateAPIKey", "Error at line 99")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	key := models.APIKey{
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    strings.Join(slices.Compact(slices.Sorted(slices.Values(req.Scopes))), ","),
+		UserID:    userID,
+		CreatedBy: &creatorID,
+		ExpiresAt: expiresAt,
+	}
+	if err := h.repo.Create(&key); err != nil {
+		log.Println("Error creating API key:", err.Error(), "api_key_handler.go:CreateAPIKey", "Error at line 113")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	created, err := h.repo.GetByID(key.ID)
+	if err != nil {
+		log.Println("Error fetching created API key:", err.Error(), "api_key_handler.go:CreateAPIKey", "Error at line 119")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.CreatedAPIKeyResponse{
+		APIKeyResponse: mapToAPIKeyResponse(created),
+		Key:            raw,
+	})
+}
+
+// GetAllAPIKey godoc
+// @Summary Get all API keys (Admin only)
+// @Description Daftar API key beserta prefix, scope, masa berlaku dan waktu terakhir dipakai
+// @Tags API Key
+// @Produce json
+// @Success 200 {object} models.APIKeyResponse "OK"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Router /api/api-keys [get]
+func (h *APIKeyHandler) GetAllAPIKey(c *fiber.Ctx) error {
+	keys, err := h.repo.List()
+	if err != nil {
+		log.Println("Error fetching API keys:", err.Error(), "api_key_handler.go:GetAllAPIKey", "Error at line 141")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	response := make([]models.APIKeyResponse, len(keys))
+	for i := range keys {
+		response[i] = mapToAPIKeyResponse(&keys[i])
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response,
+	})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key (Admin only)
+// @Description Mencabut API key sehingga tidak bisa dipakai lagi
+// @Tags API Key
+// @Produce json
+// @Param id path int true "API Key ID"
+// @Success 200 {object} models.APIKeyResponse "OK"
+// @Failure 404 {object} middleware.ErrorResponse "Not Found"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Router /api/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	if err := h.repo.Revoke(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "API key tidak ditemukan atau sudah dicabut")
+		}
+		log.Println("Error revoking API key:", err.Error(), "api_key_handler.go:RevokeAPIKey", "Error at line 176")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	key, err := h.repo.GetByID(uint(id))
+	if err != nil {
+		log.Println("Error fetching revoked API key:", err.Error(), "api_key_handler.go:RevokeAPIKey", "Error at line 182")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(mapToAPIKeyResponse(key))
+}
+
+// Private helper functions untuk mapping struct response
+func mapToAPIKeyResponse(k *models.APIKey) models.APIKeyResponse {
+	response := models.APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		UserID:     k.UserID,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+	if k.User != nil {
+		response.User = models.UserSimpleResponse{Username: k.User.Username, FullName: k.User.FullName}
+	}
+	return response
+}
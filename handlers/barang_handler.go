@@ -3,11 +3,14 @@ package handlers
 import (
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
+	"strings"
 
 	"warehouse-inventory-server/middleware"
 	"warehouse-inventory-server/models"
 	"warehouse-inventory-server/repositories"
+	"warehouse-inventory-server/utils"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -22,8 +25,17 @@ func NewBarangHandler(repo *repositories.BarangRepository) *BarangHandler {
 
 func (h *BarangHandler) RegisterRoute(r fiber.Router) {
 	r.Get("/", h.GetBarang)
+	r.Get("/harga-history", h.GetHargaHistory)
+	r.Post("/harga/bulk", middleware.GuardAdmin(), h.BulkUpdateHarga)
+	r.Get("/scan/:barcode", h.ScanBarang)
+	r.Get("/labels", h.GetLabels)
+	r.Get("/labels/templates", h.GetLabelTemplates)
 	r.Get("/:id", h.GetBarangByID)
+	r.Get("/:id/harga-history", h.GetHargaHistoryByBarangID)
+	r.Post("/:id/barcodes", middleware.GuardAdmin(), h.AddBarcode)
+	r.Delete("/:id/barcodes/:barcode", middleware.GuardAdmin(), h.DeleteBarcode)
 	r.Post("/", middleware.GuardAdmin(), h.CreateBarang)
+	r.Post("/import", middleware.GuardAdmin(), h.ImportBarang)
 	r.Put("/:id", middleware.GuardAdmin(), h.UpdateBarangByID)
 	r.Delete("/:id", middleware.GuardAdmin(), h.DeleteBarangByID)
 }
@@ -34,40 +46,67 @@ func (h *BarangHandler) RegisterRoute(r fiber.Router) {
 // @Tags Barang
 // @Accept json
 // @Produce json
-// @Param search query string false "Search by name"
-// @Param page query int false "Page number"
-// @Param limit query int false "Items per page"
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param search query string false "Cari kode, nama dan deskripsi (full-text + toleran salah ketik), hasil diurutkan berdasarkan relevansi"
+// @Param satuan query string false "Filter satuan (pisahkan dengan koma untuk lebih dari satu)"
+// @Param min_harga query number false "Harga jual minimum"
+// @Param max_harga query number false "Harga jual maksimum"
+// @Param stock_status query string false "in_stock, low atau out"
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam,ukuran:xl)"
+// @Param format query string false "json (default), csv atau xlsx. csv/xlsx mengunduh seluruh hasil filter tanpa paginasi"
+// @Param page query int false "Page number (mode page/limit)"
+// @Param limit query int false "Items per page (default 10, max 100)"
+// @Param cursor query string false "Cursor dari meta.next_cursor; kirim kosong (?cursor=) untuk halaman pertama mode cursor"
 // @Success 200 {object} models.BarangResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
 // @Failure 500 {object} middleware.ValidationError "Internal Server Error"
 // @Router /api/barang [get]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *BarangHandler) GetBarang(c *fiber.Ctx) error {
-	search := c.Query("search")
-	page, _ := strconv.Atoi(c.Query("page"))
-	limit, _ := strconv.Atoi(c.Query("limit"))
-	items, total, err := h.repo.List(search, page, limit)
+	page, limit := parsePagination(c)
+	cursor, err := parseCursor(c)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseBarangFilter(c)
+	if err != nil {
+		return err
+	}
+	format, err := parseExportFormat(c)
+	if err != nil {
+		return err
+	}
+	if format != "" {
+		return h.exportBarang(c, format, filter)
+	}
+	filter.Limit = limit + 1
+	filter.Offset = (page - 1) * limit
+	filter.Cursor = cursor
+
+	items, total, err := h.repo.List(filter)
 	if err != nil {
 		log.Println("Error fetching barang list:", err.Error(), "barang_handler.go:GetBarang", "Error at line 48")
 		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
 	}
+	fetched := len(items)
+	items = items[:min(fetched, limit)]
 
-	var response []models.BarangResponse
+	response := []models.BarangResponse{}
 	for _, item := range items {
-		response = append(response, models.BarangResponse{
-			ID:         item.ID,
-			KodeBarang: item.KodeBarang,
-			NamaBarang: item.NamaBarang,
-			Deskripsi:  item.Deskripsi,
-			Satuan:     item.Satuan,
-			HargaBeli:  item.HargaBeli,
-			HargaJual:  item.HargaJual,
-			Stok:       item.StokAkhir,
-		})
+		response = append(response, mapToBarangResponse(&item))
 	}
 
 	return c.Status(200).JSON(fiber.Map{
 		"data": response,
-		"meta": fiber.Map{"total": total, "page": page, "limit": limit},
+		"meta": listMeta(cursor, page, limit, total, fetched, func() string {
+			last := items[len(items)-1]
+			return utils.EncodeCursor(last.KodeBarang, last.ID)
+		}),
 	})
 }
 
@@ -82,6 +121,7 @@ func (h *BarangHandler) GetBarang(c *fiber.Ctx) error {
 // @Failure 404 {object} middleware.SpecificErrorResponse "Not Found"
 // @Router /api/barang/{id} [get]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *BarangHandler) GetBarangByID(c *fiber.Ctx) error {
 	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
@@ -92,15 +132,10 @@ func (h *BarangHandler) GetBarangByID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "Barang tidak Ditemukan")
 	}
 
-	response := models.BarangResponse{
-		ID:         barang.ID,
-		KodeBarang: barang.KodeBarang,
-		NamaBarang: barang.NamaBarang,
-		Deskripsi:  barang.Deskripsi,
-		Satuan:     barang.Satuan,
-		HargaBeli:  barang.HargaBeli,
-		HargaJual:  barang.HargaJual,
-		Stok:       barang.StokAkhir,
+	response, err := h.barangDetailResponse(barang)
+	if err != nil {
+		log.Println("Error fetching barcodes:", err.Error(), "barang_handler.go:GetBarangByID")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
 	}
 
 	return c.Status(200).JSON(response)
@@ -119,6 +154,7 @@ func (h *BarangHandler) GetBarangByID(c *fiber.Ctx) error {
 // @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
 // @Router /api/barang [post]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *BarangHandler) CreateBarang(c *fiber.Ctx) error {
 	var req models.BarangRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -126,24 +162,12 @@ func (h *BarangHandler) CreateBarang(c *fiber.Ctx) error {
 	}
 
 	// Create Barang validations
-	errMap := make(map[string]string)
-
-	if req.NamaBarang == "" {
-		errMap["nama_barang"] = "nama barang tidak boleh kosong"
-	}
-
-	if req.Satuan == "" {
-		errMap["satuan"] = "satuan tidak boleh kosong"
-	}
-
-	if req.HargaBeli < 0 {
-		errMap["harga_beli"] = "harga beli tidak boleh kurang dari 0"
-	}
-
-	if req.HargaJual < 0 {
-		errMap["harga_jual"] = "harga jual tidak boleh kurang dari 0"
+	errMap := validateBarangRequest(req)
+	atribut, err := h.validateKlasifikasi(req, errMap)
+	if err != nil {
+		log.Println("Error validating kategori/brand:", err.Error(), "barang_handler.go:CreateBarang")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
 	}
-
 	if len(errMap) > 0 {
 		return &middleware.ValidationError{
 			Message: "validation error",
@@ -157,6 +181,9 @@ func (h *BarangHandler) CreateBarang(c *fiber.Ctx) error {
 		Satuan:     req.Satuan,
 		HargaBeli:  req.HargaBeli,
 		HargaJual:  req.HargaJual,
+		KategoriID: req.KategoriID,
+		BrandID:    req.BrandID,
+		Atribut:    atribut,
 	}
 
 	if err := h.repo.Create(&barang); err != nil {
@@ -172,6 +199,9 @@ func (h *BarangHandler) CreateBarang(c *fiber.Ctx) error {
 		Satuan:     barang.Satuan,
 		HargaBeli:  barang.HargaBeli,
 		HargaJual:  barang.HargaJual,
+		KategoriID: barang.KategoriID,
+		BrandID:    barang.BrandID,
+		Atribut:    barang.Atribut,
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(response)
@@ -179,7 +209,7 @@ func (h *BarangHandler) CreateBarang(c *fiber.Ctx) error {
 
 // UpdateBarangByID godoc
 // @Summary Update barang by ID
-// @Description Memperbarui detail barang berdasarkan ID
+// @Description Memperbarui detail barang berdasarkan ID. kategori_id, brand_id dan atribut hanya diubah jika dikirim (null untuk mengosongkan)
 // @Tags Barang
 // @Accept json
 // @Produce json
@@ -192,6 +222,7 @@ func (h *BarangHandler) CreateBarang(c *fiber.Ctx) error {
 // @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
 // @Router /api/barang/{id} [put]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *BarangHandler) UpdateBarangByID(c *fiber.Ctx) error {
 	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
@@ -219,6 +250,11 @@ func (h *BarangHandler) UpdateBarangByID(c *fiber.Ctx) error {
 	case req.HargaJual < 0:
 		errMap["harga_jual"] = "harga jual tidak boleh kurang dari 0"
 	}
+	atribut, err := h.validateKlasifikasi(req, errMap)
+	if err != nil {
+		log.Println("Error validating kategori/brand:", err.Error(), "barang_handler.go:UpdateBarangByID")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
 
 	if len(errMap) > 0 {
 		return &middleware.ValidationError{
@@ -232,8 +268,20 @@ func (h *BarangHandler) UpdateBarangByID(c *fiber.Ctx) error {
 	barang.Satuan = req.Satuan
 	barang.HargaBeli = req.HargaBeli
 	barang.HargaJual = req.HargaJual
+	// Klasifikasi hanya diubah jika field-nya dikirim (null untuk mengosongkan), agar client lama yang
+	// belum mengenal kategori/brand/atribut tidak menghapusnya saat mengubah harga
+	fields := jsonBodyFields(c)
+	if _, ok := fields["kategori_id"]; ok {
+		barang.KategoriID = req.KategoriID
+	}
+	if _, ok := fields["brand_id"]; ok {
+		barang.BrandID = req.BrandID
+	}
+	if _, ok := fields["atribut"]; ok {
+		barang.Atribut = atribut
+	}
 
-	if err := h.repo.Update(barang); err != nil {
+	if err := h.repo.Update(barang, claimsUserID(c), claimsAPIKeyID(c)); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
@@ -245,6 +293,9 @@ func (h *BarangHandler) UpdateBarangByID(c *fiber.Ctx) error {
 		Satuan:     barang.Satuan,
 		HargaBeli:  barang.HargaBeli,
 		HargaJual:  barang.HargaJual,
+		KategoriID: barang.KategoriID,
+		BrandID:    barang.BrandID,
+		Atribut:    barang.Atribut,
 	}
 
 	return c.Status(200).JSON(response)
@@ -264,6 +315,7 @@ func (h *BarangHandler) UpdateBarangByID(c *fiber.Ctx) error {
 // @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
 // @Router /api/barang/{id} [delete]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *BarangHandler) DeleteBarangByID(c *fiber.Ctx) error {
 	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
@@ -286,3 +338,133 @@ func (h *BarangHandler) DeleteBarangByID(c *fiber.Ctx) error {
 		Message: message,
 	})
 }
+
+// validateBarangRequest memvalidasi data barang baru (dipakai CreateBarang dan import barang)
+func validateBarangRequest(req models.BarangRequest) map[string]string {
+	errMap := make(map[string]string)
+
+	if req.NamaBarang == "" {
+		errMap["nama_barang"] = "nama barang tidak boleh kosong"
+	}
+
+	if req.Satuan == "" {
+		errMap["satuan"] = "satuan tidak boleh kosong"
+	}
+
+	if req.HargaBeli < 0 {
+		errMap["harga_beli"] = "harga beli tidak boleh kurang dari 0"
+	}
+
+	if req.HargaJual < 0 {
+		errMap["harga_jual"] = "harga jual tidak boleh kurang dari 0"
+	}
+
+	return errMap
+}
+
+// validateKlasifikasi memastikan kategori dan brand yang dipilih ada dan menormalkan atribut barang.
+// Pesan validasi ditambahkan ke errMap.
+func (h *BarangHandler) validateKlasifikasi(req models.BarangRequest, errMap map[string]string) (models.Atribut, error) {
+	if req.KategoriID != nil {
+		ok, err := h.repo.KategoriExists(*req.KategoriID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			errMap["kategori_id"] = "kategori tidak ditemukan"
+		}
+	}
+	if req.BrandID != nil {
+		ok, err := h.repo.BrandExists(*req.BrandID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			errMap["brand_id"] = "brand tidak ditemukan"
+		}
+	}
+	atribut, msg := normalizeAtribut(req.Atribut)
+	if msg != "" {
+		errMap["atribut"] = msg
+	}
+	return atribut, nil
+}
+
+// mapToBarangResponse memetakan barang beserta stok, nama kategori dan brand ke response
+func mapToBarangResponse(b *models.BarangWithStock) models.BarangResponse {
+	return models.BarangResponse{
+		ID:         b.ID,
+		KodeBarang: b.KodeBarang,
+		NamaBarang: b.NamaBarang,
+		Deskripsi:  b.Deskripsi,
+		Satuan:     b.Satuan,
+		HargaBeli:  b.HargaBeli,
+		HargaJual:  b.HargaJual,
+		KategoriID: b.KategoriID,
+		Kategori:   b.NamaKategori,
+		BrandID:    b.BrandID,
+		Brand:      b.NamaBrand,
+		Atribut:    b.Atribut,
+		Stok:       b.StokAkhir,
+	}
+}
+
+// exportBarang mengunduh daftar barang sesuai filter sebagai CSV/XLSX
+func (h *BarangHandler) exportBarang(c *fiber.Ctx, format string, f models.BarangFilter) error {
+	header := []string{"ID", "Kode Barang", "Nama Barang", "Deskripsi", "Satuan", "Kategori", "Brand", "Harga Beli", "Harga Jual", "Stok"}
+	return streamExport(c, format, "barang", header, func(write func(row ...any) error) error {
+		return h.repo.EachBarang(f, func(b models.BarangWithStock) error {
+			return write(b.ID, b.KodeBarang, b.NamaBarang, b.Deskripsi, b.Satuan, b.NamaKategori, b.NamaBrand, b.HargaBeli, b.HargaJual, b.StokAkhir)
+		})
+	})
+}
+
+// parseBarangFilter membaca query search, satuan, min_harga, max_harga, stock_status serta kategori_id, brand_id dan atribut
+func parseBarangFilter(c *fiber.Ctx) (models.BarangFilter, error) {
+	errMap := make(map[string]string)
+	f := models.BarangFilter{
+		Search:            strings.TrimSpace(c.Query("search")),
+		StockStatus:       c.Query("stock_status"),
+		KlasifikasiFilter: parseKlasifikasiFilter(c, errMap),
+	}
+
+	if v := c.Query("satuan"); v != "" {
+		for _, satuan := range strings.Split(v, ",") {
+			if satuan = strings.ToLower(strings.TrimSpace(satuan)); satuan != "" {
+				f.Satuan = append(f.Satuan, satuan)
+			}
+		}
+	}
+
+	for _, key := range []string{"min_harga", "max_harga"} {
+		v := c.Query(key)
+		if v == "" {
+			continue
+		}
+		harga, err := strconv.ParseFloat(v, 64)
+		if err != nil || harga < 0 {
+			errMap[key] = key + " harus angka >= 0"
+			continue
+		}
+		if key == "min_harga" {
+			f.MinHarga = &harga
+		} else {
+			f.MaxHarga = &harga
+		}
+	}
+	if f.MinHarga != nil && f.MaxHarga != nil && *f.MaxHarga < *f.MinHarga {
+		errMap["max_harga"] = "max_harga tidak boleh lebih kecil dari min_harga"
+	}
+
+	if f.StockStatus != "" && !slices.Contains([]string{models.StockStatusInStock, models.StockStatusLow, models.StockStatusOut}, f.StockStatus) {
+		errMap["stock_status"] = "stock_status harus in_stock, low atau out"
+	}
+
+	if len(errMap) > 0 {
+		return f, &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+	return f, nil
+}
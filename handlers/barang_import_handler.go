@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"warehouse-inventory-server/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// Batas jumlah baris data per file import
+const maxImportRows = 5000
+
+// Kolom file import barang; nama header tidak case-sensitive dan spasi dianggap underscore ("Kode Barang" = kode_barang)
+var barangImportColumns = []string{"kode_barang", "nama_barang", "deskripsi", "satuan", "kategori", "brand", "harga_beli", "harga_jual", "stok_awal"}
+
+// ImportBarang godoc
+// @Summary Import barang from CSV/XLSX
+// @Description Membuat atau memperbarui barang secara massal dari file CSV/XLSX (Admin only). Baris dicocokkan berdasarkan kode_barang: kode yang sudah ada diperbarui, kode baru atau kosong dibuat (kode kosong mendapat kode otomatis; kode baru berformat kode otomatis BRGxxx untuk ID yang belum ada ditolak). Saat update, deskripsi/kategori/brand/harga_beli/harga_jual yang kosong atau kolomnya tidak ada tidak mengubah nilai lama. Kolom: kode_barang, nama_barang, deskripsi, satuan, kategori, brand, harga_beli, harga_jual, stok_awal (sama dengan kolom export). kategori dan brand berisi nama yang sudah terdaftar (tidak case-sensitive). stok_awal hanya untuk barang baru dan dicatat sebagai history adjustment. Validasi sama seperti create barang; jika ada baris yang gagal, tidak ada data yang disimpan. dry_run=true hanya mengembalikan laporan validasi
+// @Tags Barang
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "File .csv atau .xlsx (baris pertama header)"
+// @Param dry_run query bool false "Validasi saja tanpa menyimpan"
+// @Success 200 {object} models.BarangImportResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 422 {object} models.BarangImportResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/barang/import [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BarangHandler) ImportBarang(c *fiber.Ctx) error {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "file wajib diunggah")
+	}
+	records, err := readImportFile(fh)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if len(records) < 2 {
+		return fiber.NewError(fiber.StatusBadRequest, "file tidak berisi data")
+	}
+	if len(records)-1 > maxImportRows {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("maksimal %d baris per import", maxImportRows))
+	}
+
+	columns, err := importColumnIndex(records[0])
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	rows, results := parseBarangImportRows(records[1:], columns)
+
+	// Tandai create/update berdasarkan kode_barang yang sudah ada
+	var kodes, kategoriNames, brandNames []string
+	for _, row := range rows {
+		if row.KodeBarang != "" {
+			kodes = append(kodes, row.KodeBarang)
+		}
+		if row.Kategori != "" {
+			kategoriNames = append(kategoriNames, strings.ToLower(row.Kategori))
+		}
+		if row.Brand != "" {
+			brandNames = append(brandNames, strings.ToLower(row.Brand))
+		}
+	}
+	existing, err := h.repo.GetByKodes(kodes)
+	if err != nil {
+		log.Println("Error fetching barang by kode:", err.Error(), "barang_import_handler.go:ImportBarang")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	maxID, err := h.repo.MaxID()
+	if err != nil {
+		log.Println("Error fetching max barang id:", err.Error(), "barang_import_handler.go:ImportBarang")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	kategoriIDs, err := h.repo.KategoriIDsByNama(kategoriNames)
+	if err != nil {
+		log.Println("Error fetching kategori by nama:", err.Error(), "barang_import_handler.go:ImportBarang")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	brandIDs, err := h.repo.BrandIDsByNama(brandNames)
+	if err != nil {
+		log.Println("Error fetching brand by nama:", err.Error(), "barang_import_handler.go:ImportBarang")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	report := models.BarangImportResponse{
+		DryRun:    c.QueryBool("dry_run", false),
+		TotalRows: len(results),
+		Rows:      results,
+	}
+	byRow := make(map[int]*models.BarangImportRowResult, len(report.Rows))
+	for i := range report.Rows {
+		byRow[report.Rows[i].Row] = &report.Rows[i]
+	}
+
+	var valid []models.BarangImportRow
+	for _, row := range rows {
+		result := byRow[row.Row]
+		if row.Kategori != "" {
+			ids := kategoriIDs[strings.ToLower(row.Kategori)]
+			switch len(ids) {
+			case 0:
+				markImportError(result, "kategori", "kategori "+row.Kategori+" tidak ditemukan")
+			case 1:
+				row.Request.KategoriID = &ids[0]
+			default:
+				markImportError(result, "kategori", "nama kategori "+row.Kategori+" dipakai lebih dari satu kategori, ubah namanya agar unik")
+			}
+		}
+		if row.Brand != "" {
+			if id, ok := brandIDs[strings.ToLower(row.Brand)]; ok {
+				row.Request.BrandID = &id
+			} else {
+				markImportError(result, "brand", "brand "+row.Brand+" tidak ditemukan")
+			}
+		}
+		if result.Aksi == models.ImportAksiError {
+			continue
+		}
+
+		_, exists := existing[row.KodeBarang]
+		switch {
+		case exists && row.StokAwal > 0:
+			markImportError(result, "stok_awal", "stok_awal hanya untuk barang baru, gunakan adjustment stok untuk barang yang sudah ada")
+			continue
+		case exists:
+			result.Aksi = models.ImportAksiUpdate
+		case reservedKodeBarang(row.KodeBarang, maxID):
+			markImportError(result, "kode_barang", "kode "+row.KodeBarang+" dicadangkan untuk kode otomatis barang baru, gunakan kode lain atau kosongkan")
+			continue
+		default:
+			result.Aksi = models.ImportAksiCreate
+		}
+		valid = append(valid, row)
+	}
+
+	for _, result := range report.Rows {
+		switch result.Aksi {
+		case models.ImportAksiCreate:
+			report.Created++
+		case models.ImportAksiUpdate:
+			report.Updated++
+		default:
+			report.Failed++
+		}
+	}
+
+	if report.Failed > 0 {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(report)
+	}
+	if report.DryRun {
+		return c.Status(fiber.StatusOK).JSON(report)
+	}
+
+	report.Created, report.Updated, err = h.repo.Import(valid, claimsUserID(c), claimsAPIKeyID(c))
+	if err != nil {
+		log.Println("Error importing barang:", err.Error(), "barang_import_handler.go:ImportBarang")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+// readImportFile membaca seluruh baris file .csv atau sheet pertama file .xlsx
+func readImportFile(fh *multipart.FileHeader) ([][]string, error) {
+	file, err := fh.Open()
+	if err != nil {
+		return nil, fmt.Errorf("file tidak dapat dibaca")
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(fh.Filename)) {
+	case ".csv":
+		r := csv.NewReader(file)
+		r.FieldsPerRecord = -1
+		r.TrimLeadingSpace = true
+		records, err := r.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("file CSV tidak valid: %v", err)
+		}
+		return records, nil
+	case ".xlsx":
+		return readXLSX(file)
+	default:
+		return nil, fmt.Errorf("file harus berformat .csv atau .xlsx")
+	}
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	x, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("file XLSX tidak valid")
+	}
+	defer x.Close()
+
+	sheets := x.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("file XLSX tidak memiliki sheet")
+	}
+	rows, err := x.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("file XLSX tidak valid")
+	}
+	return rows, nil
+}
+
+// importColumnIndex memetakan nama kolom import ke indeks kolom di header file
+func importColumnIndex(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))), " ", "_")
+		for _, col := range barangImportColumns {
+			if name == col {
+				columns[col] = i
+			}
+		}
+	}
+	for _, required := range []string{"nama_barang", "satuan"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("kolom %s tidak ditemukan di header", required)
+		}
+	}
+	return columns, nil
+}
+
+// parseBarangImportRows membaca dan memvalidasi setiap baris data (baris kosong dilewati).
+// Setiap baris mendapat hasil validasi; baris yang valid juga dikembalikan sebagai BarangImportRow.
+func parseBarangImportRows(records [][]string, columns map[string]int) ([]models.BarangImportRow, []models.BarangImportRowResult) {
+	var rows []models.BarangImportRow
+	results := make([]models.BarangImportRowResult, 0, len(records))
+	seen := make(map[string]int)
+
+	for i, record := range records {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		cell := func(col string) string {
+			idx, ok := columns[col]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return unescapeFormula(strings.TrimSpace(record[idx]))
+		}
+
+		row := models.BarangImportRow{
+			Row:        i + 2,
+			KodeBarang: cell("kode_barang"),
+			Kategori:   cell("kategori"),
+			Brand:      cell("brand"),
+			Filled:     make(map[string]bool),
+			Request: models.BarangRequest{
+				NamaBarang: cell("nama_barang"),
+				Deskripsi:  cell("deskripsi"),
+				Satuan:     cell("satuan"),
+			},
+		}
+		result := models.BarangImportRowResult{
+			Row:        row.Row,
+			KodeBarang: row.KodeBarang,
+			NamaBarang: row.Request.NamaBarang,
+		}
+
+		for col, v := range map[string]string{"deskripsi": row.Request.Deskripsi, "kategori": row.Kategori, "brand": row.Brand} {
+			if v != "" {
+				row.Filled[col] = true
+			}
+		}
+
+		errMap := make(map[string]string)
+		for _, col := range []string{"harga_beli", "harga_jual"} {
+			v := cell(col)
+			if v == "" {
+				continue
+			}
+			row.Filled[col] = true
+			harga, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				errMap[col] = col + " harus berupa angka"
+				continue
+			}
+			if col == "harga_beli" {
+				row.Request.HargaBeli = harga
+			} else {
+				row.Request.HargaJual = harga
+			}
+		}
+		for k, v := range validateBarangRequest(row.Request) {
+			if _, ok := errMap[k]; !ok {
+				errMap[k] = v
+			}
+		}
+
+		if v := cell("stok_awal"); v != "" {
+			stok, err := strconv.Atoi(v)
+			if err != nil || stok < 0 {
+				errMap["stok_awal"] = "stok_awal harus bilangan bulat >= 0"
+			}
+			row.StokAwal = stok
+		}
+
+		if row.KodeBarang != "" {
+			if len(row.KodeBarang) > 50 {
+				errMap["kode_barang"] = "kode barang maksimal 50 karakter"
+			} else if first, ok := seen[row.KodeBarang]; ok {
+				errMap["kode_barang"] = fmt.Sprintf("kode barang duplikat dengan baris %d", first)
+			} else {
+				seen[row.KodeBarang] = row.Row
+			}
+		}
+
+		if len(errMap) > 0 {
+			result.Aksi = models.ImportAksiError
+			result.Errors = errMap
+		} else {
+			rows = append(rows, row)
+		}
+		results = append(results, result)
+	}
+	return rows, results
+}
+
+// reservedKodeBarang mengecek apakah kode barang baru sama dengan kode otomatis (BRG + ID) yang akan
+// diberikan ke barang berikutnya, yaitu BRG%03d dengan ID lebih besar dari ID barang terbesar saat ini
+func reservedKodeBarang(kode string, maxID uint) bool {
+	digits, ok := strings.CutPrefix(kode, "BRG")
+	if !ok {
+		return false
+	}
+	id, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return false
+	}
+	return id > uint64(maxID) && fmt.Sprintf("BRG%03d", id) == kode
+}
+
+func markImportError(result *models.BarangImportRowResult, field, message string) {
+	if result.Errors == nil {
+		result.Errors = make(map[string]string)
+	}
+	result.Errors[field] = message
+	result.Aksi = models.ImportAksiError
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/repositories"
+	"warehouse-inventory-server/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ScanBarang godoc
+// @Summary Scan barcode
+// @Description Mencari barang berdasarkan barcode (EAN/UPC) atau kode_barang, beserta stok saat ini
+// @Tags Barang
+// @Produce json
+// @Param barcode path string true "Barcode atau kode barang"
+// @Success 200 {object} models.BarangResponse "OK"
+// @Failure 404 {object} middleware.SpecificErrorResponse "Not Found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/barang/scan/{barcode} [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BarangHandler) ScanBarang(c *fiber.Ctx) error {
+	barang, err := h.repo.GetDetailByBarcode(strings.TrimSpace(c.Params("barcode")))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Barang tidak ditemukan")
+	}
+	if err != nil {
+		log.Println("Error scanning barcode:", err.Error(), "barcode_handler.go:ScanBarang")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	response, err := h.barangDetailResponse(barang)
+	if err != nil {
+		log.Println("Error fetching barcodes:", err.Error(), "barcode_handler.go:ScanBarang")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// AddBarcode godoc
+// @Summary Add barcode to barang
+// @Description Menambahkan barcode EAN-8, UPC-A, EAN-13 atau GTIN-14 ke barang (Admin only). Check digit divalidasi; barcode disimpan sebagai GTIN-14 (diawali nol) dan harus unik, sehingga UPC-A dan EAN-13 untuk produk yang sama ditolak dengan 409
+// @Tags Barang
+// @Accept json
+// @Produce json
+// @Param id path int true "Barang ID"
+// @Param body body models.BarcodeRequest true "Barcode Request"
+// @Success 201 {object} models.BarangResponse "Created"
+// @Failure 404 {object} middleware.SpecificErrorResponse "Not Found"
+// @Failure 409 {object} middleware.SpecificErrorResponse "Conflict"
+// @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/barang/{id}/barcodes [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BarangHandler) AddBarcode(c *fiber.Ctx) error {
+	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	var req models.BarcodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	req.Barcode = strings.TrimSpace(req.Barcode)
+	if !utils.ValidGTIN(req.Barcode) {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  map[string]string{"barcode": "barcode harus EAN-8, UPC-A, EAN-13 atau GTIN-14 dengan check digit yang benar"},
+		}
+	}
+
+	if _, err := h.repo.GetByID(uint(id64)); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Barang tidak ditemukan")
+	}
+	if _, err := h.repo.AddBarcode(uint(id64), req.Barcode); err != nil {
+		if errors.Is(err, repositories.ErrBarcodeSudahDipakai) {
+			return fiber.NewError(fiber.StatusConflict, "Barcode sudah dipakai")
+		}
+		log.Println("Error adding barcode:", err.Error(), "barcode_handler.go:AddBarcode")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	barang, err := h.repo.GetDetailByID(uint(id64))
+	if err != nil {
+		log.Println("Error fetching barang:", err.Error(), "barcode_handler.go:AddBarcode")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	response, err := h.barangDetailResponse(barang)
+	if err != nil {
+		log.Println("Error fetching barcodes:", err.Error(), "barcode_handler.go:AddBarcode")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// DeleteBarcode godoc
+// @Summary Delete barcode from barang
+// @Description Menghapus barcode dari barang (Admin only)
+// @Tags Barang
+// @Produce json
+// @Param id path int true "Barang ID"
+// @Param barcode path string true "Barcode"
+// @Success 200 {object} models.DeleteBarangResponse "OK"
+// @Failure 404 {object} middleware.SpecificErrorResponse "Not Found"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/barang/{id}/barcodes/{barcode} [delete]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BarangHandler) DeleteBarcode(c *fiber.Ctx) error {
+	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	barcode := c.Params("barcode")
+
+	if err := h.repo.DeleteBarcode(uint(id64), barcode); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Barcode tidak ditemukan")
+		}
+		log.Println("Error deleting barcode:", err.Error(), "barcode_handler.go:DeleteBarcode")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.DeleteBarangResponse{
+		Message: "Barcode " + barcode + " berhasil dihapus",
+	})
+}
+
+// barangDetailResponse membuat response detail barang beserta stok dan barcode-nya
+func (h *BarangHandler) barangDetailResponse(barang *models.BarangWithStock) (models.BarangResponse, error) {
+	barcodes, err := h.repo.GetBarcodes(barang.ID)
+	if err != nil {
+		return models.BarangResponse{}, err
+	}
+
+	response := mapToBarangResponse(barang)
+	for _, b := range barcodes {
+		response.Barcodes = append(response.Barcodes, b.Barcode)
+	}
+	return response, nil
+}
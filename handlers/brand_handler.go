@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/repositories"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type BrandHandler struct {
+	repo *repositories.BrandRepository
+}
+
+func NewBrandHandler(repo *repositories.BrandRepository) *BrandHandler {
+	return &BrandHandler{repo: repo}
+}
+
+// RegisterRoute mendaftarkan seluruh endpoint "/api/brand"
+func (h *BrandHandler) RegisterRoute(r fiber.Router) {
+	r.Get("/", h.GetAllBrand)
+	r.Post("/", middleware.GuardAdmin(), h.CreateBrand)
+	r.Put("/:id", middleware.GuardAdmin(), h.UpdateBrand)
+	r.Delete("/:id", middleware.GuardAdmin(), h.DeleteBrand)
+}
+
+// GetAllBrand godoc
+// @Summary Get all brand
+// @Description Daftar seluruh brand (urut nama) beserta jumlah barangnya
+// @Tags Brand
+// @Produce json
+// @Success 200 {array} models.BrandResponse "OK"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/brand [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BrandHandler) GetAllBrand(c *fiber.Ctx) error {
+	list, err := h.repo.List()
+	if err != nil {
+		log.Println("Error fetching brand:", err.Error(), "brand_handler.go:GetAllBrand")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	if list == nil {
+		list = []models.BrandResponse{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": list,
+	})
+}
+
+// CreateBrand godoc
+// @Summary Create brand
+// @Description Membuat brand baru (Admin only)
+// @Tags Brand
+// @Accept json
+// @Produce json
+// @Param body body models.BrandRequest true "Brand Request"
+// @Success 201 {object} models.Brand "Created"
+// @Failure 409 {object} middleware.SpecificErrorResponse "Conflict"
+// @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/brand [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BrandHandler) CreateBrand(c *fiber.Ctx) error {
+	var req models.BrandRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	if err := validateBrandRequest(&req); err != nil {
+		return err
+	}
+
+	brand := models.Brand{Nama: req.Nama}
+	if err := h.repo.Create(&brand); err != nil {
+		return brandError(err, "brand_handler.go:CreateBrand")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(brand)
+}
+
+// UpdateBrand godoc
+// @Summary Update brand
+// @Description Mengganti nama brand (Admin only)
+// @Tags Brand
+// @Accept json
+// @Produce json
+// @Param id path int true "Brand ID"
+// @Param body body models.BrandRequest true "Brand Request"
+// @Success 200 {object} models.Brand "OK"
+// @Failure 404 {object} middleware.SpecificErrorResponse "Not Found"
+// @Failure 409 {object} middleware.SpecificErrorResponse "Conflict"
+// @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/brand/{id} [put]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BrandHandler) UpdateBrand(c *fiber.Ctx) error {
+	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	brand, err := h.repo.GetByID(uint(id64))
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Brand tidak ditemukan")
+	}
+
+	var req models.BrandRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	if err := validateBrandRequest(&req); err != nil {
+		return err
+	}
+
+	brand.Nama = req.Nama
+	if err := h.repo.Update(brand); err != nil {
+		return brandError(err, "brand_handler.go:UpdateBrand")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(brand)
+}
+
+// DeleteBrand godoc
+// @Summary Delete brand
+// @Description Menghapus brand (Admin only). Brand yang masih dipakai barang tidak dapat dihapus
+// @Tags Brand
+// @Produce json
+// @Param id path int true "Brand ID"
+// @Success 200 {object} models.DeleteBrandResponse "OK"
+// @Failure 404 {object} middleware.SpecificErrorResponse "Not Found"
+// @Failure 409 {object} middleware.SpecificErrorResponse "Conflict"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/brand/{id} [delete]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BrandHandler) DeleteBrand(c *fiber.Ctx) error {
+	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	if err := h.repo.Delete(uint(id64)); err != nil {
+		return brandError(err, "brand_handler.go:DeleteBrand")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.DeleteBrandResponse{
+		Message: fmt.Sprintf("Brand dengan ID %d berhasil dihapus", id64),
+	})
+}
+
+func validateBrandRequest(req *models.BrandRequest) error {
+	req.Nama = strings.TrimSpace(req.Nama)
+	errMap := make(map[string]string)
+
+	switch {
+	case req.Nama == "":
+		errMap["nama"] = "nama brand tidak boleh kosong"
+	case len(req.Nama) > 100:
+		errMap["nama"] = "nama brand maksimal 100 karakter"
+	}
+
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+	return nil
+}
+
+// brandError memetakan error repository brand ke response HTTP
+func brandError(err error, source string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "Brand tidak ditemukan")
+	case errors.Is(err, repositories.ErrBrandDuplikat), errors.Is(err, repositories.ErrBrandDipakai):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	log.Println("Error saving brand:", err.Error(), source)
+	return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// claimsUserID mengambil user ID dari JWT claims yang disimpan middleware.Authentication
+func claimsUserID(c *fiber.Ctx) uint {
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		return 0
+	}
+	switch v := claims["id"].(type) {
+	case float64:
+		return uint(v)
+	case int:
+		return uint(v)
+	}
+	return 0
+}
+
+// claimsAPIKeyID mengambil ID API key jika request diautentikasi dengan API key (nil untuk JWT user)
+func claimsAPIKeyID(c *fiber.Ctx) *uint {
+	claims, ok := c.Locals("user").(jwt.MapClaims)
+	if !ok {
+		return nil
+	}
+	if v, ok := claims["api_key_id"].(float64); ok {
+		id := uint(v)
+		return &id
+	}
+	return nil
+}
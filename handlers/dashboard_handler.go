@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/repositories"
+	"warehouse-inventory-server/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/sync/singleflight"
+)
+
+// Dashboard di-cache singkat di memori agar halaman utama yang sering di-refresh tidak membebani database
+const dashboardCacheTTL = 30 * time.Second
+
+// Batas jumlah kombinasi filter yang di-cache; cache dikosongkan jika penuh
+const dashboardCacheSize = 100
+
+type dashboardCacheEntry struct {
+	summary  *models.DashboardResponse
+	cachedAt time.Time
+}
+
+type DashboardHandler struct {
+	repo *repositories.DashboardRepository
+
+	mu    sync.Mutex
+	cache map[string]dashboardCacheEntry // key: dashboardCacheKey
+	group singleflight.Group
+}
+
+func NewDashboardHandler(repo *repositories.DashboardRepository) *DashboardHandler {
+	return &DashboardHandler{repo: repo, cache: make(map[string]dashboardCacheEntry)}
+}
+
+// RegisterRoute mendaftarkan endpoint "/api/dashboard"
+func (h *DashboardHandler) RegisterRoute(r fiber.Router) {
+	r.Get("/", h.GetDashboard)
+}
+
+// GetDashboard godoc
+// @Summary Dashboard summary
+// @Description KPI halaman utama: total penjualan/pembelian hari ini & bulan ini, nilai stok, jumlah barang stok rendah, top 5 barang terlaris bulan ini dan pergerakan stok terbaru. Filter kategori/brand/atribut membatasi semua KPI ke barang yang cocok (total transaksi dihitung dari subtotal detail barang tersebut); group_by menambahkan nilai stok per kelompok di field groups. Data di-cache 30 detik per kombinasi filter
+// @Tags Dashboard
+// @Produce json
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)"
+// @Param group_by query string false "Nilai stok per kategori, brand atau atribut (di field groups)"
+// @Param kategori_level query int false "group_by kategori: level kategori (default 1 = kategori utama)"
+// @Param atribut_key query string false "group_by atribut: nama atribut"
+// @Success 200 {object} models.DashboardResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/dashboard [get]
+func (h *DashboardHandler) GetDashboard(c *fiber.Ctx) error {
+	errMap := make(map[string]string)
+	filter := parseStockReportFilter(c, errMap)
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+	key := dashboardCacheKey(filter)
+
+	h.mu.Lock()
+	entry, ok := h.cache[key]
+	h.mu.Unlock()
+	if ok && time.Since(entry.cachedAt) <= dashboardCacheTTL {
+		return c.Status(fiber.StatusOK).JSON(entry.summary)
+	}
+
+	// Dashboard dibangun di luar lock; request bersamaan dengan filter yang sama menunggu satu query yang sama
+	summary, err, _ := h.group.Do(key, func() (interface{}, error) {
+		summary, err := h.buildDashboard(filter)
+		if err != nil {
+			return nil, err
+		}
+		h.mu.Lock()
+		if len(h.cache) >= dashboardCacheSize {
+			clear(h.cache)
+		}
+		h.cache[key] = dashboardCacheEntry{summary: summary, cachedAt: time.Now()}
+		h.mu.Unlock()
+		return summary, nil
+	})
+	if err != nil {
+		log.Println("Error building dashboard:", err.Error(), "dashboard_handler.go:GetDashboard")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(summary)
+}
+
+// dashboardCacheKey membentuk key cache dari filter yang sudah diparse, sehingga kategori_id=01 dan =1
+// atau urutan atribut yang berbeda memakai entry yang sama
+func dashboardCacheKey(f models.ReportFilter) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d|%d|%s", f.KategoriID, f.BrandID, f.GroupBy)
+	switch f.GroupBy {
+	case "kategori":
+		fmt.Fprintf(&sb, "|%d", max(f.KategoriLevel, 1))
+	case "atribut":
+		fmt.Fprintf(&sb, "|%q", f.AtributKey)
+	}
+	for _, name := range slices.Sorted(maps.Keys(f.Atribut)) {
+		fmt.Fprintf(&sb, "|%q=%q", name, f.Atribut[name])
+	}
+	return sb.String()
+}
+
+func (h *DashboardHandler) buildDashboard(f models.ReportFilter) (*models.DashboardResponse, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	tomorrow := today.AddDate(0, 0, 1)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	var (
+		summary = models.DashboardResponse{GeneratedAt: now, GroupBy: f.GroupBy}
+		k       = f.KlasifikasiFilter
+		err     error
+	)
+
+	if summary.PenjualanHariIni, err = h.repo.TransactionTotal("jual_header", today, tomorrow, k); err != nil {
+		return nil, err
+	}
+	if summary.PenjualanBulanIni, err = h.repo.TransactionTotal("jual_header", monthStart, tomorrow, k); err != nil {
+		return nil, err
+	}
+	if summary.PembelianHariIni, err = h.repo.TransactionTotal("beli_header", today, tomorrow, k); err != nil {
+		return nil, err
+	}
+	if summary.PembelianBulanIni, err = h.repo.TransactionTotal("beli_header", monthStart, tomorrow, k); err != nil {
+		return nil, err
+	}
+	if summary.TotalNilaiStok, err = h.repo.StockValue(k); err != nil {
+		return nil, err
+	}
+	if summary.JumlahStokRendah, err = h.repo.CountLowStock(utils.LowStockThreshold(), k); err != nil {
+		return nil, err
+	}
+	if summary.TopBarang, err = h.repo.TopSellers(monthStart, tomorrow, 5, k); err != nil {
+		return nil, err
+	}
+
+	if f.GroupBy != "" {
+		if summary.Groups, err = h.repo.StockGroups(f); err != nil {
+			return nil, err
+		}
+	}
+
+	history, err := h.repo.RecentHistory(10, k)
+	if err != nil {
+		return nil, err
+	}
+	summary.HistoryTerbaru = make([]models.HistoryStokResponse, len(history))
+	for i, item := range history {
+		summary.HistoryTerbaru[i] = mapToHistoryStokResponse(item)
+	}
+	if summary.TopBarang == nil {
+		summary.TopBarang = []models.DashboardTopBarang{}
+	}
+
+	return &summary, nil
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"testing"
+
+	"warehouse-inventory-server/models"
+)
+
+func TestDashboardCacheKey(t *testing.T) {
+	filter := func(kategoriID uint, atribut map[string]string, groupBy string, level int, key string) models.ReportFilter {
+		return models.ReportFilter{
+			KlasifikasiFilter: models.KlasifikasiFilter{KategoriID: kategoriID, Atribut: atribut},
+			GroupBy:           groupBy, KategoriLevel: level, AtributKey: key,
+		}
+	}
+
+	same := [][2]models.ReportFilter{
+		{filter(1, nil, "", 0, ""), filter(1, map[string]string{}, "", 0, "")},
+		{filter(0, map[string]string{"warna": "hitam", "ukuran": "xl"}, "", 0, ""), filter(0, map[string]string{"ukuran": "xl", "warna": "hitam"}, "", 0, "")},
+		{filter(0, nil, "kategori", 0, ""), filter(0, nil, "kategori", 1, "")},
+		{filter(0, nil, "brand", 2, "warna"), filter(0, nil, "brand", 0, "")},
+	}
+	for _, pair := range same {
+		if a, b := dashboardCacheKey(pair[0]), dashboardCacheKey(pair[1]); a != b {
+			t.Errorf("key berbeda untuk filter yang sama: %q vs %q", a, b)
+		}
+	}
+
+	different := [][2]models.ReportFilter{
+		{filter(1, nil, "", 0, ""), filter(2, nil, "", 0, "")},
+		{filter(0, map[string]string{"warna": "hitam"}, "", 0, ""), filter(0, map[string]string{"warna": "putih"}, "", 0, "")},
+		{filter(0, map[string]string{"a|b": "c"}, "", 0, ""), filter(0, map[string]string{"a": "b|c"}, "", 0, "")},
+		{filter(0, nil, "kategori", 1, ""), filter(0, nil, "kategori", 2, "")},
+		{filter(0, nil, "atribut", 0, "warna"), filter(0, nil, "atribut", 0, "ukuran")},
+	}
+	for _, pair := range different {
+		if a, b := dashboardCacheKey(pair[0]), dashboardCacheKey(pair[1]); a == b {
+			t.Errorf("key sama untuk filter berbeda: %q", a)
+		}
+	}
+}
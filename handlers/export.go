@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// Format unduhan untuk endpoint list (?format=csv / ?format=xlsx)
+const (
+	exportFormatCSV  = "csv"
+	exportFormatXLSX = "xlsx"
+)
+
+const exportTimeLayout = "2006-01-02 15:04:05"
+
+// parseExportFormat membaca query format. String kosong berarti response JSON biasa.
+func parseExportFormat(c *fiber.Ctx) (string, error) {
+	switch format := c.Query("format", "json"); format {
+	case "json":
+		return "", nil
+	case exportFormatCSV, exportFormatXLSX:
+		return format, nil
+	default:
+		return "", fiber.NewError(fiber.StatusBadRequest, "format harus json, csv atau xlsx")
+	}
+}
+
+// exportWriter menulis baris export ke CSV atau XLSX
+type exportWriter interface {
+	Write(row []any) error
+	Close() error
+}
+
+// Awalan yang membuat sel teks dibaca sebagai formula oleh aplikasi spreadsheet (CSV injection)
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula menambahkan ' di depan teks yang diawali karakter formula sehingga ditampilkan sebagai teks biasa
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune(formulaPrefixes, rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// unescapeFormula membuang ' yang ditambahkan escapeFormula (dipakai saat import file hasil export)
+func unescapeFormula(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
+
+// streamExport mengirim unduhan CSV/XLSX. Baris ditulis oleh each langsung ke body response secara streaming
+// setelah handler selesai, sehingga each tidak boleh memakai *fiber.Ctx. Status 200 sudah terkirim saat each berjalan,
+// jadi error di tengah jalan dicatat di log lalu koneksi diputus agar client menerima unduhan yang gagal,
+// bukan file yang terpotong.
+func streamExport(c *fiber.Ctx, format, filename string, header []string, each func(write func(row ...any) error) error) error {
+	if format == exportFormatXLSX {
+		c.Set(fiber.HeaderContentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	} else {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	}
+	c.Attachment(fmt.Sprintf("%s-%s.%s", filename, time.Now().Format("20060102-150405"), format))
+
+	conn := c.Context().Conn()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		var ew exportWriter
+		if format == exportFormatXLSX {
+			ew = newXLSXExportWriter(w)
+		} else {
+			ew = &csvExportWriter{w: csv.NewWriter(w)}
+		}
+
+		cols := make([]any, len(header))
+		for i, h := range header {
+			cols[i] = h
+		}
+		err := ew.Write(cols)
+		if err == nil {
+			err = each(func(row ...any) error { return ew.Write(row) })
+		}
+		if closeErr := ew.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			log.Println("Error streaming export:", err.Error(), "export.go:streamExport", filename)
+			conn.Close()
+			return
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// exportTime memformat waktu untuk sel export; waktu kosong menjadi sel kosong
+func exportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(exportTimeLayout)
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (e *csvExportWriter) Write(row []any) error {
+	record := make([]string, len(row))
+	for i, v := range row {
+		switch v := v.(type) {
+		case string:
+			record[i] = escapeFormula(v)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case time.Time:
+			record[i] = exportTime(v)
+		case nil:
+			record[i] = ""
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExportWriter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// xlsxExportWriter memakai StreamWriter excelize (baris disimpan ke file sementara, bukan memori)
+// lalu menulis workbook ke body saat Close
+type xlsxExportWriter struct {
+	out  io.Writer
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	row  int
+	err  error
+}
+
+func newXLSXExportWriter(out io.Writer) *xlsxExportWriter {
+	file := excelize.NewFile()
+	sw, err := file.NewStreamWriter("Sheet1")
+	return &xlsxExportWriter{out: out, file: file, sw: sw, err: err}
+}
+
+func (e *xlsxExportWriter) Write(row []any) error {
+	if e.err != nil {
+		return e.err
+	}
+	for i, v := range row {
+		switch v := v.(type) {
+		case string:
+			row[i] = escapeFormula(v)
+		case time.Time:
+			row[i] = exportTime(v)
+		}
+	}
+	e.row++
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	return e.sw.SetRow(cell, row)
+}
+
+func (e *xlsxExportWriter) Close() error {
+	defer e.file.Close()
+	if e.err != nil {
+		return e.err
+	}
+	if err := e.sw.Flush(); err != nil {
+		return err
+	}
+	return e.file.Write(e.out)
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/repositories"
+	"warehouse-inventory-server/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BulkUpdateHarga godoc
+// @Summary Bulk update harga barang
+// @Description Mengubah harga beli dan/atau harga jual banyak barang sekaligus (Admin only). Barang dipilih dengan tepat satu dari barang_ids, filter (search, satuan, stock_status, kategori_id, brand_id) atau supplier (barang yang pernah dibeli dari supplier tersebut). Perubahan persen atau nominal, lalu dibulatkan ke kelipatan pembulatan. dry_run=true menampilkan preview tanpa menyimpan. Setiap perubahan dicatat di riwayat harga
+// @Tags Barang
+// @Accept json
+// @Produce json
+// @Param dry_run query bool false "Preview tanpa menyimpan"
+// @Param body body models.BulkHargaRequest true "Bulk Harga Request"
+// @Success 200 {object} models.BulkHargaResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/barang/harga/bulk [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BarangHandler) BulkUpdateHarga(c *fiber.Ctx) error {
+	var req models.BulkHargaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	if req.ArahPembulatan == "" {
+		req.ArahPembulatan = "terdekat"
+	}
+	if errMap := validateBulkHargaRequest(req); len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	targets, err := h.repo.GetHargaTargets(req)
+	if err != nil {
+		log.Println("Error fetching barang for bulk harga:", err.Error(), "harga_handler.go:BulkUpdateHarga")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	if len(targets) == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "Tidak ada barang yang cocok")
+	}
+
+	compute := func(b models.MasterBarang) models.PerubahanHarga {
+		return hitungPerubahanHarga(b, req)
+	}
+
+	response := models.BulkHargaResponse{
+		DryRun:    c.QueryBool("dry_run", false),
+		Perubahan: make([]models.PerubahanHarga, 0, len(targets)),
+	}
+	errMap := make(map[string]string)
+	ids := make([]uint, 0, len(targets))
+	for _, b := range targets {
+		change := compute(b)
+		if change.HargaBeliBaru < 0 || change.HargaJualBaru < 0 {
+			errMap[b.KodeBarang] = "harga baru tidak boleh negatif"
+		}
+		response.Perubahan = append(response.Perubahan, change)
+		ids = append(ids, b.ID)
+	}
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	if !response.DryRun {
+		response.Perubahan, err = h.repo.BulkUpdateHarga(ids, compute, req.Keterangan, claimsUserID(c), claimsAPIKeyID(c))
+		if errors.Is(err, repositories.ErrHargaNegatif) {
+			return fiber.NewError(fiber.StatusConflict, "Harga barang berubah, harga baru menjadi negatif. Silakan preview ulang")
+		}
+		if err != nil {
+			log.Println("Error bulk updating harga:", err.Error(), "harga_handler.go:BulkUpdateHarga")
+			return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+		}
+	}
+	response.JumlahBarang = len(response.Perubahan)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetHargaHistory godoc
+// @Summary Get price change history
+// @Description Riwayat perubahan harga beli/jual barang (siapa, kapan, sumber perubahan), terbaru dahulu
+// @Tags Barang
+// @Produce json
+// @Param barang_id query int false "Filter barang"
+// @Param user_id query int false "Filter user"
+// @Param sumber query string false "manual, bulk atau import"
+// @Param from query string false "Tanggal awal YYYY-MM-DD"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page (default 10, max 100)"
+// @Success 200 {object} models.HargaHistoryResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/barang/harga-history [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BarangHandler) GetHargaHistory(c *fiber.Ctx) error {
+	barangID, err := strconv.ParseUint(c.Query("barang_id", "0"), 10, 64)
+	if err != nil {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  map[string]string{"barang_id": "barang_id tidak valid"},
+		}
+	}
+	return h.getHargaHistory(c, uint(barangID))
+}
+
+// GetHargaHistoryByBarangID godoc
+// @Summary Get price change history by barang ID
+// @Description Riwayat perubahan harga satu barang, terbaru dahulu
+// @Tags Barang
+// @Produce json
+// @Param id path int true "Barang ID"
+// @Param user_id query int false "Filter user"
+// @Param sumber query string false "manual, bulk atau import"
+// @Param from query string false "Tanggal awal YYYY-MM-DD"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page (default 10, max 100)"
+// @Success 200 {object} models.HargaHistoryResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/barang/{id}/harga-history [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BarangHandler) GetHargaHistoryByBarangID(c *fiber.Ctx) error {
+	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	return h.getHargaHistory(c, uint(id64))
+}
+
+func (h *BarangHandler) getHargaHistory(c *fiber.Ctx, barangID uint) error {
+	page, limit := parsePagination(c)
+	errMap := make(map[string]string)
+	f := models.HargaHistoryFilter{
+		BarangID: barangID,
+		Sumber:   c.Query("sumber"),
+		Limit:    limit,
+		Offset:   (page - 1) * limit,
+	}
+
+	userID, err := strconv.ParseUint(c.Query("user_id", "0"), 10, 64)
+	if err != nil {
+		errMap["user_id"] = "user_id tidak valid"
+	}
+	f.UserID = uint(userID)
+
+	if f.Sumber != "" && !slices.Contains([]string{models.HargaSumberManual, models.HargaSumberBulk, models.HargaSumberImport}, f.Sumber) {
+		errMap["sumber"] = "sumber harus manual, bulk atau import"
+	}
+	if v := c.Query("from"); v != "" {
+		t, err := time.ParseInLocation(reportDateLayout, v, time.Local)
+		if err != nil {
+			errMap["from"] = "format from harus YYYY-MM-DD"
+		}
+		f.From = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.ParseInLocation(reportDateLayout, v, time.Local)
+		if err != nil {
+			errMap["to"] = "format to harus YYYY-MM-DD"
+		} else {
+			f.To = t.AddDate(0, 0, 1)
+		}
+	}
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	data, total, err := h.repo.GetHargaHistory(f)
+	if err != nil {
+		log.Println("Error fetching harga history:", err.Error(), "harga_handler.go:getHargaHistory")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	response := make([]models.HargaHistoryResponse, 0, len(data))
+	for _, item := range data {
+		response = append(response, models.HargaHistoryResponse{
+			ID:            item.ID,
+			BarangID:      item.BarangID,
+			HargaBeliLama: item.HargaBeliLama,
+			HargaBeliBaru: item.HargaBeliBaru,
+			HargaJualLama: item.HargaJualLama,
+			HargaJualBaru: item.HargaJualBaru,
+			Sumber:        item.Sumber,
+			Keterangan:    item.Keterangan,
+			APIKeyID:      item.APIKeyID,
+			CreatedAt:     item.CreatedAt,
+			Barang: models.BarangSimpleResponse{
+				KodeBarang: item.MasterBarang.KodeBarang,
+				NamaBarang: item.MasterBarang.NamaBarang,
+			},
+			User: models.UserSimpleResponse{
+				Username: item.User.Username,
+				FullName: item.User.FullName,
+			},
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response,
+		"meta": paginationMeta(page, limit, total),
+	})
+}
+
+// validateBulkHargaRequest memvalidasi pemilihan barang, target, jenis perubahan dan aturan pembulatan
+func validateBulkHargaRequest(req models.BulkHargaRequest) map[string]string {
+	errMap := make(map[string]string)
+
+	selectors := 0
+	if len(req.BarangIDs) > 0 {
+		selectors++
+	}
+	if req.Filter != nil {
+		selectors++
+		if strings.TrimSpace(req.Filter.Search) == "" && len(req.Filter.Satuan) == 0 && req.Filter.StockStatus == "" &&
+			req.Filter.KategoriID == 0 && req.Filter.BrandID == 0 {
+			errMap["filter"] = "filter harus berisi search, satuan, stock_status, kategori_id atau brand_id"
+		}
+		if req.Filter.StockStatus != "" && !slices.Contains([]string{models.StockStatusInStock, models.StockStatusLow, models.StockStatusOut}, req.Filter.StockStatus) {
+			errMap["filter"] = "stock_status harus in_stock, low atau out"
+		}
+	}
+	if strings.TrimSpace(req.Supplier) != "" {
+		selectors++
+	}
+	if selectors != 1 {
+		errMap["barang_ids"] = "pilih barang dengan tepat satu dari barang_ids, filter atau supplier"
+	}
+
+	if !slices.Contains([]string{"harga_beli", "harga_jual", "keduanya"}, req.Target) {
+		errMap["target"] = "target harus harga_beli, harga_jual atau keduanya"
+	}
+	switch req.Jenis {
+	case "persen":
+		if req.Nilai < -100 {
+			errMap["nilai"] = "persen tidak boleh kurang dari -100"
+		}
+	case "nominal":
+	default:
+		errMap["jenis"] = "jenis harus persen atau nominal"
+	}
+	if req.Nilai == 0 {
+		errMap["nilai"] = "nilai tidak boleh 0"
+	}
+	if req.Pembulatan < 0 {
+		errMap["pembulatan"] = "pembulatan tidak boleh kurang dari 0"
+	}
+	if !slices.Contains([]string{"terdekat", "atas", "bawah"}, req.ArahPembulatan) {
+		errMap["arah_pembulatan"] = "arah_pembulatan harus terdekat, atas atau bawah"
+	}
+
+	return errMap
+}
+
+// hitungPerubahanHarga menerapkan perubahan dan pembulatan ke harga yang menjadi target
+func hitungPerubahanHarga(b models.MasterBarang, req models.BulkHargaRequest) models.PerubahanHarga {
+	change := models.PerubahanHarga{
+		BarangID:      b.ID,
+		KodeBarang:    b.KodeBarang,
+		NamaBarang:    b.NamaBarang,
+		HargaBeliLama: b.HargaBeli,
+		HargaBeliBaru: b.HargaBeli,
+		HargaJualLama: b.HargaJual,
+		HargaJualBaru: b.HargaJual,
+	}
+	if req.Target == "harga_beli" || req.Target == "keduanya" {
+		change.HargaBeliBaru = utils.RoundPrice(utils.ApplyPriceChange(b.HargaBeli, req.Jenis, req.Nilai), req.Pembulatan, req.ArahPembulatan)
+	}
+	if req.Target == "harga_jual" || req.Target == "keduanya" {
+		change.HargaJualBaru = utils.RoundPrice(utils.ApplyPriceChange(b.HargaJual, req.Jenis, req.Nilai), req.Pembulatan, req.ArahPembulatan)
+	}
+	return change
+}
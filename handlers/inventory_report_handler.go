@@ -0,0 +1,393 @@
+package handlers
+
+import (
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Batas umur stok (hari) untuk laporan aging, urut dari yang terkecil
+var stockAgingBuckets = []struct {
+	label   string
+	maxDays int
+}{
+	{"0-30", 30},
+	{"31-60", 60},
+	{"61-90", 90},
+	{"91-180", 180},
+	{">180", math.MaxInt},
+}
+
+const stockAgingNoReceipt = "tanpa_pembelian"
+
+// GetABCReport godoc
+// @Summary ABC analysis
+// @Description Klasifikasi barang A/B/C berdasarkan kontribusi nilai penjualan dalam rentang tanggal. Barang masuk kelas A selama persentase kumulatif sebelum barang tersebut di bawah batas_a, kelas B di bawah batas_b, sisanya (termasuk barang tanpa penjualan) kelas C
+// @Tags Reports
+// @Produce json
+// @Param from query string false "Tanggal awal YYYY-MM-DD (default awal bulan ini)"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)"
+// @Param batas_a query number false "Batas kumulatif kelas A dalam persen (default 80)"
+// @Param batas_b query number false "Batas kumulatif kelas B dalam persen (default 95)"
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)"
+// @Param group_by query string false "Total per kategori, brand atau atribut (di field groups)"
+// @Param kategori_level query int false "group_by kategori: level kategori (default 1 = kategori utama)"
+// @Param atribut_key query string false "group_by atribut: nama atribut"
+// @Success 200 {object} models.ABCReportResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/reports/abc [get]
+func (h *ReportHandler) GetABCReport(c *fiber.Ctx) error {
+	from, to, errMap := parseDateRange(c)
+
+	batasA, err := strconv.ParseFloat(c.Query("batas_a", "80"), 64)
+	if err != nil || batasA <= 0 || batasA > 100 {
+		errMap["batas_a"] = "batas_a harus antara 0 dan 100"
+	}
+	batasB, err := strconv.ParseFloat(c.Query("batas_b", "95"), 64)
+	if err != nil || batasB < batasA || batasB > 100 {
+		errMap["batas_b"] = "batas_b harus antara batas_a dan 100"
+	}
+	filter := parseStockReportFilter(c, errMap)
+	filter.From, filter.To = from, to
+
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	rows, err := h.repo.ABCSales(filter)
+	if err != nil {
+		log.Println("Error fetching ABC report:", err.Error(), "inventory_report_handler.go:GetABCReport")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	total := 0.0
+	for _, row := range rows {
+		total += row.TotalNilai
+	}
+
+	summary := []models.ABCClassSummary{{Kelas: "A"}, {Kelas: "B"}, {Kelas: "C"}}
+	cumulative := 0.0
+	for i := range rows {
+		before := cumulative
+		if total > 0 {
+			rows[i].Persen = roundPercent(rows[i].TotalNilai * 100 / total)
+			cumulative += rows[i].TotalNilai * 100 / total
+		}
+		rows[i].PersenKumulatif = roundPercent(cumulative)
+
+		idx := 2
+		switch {
+		case rows[i].TotalNilai <= 0:
+		case before < batasA:
+			idx = 0
+		case before < batasB:
+			idx = 1
+		}
+		rows[i].Kelas = summary[idx].Kelas
+		summary[idx].JumlahItem++
+		summary[idx].TotalNilai += rows[i].TotalNilai
+	}
+	for i := range summary {
+		if total > 0 {
+			summary[i].Persen = roundPercent(summary[i].TotalNilai * 100 / total)
+		}
+	}
+	if rows == nil {
+		rows = []models.ABCReportRow{}
+	}
+
+	response := models.ABCReportResponse{
+		From:    from.Format(reportDateLayout),
+		To:      to.AddDate(0, 0, -1).Format(reportDateLayout),
+		BatasA:  batasA,
+		BatasB:  batasB,
+		Rows:    rows,
+		Summary: summary,
+		GroupBy: filter.GroupBy,
+	}
+	if filter.GroupBy != "" {
+		response.Groups = stockReportGroups(rows, func(r models.ABCReportRow) (string, string, int64, float64) {
+			return r.GroupKey, r.GroupLabel, r.TotalQty, r.TotalNilai
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetDeadStockReport godoc
+// @Summary Dead stock report
+// @Description Barang yang masih memiliki stok tetapi tidak ada pergerakan "keluar" di history stok selama N hari terakhir
+// @Tags Reports
+// @Produce json
+// @Param days query int false "Jumlah hari tanpa barang keluar (default 90)"
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)"
+// @Param group_by query string false "Total per kategori, brand atau atribut (di field groups)"
+// @Param kategori_level query int false "group_by kategori: level kategori (default 1 = kategori utama)"
+// @Param atribut_key query string false "group_by atribut: nama atribut"
+// @Success 200 {object} models.DeadStockResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/reports/dead-stock [get]
+func (h *ReportHandler) GetDeadStockReport(c *fiber.Ctx) error {
+	errMap := make(map[string]string)
+	days, err := strconv.Atoi(c.Query("days", "90"))
+	if err != nil || days <= 0 {
+		errMap["days"] = "days harus lebih dari 0"
+	}
+	filter := parseStockReportFilter(c, errMap)
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	now := time.Now()
+	rows, err := h.repo.DeadStock(now.AddDate(0, 0, -days), filter)
+	if err != nil {
+		log.Println("Error fetching dead stock report:", err.Error(), "inventory_report_handler.go:GetDeadStockReport")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	response := models.DeadStockResponse{Days: days, Rows: rows, GroupBy: filter.GroupBy}
+	for i := range rows {
+		if rows[i].TerakhirKeluar != nil {
+			hari := daysSince(now, *rows[i].TerakhirKeluar)
+			rows[i].HariTanpaKeluar = &hari
+		}
+		response.JumlahItem++
+		response.TotalNilaiStok += rows[i].NilaiStok
+	}
+	if response.Rows == nil {
+		response.Rows = []models.DeadStockRow{}
+	}
+	if filter.GroupBy != "" {
+		response.Groups = stockReportGroups(rows, func(r models.DeadStockRow) (string, string, int64, float64) {
+			return r.GroupKey, r.GroupLabel, int64(r.StokAkhir), r.NilaiStok
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetStockAgingReport godoc
+// @Summary Stock aging report
+// @Description Umur stok berdasarkan hari sejak pembelian terakhir per barang, dikelompokkan ke bucket 0-30, 31-60, 61-90, 91-180, >180 hari dan tanpa_pembelian
+// @Tags Reports
+// @Produce json
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)"
+// @Param group_by query string false "Total per kategori, brand atau atribut (di field groups)"
+// @Param kategori_level query int false "group_by kategori: level kategori (default 1 = kategori utama)"
+// @Param atribut_key query string false "group_by atribut: nama atribut"
+// @Success 200 {object} models.StockAgingResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/reports/aging [get]
+func (h *ReportHandler) GetStockAgingReport(c *fiber.Ctx) error {
+	errMap := make(map[string]string)
+	filter := parseStockReportFilter(c, errMap)
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	rows, err := h.repo.StockAging(filter)
+	if err != nil {
+		log.Println("Error fetching stock aging report:", err.Error(), "inventory_report_handler.go:GetStockAgingReport")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	buckets := make([]models.StockAgingBucket, 0, len(stockAgingBuckets)+1)
+	for _, b := range stockAgingBuckets {
+		buckets = append(buckets, models.StockAgingBucket{Bucket: b.label})
+	}
+	buckets = append(buckets, models.StockAgingBucket{Bucket: stockAgingNoReceipt})
+
+	now := time.Now()
+	for i := range rows {
+		idx := len(buckets) - 1
+		if rows[i].PenerimaanTerakhir != nil {
+			umur := daysSince(now, *rows[i].PenerimaanTerakhir)
+			rows[i].UmurHari = &umur
+			for j, b := range stockAgingBuckets {
+				if umur <= b.maxDays {
+					idx = j
+					break
+				}
+			}
+		}
+		rows[i].Bucket = buckets[idx].Bucket
+		buckets[idx].JumlahItem++
+		buckets[idx].TotalQty += rows[i].StokAkhir
+		buckets[idx].NilaiStok += rows[i].NilaiStok
+	}
+	if rows == nil {
+		rows = []models.StockAgingRow{}
+	}
+
+	response := models.StockAgingResponse{
+		Buckets: buckets,
+		Rows:    rows,
+		GroupBy: filter.GroupBy,
+	}
+	if filter.GroupBy != "" {
+		response.Groups = stockReportGroups(rows, func(r models.StockAgingRow) (string, string, int64, float64) {
+			return r.GroupKey, r.GroupLabel, int64(r.StokAkhir), r.NilaiStok
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// daysSince menghitung jumlah hari penuh dari t sampai now
+func daysSince(now, t time.Time) int {
+	return int(now.Sub(t).Hours() / 24)
+}
+
+// roundPercent membulatkan ke 2 angka desimal
+func roundPercent(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// GetTurnoverReport godoc
+// @Summary Inventory turnover report
+// @Description HPP (COGS), rata-rata stok tertimbang waktu dari history stok, turnover ratio dan days of supply per barang dalam rentang tanggal
+// @Tags Reports
+// @Produce json
+// @Param from query string false "Tanggal awal YYYY-MM-DD (default awal bulan ini)"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)"
+// @Param barang_id query int false "Filter barang"
+// @Param sort query string false "turnover (default), days_of_supply, hpp, rata_rata_stok, kode_barang"
+// @Param order query string false "desc (default) atau asc"
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)"
+// @Param group_by query string false "Turnover per kategori, brand atau atribut (di field groups)"
+// @Param kategori_level query int false "group_by kategori: level kategori (default 1 = kategori utama)"
+// @Param atribut_key query string false "group_by atribut: nama atribut"
+// @Success 200 {object} models.TurnoverReportResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/reports/turnover [get]
+func (h *ReportHandler) GetTurnoverReport(c *fiber.Ctx) error {
+	from, to, errMap := parseDateRange(c)
+
+	barangID, err := strconv.ParseUint(c.Query("barang_id", "0"), 10, 64)
+	if err != nil {
+		errMap["barang_id"] = "barang_id tidak valid"
+	}
+	sort := c.Query("sort", "turnover")
+	if !slices.Contains([]string{"turnover", "days_of_supply", "hpp", "rata_rata_stok", "kode_barang"}, sort) {
+		errMap["sort"] = "sort harus salah satu dari turnover, days_of_supply, hpp, rata_rata_stok, kode_barang"
+	}
+	order := c.Query("order", "desc")
+	if order != "asc" && order != "desc" {
+		errMap["order"] = "order harus asc atau desc"
+	}
+	filter := parseStockReportFilter(c, errMap)
+	filter.BarangID = uint(barangID)
+
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	// Periode yang belum berjalan tidak dihitung ke rata-rata stok
+	end := to
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	if !end.After(from) {
+		return fiber.NewError(fiber.StatusBadRequest, "Periode belum dimulai")
+	}
+
+	filter.From, filter.To = from, end
+	rows, err := h.repo.TurnoverReport(filter, sort, order == "desc")
+	if err != nil {
+		log.Println("Error fetching turnover report:", err.Error(), "inventory_report_handler.go:GetTurnoverReport")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	days := end.Sub(from).Hours() / 24
+	if rows == nil {
+		rows = []models.TurnoverReportRow{}
+	}
+
+	response := models.TurnoverReportResponse{
+		From:    from.Format(reportDateLayout),
+		To:      to.AddDate(0, 0, -1).Format(reportDateLayout),
+		Days:    math.Round(days*10) / 10,
+		Sort:    sort,
+		Order:   order,
+		Rows:    rows,
+		Summary: turnoverSummary(rows, days),
+		GroupBy: filter.GroupBy,
+	}
+	if filter.GroupBy != "" {
+		index := make(map[string]int)
+		var grouped [][]models.TurnoverReportRow
+		for _, row := range rows {
+			i, ok := index[row.GroupKey]
+			if !ok {
+				i = len(response.Groups)
+				index[row.GroupKey] = i
+				response.Groups = append(response.Groups, models.TurnoverReportGroup{Key: row.GroupKey, Label: row.GroupLabel})
+				grouped = append(grouped, nil)
+			}
+			grouped[i] = append(grouped[i], row)
+		}
+		for i := range response.Groups {
+			response.Groups[i].JumlahItem = len(grouped[i])
+			response.Groups[i].TurnoverReportSummary = turnoverSummary(grouped[i], days)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// turnoverSummary menghitung total HPP, rata-rata nilai stok, turnover dan days of supply dari baris turnover
+func turnoverSummary(rows []models.TurnoverReportRow, days float64) models.TurnoverReportSummary {
+	var summary models.TurnoverReportSummary
+	for _, row := range rows {
+		summary.HPP += row.HPP
+		summary.RataRataNilaiStok += row.RataRataNilaiStok
+	}
+	if summary.RataRataNilaiStok > 0 {
+		turnover := roundPercent(summary.HPP / summary.RataRataNilaiStok)
+		summary.Turnover = &turnover
+	}
+	if summary.HPP > 0 {
+		daysOfSupply := math.Round(summary.RataRataNilaiStok*days/summary.HPP*10) / 10
+		summary.DaysOfSupply = &daysOfSupply
+	}
+	return summary
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"warehouse-inventory-server/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type JWKSHandler struct {
+	keys *config.JWTKeySet
+}
+
+func NewJWKSHandler(keys *config.JWTKeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GetJWKS godoc
+// @Summary JSON Web Key Set
+// @Description Public key untuk memverifikasi JWT yang diterbitkan server ini (dipakai service internal lain)
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} map[string]interface{} "OK"
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *fiber.Ctx) error {
+	// Cache singkat agar key baru cepat terlihat oleh verifier saat rotasi
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"keys": h.keys.JWKS(),
+	})
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"strconv"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetKartuStok godoc
+// @Summary Stock card (kartu stok)
+// @Description Kartu stok barang dalam rentang tanggal: saldo awal, setiap pergerakan dengan dokumen pembelian/penjualan dan saldo berjalan, serta saldo akhir. format=csv atau format=pdf untuk unduhan
+// @Tags Stok
+// @Produce json
+// @Produce text/csv
+// @Produce application/pdf
+// @Param barang_id path int true "Barang ID"
+// @Param from query string false "Tanggal awal YYYY-MM-DD (default awal bulan ini)"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)"
+// @Param format query string false "json (default), csv atau pdf"
+// @Success 200 {object} models.KartuStokResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 404 {object} middleware.ErrorResponse "Not Found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/stok/{barang_id}/kartu [get]
+func (h *StokHandler) GetKartuStok(c *fiber.Ctx) error {
+	barangID64, err := strconv.ParseUint(c.Params("barang_id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	barangID := uint(barangID64)
+
+	from, to, errMap := parseDateRange(c)
+	format := c.Query("format", "json")
+	if format != "json" && format != "csv" && format != "pdf" {
+		errMap["format"] = "format harus json, csv atau pdf"
+	}
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	stok, err := h.repo.GetByBarangID(barangID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Barang tidak ditemukan")
+	}
+
+	opening, err := h.repo.GetStokAsOf(from, barangID, models.ReportFilter{})
+	if err != nil {
+		log.Println("Error fetching opening balance:", err.Error(), "kartu_stok_handler.go:GetKartuStok")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	rows, err := h.repo.GetKartuStok(barangID, from, to)
+	if err != nil {
+		log.Println("Error fetching kartu stok:", err.Error(), "kartu_stok_handler.go:GetKartuStok")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	kartu := models.KartuStokResponse{
+		BarangID: barangID,
+		Barang: models.BarangStokResponse{
+			KodeBarang: stok.MasterBarang.KodeBarang,
+			NamaBarang: stok.MasterBarang.NamaBarang,
+			Satuan:     stok.MasterBarang.Satuan,
+			HargaJual:  stok.MasterBarang.HargaJual,
+		},
+		From:   from.Format(reportDateLayout),
+		To:     to.AddDate(0, 0, -1).Format(reportDateLayout),
+		Mutasi: []models.KartuStokRow{},
+	}
+	if len(opening) > 0 {
+		kartu.SaldoAwal = opening[0].Stok
+	}
+
+	// Saldo berjalan dari saldo awal; adjustment negatif dicatat sebagai keluar
+	saldo := kartu.SaldoAwal
+	for _, row := range rows {
+		switch {
+		case row.JenisTransaksi == "keluar":
+			row.Keluar = row.Jumlah
+		case row.Jumlah < 0:
+			row.Keluar = -row.Jumlah
+		default:
+			row.Masuk = row.Jumlah
+		}
+		saldo += row.Masuk - row.Keluar
+		row.Saldo = saldo
+		kartu.TotalMasuk += row.Masuk
+		kartu.TotalKeluar += row.Keluar
+		kartu.Mutasi = append(kartu.Mutasi, row)
+	}
+	kartu.SaldoAkhir = saldo
+
+	filename := fmt.Sprintf("kartu-stok-%s-%s-%s", kartu.Barang.KodeBarang, kartu.From, kartu.To)
+	switch format {
+	case "csv":
+		body, err := kartuStokCSV(kartu)
+		if err != nil {
+			log.Println("Error writing kartu stok CSV:", err.Error(), "kartu_stok_handler.go:GetKartuStok")
+			return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+		}
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Attachment(filename + ".csv")
+		return c.Send(body)
+	case "pdf":
+		body, err := kartuStokPDF(kartu)
+		if err != nil {
+			log.Println("Error writing kartu stok PDF:", err.Error(), "kartu_stok_handler.go:GetKartuStok")
+			return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+		}
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		c.Attachment(filename + ".pdf")
+		return c.Send(body)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(kartu)
+}
+
+// kartuStokCSV menulis kartu stok sebagai CSV: baris saldo awal, mutasi, lalu saldo akhir.
+// Teks dari user di-escape seperti export list agar tidak dibaca sebagai formula.
+func kartuStokCSV(k models.KartuStokResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	records := [][]string{
+		{"Kartu Stok", escapeFormula(k.Barang.KodeBarang), escapeFormula(k.Barang.NamaBarang), escapeFormula(k.Barang.Satuan)},
+		{"Periode", k.From, k.To},
+		{},
+		{"Tanggal", "Jenis", "No Dokumen", "Pihak", "Keterangan", "User", "Masuk", "Keluar", "Saldo"},
+		{k.From, "", "", "", "Saldo awal", "", "", "", strconv.Itoa(k.SaldoAwal)},
+	}
+	for _, m := range k.Mutasi {
+		records = append(records, []string{
+			m.Tanggal.Format("2006-01-02 15:04:05"),
+			m.JenisTransaksi,
+			m.NoDokumen,
+			escapeFormula(m.Pihak),
+			escapeFormula(m.Keterangan),
+			escapeFormula(m.Username),
+			strconv.Itoa(m.Masuk),
+			strconv.Itoa(m.Keluar),
+			strconv.Itoa(m.Saldo),
+		})
+	}
+	records = append(records, []string{k.To, "", "", "", "Saldo akhir", "", strconv.Itoa(k.TotalMasuk), strconv.Itoa(k.TotalKeluar), strconv.Itoa(k.SaldoAkhir)})
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// kartuStokPDF menulis kartu stok sebagai PDF A4 dengan tabel mutasi
+func kartuStokPDF(k models.KartuStokResponse) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Kartu Stok "+k.Barang.KodeBarang, true)
+	pdf.SetMargins(10, 12, 10)
+	pdf.SetAutoPageBreak(true, 12)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	type column struct {
+		title string
+		width float64
+		align string
+	}
+	columns := []column{
+		{"Tanggal", 30, "L"},
+		{"No Dokumen", 24, "L"},
+		{"Keterangan", 70, "L"},
+		{"Masuk", 22, "R"},
+		{"Keluar", 22, "R"},
+		{"Saldo", 22, "R"},
+	}
+
+	header := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for _, col := range columns {
+			pdf.CellFormat(col.width, 7, col.title, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 8)
+	}
+	row := func(values ...string) {
+		if pdf.GetY() > 275 {
+			pdf.AddPage()
+			header()
+		}
+		for i, col := range columns {
+			pdf.CellFormat(col.width, 6, tr(values[i]), "1", 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	num := func(v int) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.Itoa(v)
+	}
+
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 8, "KARTU STOK", "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, tr(fmt.Sprintf("Barang  : %s - %s (%s)", k.Barang.KodeBarang, k.Barang.NamaBarang, k.Barang.Satuan)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Periode : %s s/d %s", k.From, k.To), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	header()
+	row(k.From, "", "Saldo awal", "", "", strconv.Itoa(k.SaldoAwal))
+	for _, m := range k.Mutasi {
+		keterangan := m.Keterangan
+		if m.Pihak != "" {
+			keterangan += " - " + m.Pihak
+		}
+		row(m.Tanggal.Format("2006-01-02 15:04"), m.NoDokumen, keterangan, num(m.Masuk), num(m.Keluar), strconv.Itoa(m.Saldo))
+	}
+	pdf.SetFont("Helvetica", "B", 8)
+	row(k.To, "", "Saldo akhir", strconv.Itoa(k.TotalMasuk), strconv.Itoa(k.TotalKeluar), strconv.Itoa(k.SaldoAkhir))
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
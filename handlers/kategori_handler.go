@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/repositories"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type KategoriHandler struct {
+	repo *repositories.KategoriRepository
+}
+
+func NewKategoriHandler(repo *repositories.KategoriRepository) *KategoriHandler {
+	return &KategoriHandler{repo: repo}
+}
+
+// RegisterRoute mendaftarkan seluruh endpoint "/api/kategori"
+func (h *KategoriHandler) RegisterRoute(r fiber.Router) {
+	r.Get("/", h.GetAllKategori)
+	r.Get("/:id", h.GetKategoriByID)
+	r.Post("/", middleware.GuardAdmin(), h.CreateKategori)
+	r.Put("/:id", middleware.GuardAdmin(), h.UpdateKategori)
+	r.Delete("/:id", middleware.GuardAdmin(), h.DeleteKategori)
+}
+
+// GetAllKategori godoc
+// @Summary Get all kategori
+// @Description Daftar seluruh kategori barang (bertingkat) diurutkan berdasarkan nama lengkap, beserta level dan jumlah barang termasuk sub kategori
+// @Tags Kategori
+// @Produce json
+// @Success 200 {array} models.KategoriResponse "OK"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/kategori [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *KategoriHandler) GetAllKategori(c *fiber.Ctx) error {
+	response, err := h.kategoriResponses()
+	if err != nil {
+		log.Println("Error fetching kategori:", err.Error(), "kategori_handler.go:GetAllKategori")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": response,
+	})
+}
+
+// GetKategoriByID godoc
+// @Summary Get kategori by ID
+// @Description Detail kategori beserta level, nama lengkap dan jumlah barang termasuk sub kategori
+// @Tags Kategori
+// @Produce json
+// @Param id path int true "Kategori ID"
+// @Success 200 {object} models.KategoriResponse "OK"
+// @Failure 404 {object} middleware.SpecificErrorResponse "Not Found"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/kategori/{id} [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *KategoriHandler) GetKategoriByID(c *fiber.Ctx) error {
+	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	return h.sendKategori(c, fiber.StatusOK, uint(id64))
+}
+
+// CreateKategori godoc
+// @Summary Create kategori
+// @Description Membuat kategori baru (Admin only). parent_id kosong untuk kategori utama
+// @Tags Kategori
+// @Accept json
+// @Produce json
+// @Param body body models.KategoriRequest true "Kategori Request"
+// @Success 201 {object} models.KategoriResponse "Created"
+// @Failure 409 {object} middleware.SpecificErrorResponse "Conflict"
+// @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/kategori [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *KategoriHandler) CreateKategori(c *fiber.Ctx) error {
+	var req models.KategoriRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	if err := validateKategoriRequest(&req); err != nil {
+		return err
+	}
+
+	kategori := models.Kategori{Nama: req.Nama, ParentID: req.ParentID}
+	if err := h.repo.Create(&kategori); err != nil {
+		return kategoriError(err, "kategori_handler.go:CreateKategori")
+	}
+
+	return h.sendKategori(c, fiber.StatusCreated, kategori.ID)
+}
+
+// UpdateKategori godoc
+// @Summary Update kategori
+// @Description Mengganti nama dan/atau parent kategori (Admin only). Sub kategori ikut pindah bersama kategorinya
+// @Tags Kategori
+// @Accept json
+// @Produce json
+// @Param id path int true "Kategori ID"
+// @Param body body models.KategoriRequest true "Kategori Request"
+// @Success 200 {object} models.KategoriResponse "OK"
+// @Failure 404 {object} middleware.SpecificErrorResponse "Not Found"
+// @Failure 409 {object} middleware.SpecificErrorResponse "Conflict"
+// @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/kategori/{id} [put]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *KategoriHandler) UpdateKategori(c *fiber.Ctx) error {
+	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	var req models.KategoriRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	if err := validateKategoriRequest(&req); err != nil {
+		return err
+	}
+
+	if _, err := h.repo.Update(uint(id64), req.Nama, req.ParentID); err != nil {
+		return kategoriError(err, "kategori_handler.go:UpdateKategori")
+	}
+
+	return h.sendKategori(c, fiber.StatusOK, uint(id64))
+}
+
+// DeleteKategori godoc
+// @Summary Delete kategori
+// @Description Menghapus kategori (Admin only). Kategori yang masih memiliki sub kategori atau barang tidak dapat dihapus
+// @Tags Kategori
+// @Produce json
+// @Param id path int true "Kategori ID"
+// @Success 200 {object} models.DeleteKategoriResponse "OK"
+// @Failure 404 {object} middleware.SpecificErrorResponse "Not Found"
+// @Failure 409 {object} middleware.SpecificErrorResponse "Conflict"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/kategori/{id} [delete]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *KategoriHandler) DeleteKategori(c *fiber.Ctx) error {
+	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	if err := h.repo.Delete(uint(id64)); err != nil {
+		return kategoriError(err, "kategori_handler.go:DeleteKategori")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.DeleteKategoriResponse{
+		Message: fmt.Sprintf("Kategori dengan ID %d berhasil dihapus", id64),
+	})
+}
+
+func validateKategoriRequest(req *models.KategoriRequest) error {
+	req.Nama = strings.TrimSpace(req.Nama)
+	errMap := make(map[string]string)
+
+	switch {
+	case req.Nama == "":
+		errMap["nama"] = "nama kategori tidak boleh kosong"
+	case len(req.Nama) > 100:
+		errMap["nama"] = "nama kategori maksimal 100 karakter"
+	case strings.Contains(req.Nama, ">"):
+		errMap["nama"] = "nama kategori tidak boleh mengandung karakter >"
+	}
+	if req.ParentID != nil && *req.ParentID == 0 {
+		req.ParentID = nil
+	}
+
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+	return nil
+}
+
+// kategoriError memetakan error repository kategori ke response HTTP
+func kategoriError(err error, source string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "Kategori atau parent tidak ditemukan")
+	case errors.Is(err, repositories.ErrKategoriDuplikat),
+		errors.Is(err, repositories.ErrKategoriSiklus),
+		errors.Is(err, repositories.ErrKategoriDipakai):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	log.Println("Error saving kategori:", err.Error(), source)
+	return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+}
+
+// sendKategori mengirim response satu kategori (dengan nama lengkap dan jumlah barang)
+func (h *KategoriHandler) sendKategori(c *fiber.Ctx, status int, id uint) error {
+	list, err := h.kategoriResponses()
+	if err != nil {
+		log.Println("Error fetching kategori:", err.Error(), "kategori_handler.go:sendKategori")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	i := slices.IndexFunc(list, func(k models.KategoriResponse) bool { return k.ID == id })
+	if i < 0 {
+		return fiber.NewError(fiber.StatusNotFound, "Kategori tidak ditemukan")
+	}
+	return c.Status(status).JSON(list[i])
+}
+
+// kategoriResponses mengambil seluruh kategori dan menyusun level serta nama lengkapnya dari path,
+// diurutkan berdasarkan nama lengkap sehingga sub kategori tampil di bawah induknya
+func (h *KategoriHandler) kategoriResponses() ([]models.KategoriResponse, error) {
+	list, err := h.repo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(list))
+	for _, k := range list {
+		names[strconv.FormatUint(uint64(k.ID), 10)] = k.Nama
+	}
+
+	response := make([]models.KategoriResponse, 0, len(list))
+	for _, k := range list {
+		ids := strings.Split(strings.Trim(k.Path, "/"), "/")
+		path := make([]string, len(ids))
+		for i, id := range ids {
+			path[i] = names[id]
+		}
+		response = append(response, models.KategoriResponse{
+			ID:           k.ID,
+			Nama:         k.Nama,
+			ParentID:     k.ParentID,
+			Level:        len(ids),
+			NamaLengkap:  strings.Join(path, " > "),
+			JumlahBarang: k.JumlahBarang,
+		})
+	}
+	slices.SortFunc(response, func(a, b models.KategoriResponse) int {
+		return strings.Compare(strings.ToLower(a.NamaLengkap), strings.ToLower(b.NamaLengkap))
+	})
+	return response, nil
+}
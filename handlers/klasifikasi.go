@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"warehouse-inventory-server/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Nama atribut barang: huruf kecil, angka dan underscore (spasi diubah menjadi underscore)
+var atributKeyPattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// Batas jumlah atribut per barang dan panjang nilainya
+const (
+	maxAtribut         = 20
+	maxAtributValueLen = 100
+)
+
+// barangGroupBys adalah group_by laporan berdasarkan klasifikasi barang
+var barangGroupBys = []string{"kategori", "brand", "atribut"}
+
+// normalizeAtributKey mengubah nama atribut menjadi huruf kecil dengan underscore ("Ukuran Layar" = ukuran_layar)
+func normalizeAtributKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), " ", "_")
+}
+
+// normalizeAtributValue mengubah nilai atribut menjadi huruf kecil agar filter atribut=warna:hitam
+// juga cocok dengan "Hitam" (jsonb @> membandingkan nilai secara case-sensitive)
+func normalizeAtributValue(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// normalizeAtribut memvalidasi atribut barang dari request. Nilai kosong dibuang.
+// Nama yang sama setelah dinormalisasi ("Warna" dan "warna") ditolak.
+func normalizeAtribut(in map[string]string) (models.Atribut, string) {
+	out := make(models.Atribut, len(in))
+	seen := make(map[string]string, len(in))
+	for _, k := range slices.Sorted(maps.Keys(in)) {
+		v := in[k]
+		key := normalizeAtributKey(k)
+		if !atributKeyPattern.MatchString(key) {
+			return nil, "nama atribut hanya boleh huruf, angka, spasi atau underscore (maksimal 50 karakter): " + k
+		}
+		if other, ok := seen[key]; ok {
+			return nil, "nama atribut " + other + " dan " + k + " sama-sama menjadi " + key
+		}
+		seen[key] = k
+		if v = normalizeAtributValue(v); v == "" {
+			continue
+		}
+		if len(v) > maxAtributValueLen {
+			return nil, "nilai atribut " + key + " maksimal 100 karakter"
+		}
+		out[key] = v
+	}
+	if len(out) > maxAtribut {
+		return nil, "maksimal 20 atribut per barang"
+	}
+	return out, ""
+}
+
+// jsonBodyFields mengembalikan field tingkat atas yang dikirim di body JSON (termasuk yang bernilai null).
+// Body yang bukan objek JSON menghasilkan map kosong.
+func jsonBodyFields(c *fiber.Ctx) map[string]json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(c.Body(), &fields); err != nil {
+		return map[string]json.RawMessage{}
+	}
+	return fields
+}
+
+// parseKlasifikasiFilter membaca query kategori_id (termasuk sub kategori), brand_id dan
+// atribut (format nama:nilai, pisahkan dengan koma, misal atribut=warna:hitam,ukuran:xl)
+func parseKlasifikasiFilter(c *fiber.Ctx, errMap map[string]string) models.KlasifikasiFilter {
+	var f models.KlasifikasiFilter
+
+	kategoriID, err := strconv.ParseUint(c.Query("kategori_id", "0"), 10, 64)
+	if err != nil {
+		errMap["kategori_id"] = "kategori_id tidak valid"
+	}
+	f.KategoriID = uint(kategoriID)
+
+	brandID, err := strconv.ParseUint(c.Query("brand_id", "0"), 10, 64)
+	if err != nil {
+		errMap["brand_id"] = "brand_id tidak valid"
+	}
+	f.BrandID = uint(brandID)
+
+	if v := c.Query("atribut"); v != "" {
+		f.Atribut = make(map[string]string)
+		for _, pair := range strings.Split(v, ",") {
+			key, value, ok := strings.Cut(pair, ":")
+			key = normalizeAtributKey(key)
+			if !ok || !atributKeyPattern.MatchString(key) || strings.TrimSpace(value) == "" {
+				errMap["atribut"] = "format atribut harus nama:nilai, pisahkan dengan koma"
+				break
+			}
+			f.Atribut[key] = normalizeAtributValue(value)
+		}
+	}
+	return f
+}
+
+// parseBarangGroupBy membaca kategori_level (group_by kategori, default 1) dan atribut_key (wajib untuk group_by atribut)
+func parseBarangGroupBy(c *fiber.Ctx, f *models.ReportFilter, errMap map[string]string) {
+	switch f.GroupBy {
+	case "kategori":
+		level, err := strconv.Atoi(c.Query("kategori_level", "1"))
+		if err != nil || level < 1 {
+			errMap["kategori_level"] = "kategori_level harus lebih dari 0"
+		}
+		f.KategoriLevel = level
+	case "atribut":
+		f.AtributKey = normalizeAtributKey(c.Query("atribut_key"))
+		if !atributKeyPattern.MatchString(f.AtributKey) {
+			errMap["atribut_key"] = "atribut_key wajib diisi untuk group_by atribut"
+		}
+	}
+}
+
+// parseStockReportFilter membaca filter kategori/brand/atribut dan group_by opsional (kategori, brand, atribut)
+// untuk laporan stok per barang
+func parseStockReportFilter(c *fiber.Ctx, errMap map[string]string) models.ReportFilter {
+	f := models.ReportFilter{
+		KlasifikasiFilter: parseKlasifikasiFilter(c, errMap),
+		GroupBy:           c.Query("group_by"),
+	}
+	if f.GroupBy != "" && !slices.Contains(barangGroupBys, f.GroupBy) {
+		errMap["group_by"] = "group_by harus salah satu dari " + strings.Join(barangGroupBys, ", ")
+	}
+	parseBarangGroupBy(c, &f, errMap)
+	return f
+}
+
+// stockReportGroups menjumlahkan baris laporan stok per group_key, diurutkan dari total_nilai terbesar
+func stockReportGroups[T any](rows []T, value func(T) (key, label string, qty int64, nilai float64)) []models.StockReportGroup {
+	var groups []models.StockReportGroup
+	index := make(map[string]int)
+	for _, row := range rows {
+		key, label, qty, nilai := value(row)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, models.StockReportGroup{Key: key, Label: label})
+		}
+		groups[i].JumlahItem++
+		groups[i].TotalQty += qty
+		groups[i].TotalNilai += nilai
+	}
+	slices.SortStableFunc(groups, func(a, b models.StockReportGroup) int {
+		return cmp.Compare(b.TotalNilai, a.TotalNilai)
+	})
+	return groups
+}
@@ -0,0 +1,411 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/utils"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Batas jumlah barang dan total label (barang x salinan) per permintaan label; label dibuat sekaligus di memori
+const (
+	maxLabelItems = 500
+	maxLabelTotal = 2000
+)
+
+var labelSymbologies = []string{"code128", "ean13", "qr"}
+
+// GetLabelTemplates godoc
+// @Summary List label templates
+// @Description Daftar template label bawaan dan dari LABEL_TEMPLATES_FILE
+// @Tags Barang
+// @Produce json
+// @Success 200 {array} models.LabelTemplate "OK"
+// @Router /api/barang/labels/templates [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BarangHandler) GetLabelTemplates(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": utils.LabelTemplates(),
+	})
+}
+
+// GetLabels godoc
+// @Summary Generate barang labels
+// @Description Membuat label barang (barcode, nama, harga jual) sebagai PDF untuk kertas label A4 atau ZPL untuk printer thermal, sesuai format template. Simbologi code128 dan qr memakai kode_barang; ean13 memakai barcode EAN-13 pertama yang terdaftar pada barang
+// @Tags Barang
+// @Produce application/pdf
+// @Produce text/plain
+// @Param ids query string true "ID barang, pisahkan dengan koma"
+// @Param template query string false "Nama template (default a4-3x8), lihat /api/barang/labels/templates"
+// @Param symbology query string false "code128 (default), ean13 atau qr"
+// @Param copies query int false "Jumlah salinan per barang (default 1). Jumlah barang x copies maksimal 2000 label"
+// @Param show_name query bool false "Tampilkan nama barang (default sesuai template)"
+// @Param show_price query bool false "Tampilkan harga jual (default sesuai template)"
+// @Success 200 {file} file "PDF atau ZPL"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 404 {object} middleware.SpecificErrorResponse "Not Found"
+// @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/barang/labels [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *BarangHandler) GetLabels(c *fiber.Ctx) error {
+	errMap := make(map[string]string)
+
+	var ids []uint
+	for _, v := range strings.Split(c.Query("ids"), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			errMap["ids"] = "ids harus berisi ID barang dipisahkan koma"
+			break
+		}
+		if !slices.Contains(ids, uint(id)) {
+			ids = append(ids, uint(id))
+		}
+	}
+	if len(ids) == 0 && errMap["ids"] == "" {
+		errMap["ids"] = "ids tidak boleh kosong"
+	}
+	if len(ids) > maxLabelItems {
+		errMap["ids"] = fmt.Sprintf("maksimal %d barang per permintaan", maxLabelItems)
+	}
+
+	tpl, ok := utils.FindLabelTemplate(c.Query("template", "a4-3x8"))
+	if !ok {
+		errMap["template"] = "template tidak ditemukan"
+	}
+	tpl.ShowName = c.QueryBool("show_name", tpl.ShowName)
+	tpl.ShowPrice = c.QueryBool("show_price", tpl.ShowPrice)
+
+	symbology := c.Query("symbology", "code128")
+	if !slices.Contains(labelSymbologies, symbology) {
+		errMap["symbology"] = "symbology harus code128, ean13 atau qr"
+	}
+
+	copies, err := strconv.Atoi(c.Query("copies", "1"))
+	switch {
+	case err != nil || copies < 1:
+		errMap["copies"] = "copies minimal 1"
+	case len(ids)*copies > maxLabelTotal:
+		errMap["copies"] = fmt.Sprintf("jumlah barang x copies maksimal %d label per permintaan", maxLabelTotal)
+	}
+
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	barangs, err := h.repo.GetByIDs(ids)
+	if err != nil {
+		log.Println("Error fetching barang for labels:", err.Error(), "label_handler.go:GetLabels")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	if len(barangs) != len(ids) {
+		return fiber.NewError(fiber.StatusNotFound, "Barang tidak ditemukan")
+	}
+
+	var barcodes map[uint][]string
+	if symbology == "ean13" {
+		barcodes, err = h.repo.GetBarcodesByBarangIDs(ids)
+		if err != nil {
+			log.Println("Error fetching barcodes for labels:", err.Error(), "label_handler.go:GetLabels")
+			return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+		}
+	}
+
+	items := make([]models.LabelItem, 0, len(barangs))
+	for _, b := range barangs {
+		item := models.LabelItem{
+			KodeBarang: b.KodeBarang,
+			NamaBarang: b.NamaBarang,
+			HargaJual:  b.HargaJual,
+			Barcode:    b.KodeBarang,
+		}
+		if symbology == "ean13" {
+			// Barcode disimpan sebagai GTIN-14; yang diawali nol bisa dicetak sebagai EAN-13
+			i := slices.IndexFunc(barcodes[b.ID], func(code string) bool {
+				_, ok := utils.EAN13FromGTIN(code)
+				return ok
+			})
+			if i < 0 {
+				errMap[b.KodeBarang] = "barang belum memiliki barcode EAN-13"
+				continue
+			}
+			item.Barcode, _ = utils.EAN13FromGTIN(barcodes[b.ID][i])
+		}
+		items = append(items, item)
+	}
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	if tpl.Format == "zpl" {
+		c.Set(fiber.HeaderContentType, "text/plain; charset=utf-8")
+		c.Attachment("label-barang.zpl")
+		return c.SendString(labelZPL(items, tpl, symbology, copies))
+	}
+
+	var labels []models.LabelItem
+	for _, item := range items {
+		for range copies {
+			labels = append(labels, item)
+		}
+	}
+	body, err := labelPDF(labels, tpl, symbology)
+	if err != nil {
+		log.Println("Error writing label PDF:", err.Error(), "label_handler.go:GetLabels")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Attachment("label-barang.pdf")
+	return c.Send(body)
+}
+
+// encodeLabelBarcode membuat barcode (matriks modul) sesuai simbologi
+func encodeLabelBarcode(content, symbology string) (barcode.Barcode, error) {
+	switch symbology {
+	case "ean13":
+		return ean.Encode(content)
+	case "qr":
+		return qr.Encode(content, qr.M, qr.Auto)
+	default:
+		return code128.Encode(content)
+	}
+}
+
+// labelPDF menyusun label dalam grid kolom x baris per halaman sesuai template
+func labelPDF(items []models.LabelItem, tpl models.LabelTemplate, symbology string) ([]byte, error) {
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		UnitStr: "mm",
+		Size:    fpdf.SizeType{Wd: tpl.PageWidth, Ht: tpl.PageHeight},
+	})
+	pdf.SetTitle("Label Barang", true)
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	perPage := max(tpl.Columns*tpl.Rows, 1)
+	for i, item := range items {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+		n := i % perPage
+		x := tpl.MarginLeft + float64(n%max(tpl.Columns, 1))*(tpl.LabelWidth+tpl.GapX)
+		y := tpl.MarginTop + float64(n/max(tpl.Columns, 1))*(tpl.LabelHeight+tpl.GapY)
+
+		code, err := encodeLabelBarcode(item.Barcode, symbology)
+		if err != nil {
+			return nil, fmt.Errorf("barcode %s: %w", item.KodeBarang, err)
+		}
+		drawLabelPDF(pdf, tr, x, y, tpl, item, code, symbology == "qr")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLabelPDF menggambar satu label: nama di atas, barcode, kode barang dan harga di bawah.
+// Untuk QR, kode QR di kiri dan teks di kanan.
+func drawLabelPDF(pdf *fpdf.Fpdf, tr func(string) string, x, y float64, tpl models.LabelTemplate, item models.LabelItem, code barcode.Barcode, square bool) {
+	const pad = 2.0
+	lineH := tpl.FontSize * 0.3528 * 1.3 // pt ke mm, dengan jarak baris
+	innerW := tpl.LabelWidth - 2*pad
+	textX := x + pad
+	textW := innerW
+
+	if square {
+		size := min(tpl.LabelHeight-2*pad, innerW*0.45)
+		drawBarcodePDF(pdf, code, x+pad, y+(tpl.LabelHeight-size)/2, size, size)
+		textX += size + pad
+		textW -= size + pad
+
+		ty := y + pad
+		if tpl.ShowName {
+			pdf.SetFont("Helvetica", "B", tpl.FontSize)
+			pdf.SetXY(textX, ty)
+			pdf.MultiCell(textW, lineH, tr(item.NamaBarang), "", "L", false)
+			ty = min(pdf.GetY(), y+tpl.LabelHeight/2)
+		}
+		pdf.SetFont("Helvetica", "", tpl.FontSize)
+		pdf.SetXY(textX, ty)
+		pdf.CellFormat(textW, lineH, item.KodeBarang, "", 0, "L", false, 0, "")
+		if tpl.ShowPrice {
+			pdf.SetFont("Helvetica", "B", tpl.FontSize+3)
+			pdf.SetXY(textX, y+tpl.LabelHeight-pad-lineH*1.3)
+			pdf.CellFormat(textW, lineH*1.3, formatRupiah(item.HargaJual), "", 0, "L", false, 0, "")
+		}
+		return
+	}
+
+	top := y + pad
+	if tpl.ShowName {
+		pdf.SetFont("Helvetica", "B", tpl.FontSize)
+		pdf.SetXY(textX, top)
+		pdf.CellFormat(textW, lineH, fitText(pdf, tr(item.NamaBarang), textW), "", 0, "C", false, 0, "")
+		top += lineH
+	}
+	bottom := y + tpl.LabelHeight - pad
+	if tpl.ShowPrice {
+		pdf.SetFont("Helvetica", "B", tpl.FontSize+3)
+		bottom -= lineH * 1.3
+		pdf.SetXY(textX, bottom)
+		pdf.CellFormat(textW, lineH*1.3, formatRupiah(item.HargaJual), "", 0, "C", false, 0, "")
+	}
+
+	// Teks kode di bawah barcode (human readable)
+	pdf.SetFont("Helvetica", "", tpl.FontSize-1)
+	bottom -= lineH
+	pdf.SetXY(textX, bottom)
+	pdf.CellFormat(textW, lineH, item.Barcode, "", 0, "C", false, 0, "")
+
+	barH := bottom - top - 0.5
+	if barH > 2 {
+		drawBarcodePDF(pdf, code, textX, top+0.5, textW, barH)
+	}
+}
+
+// drawBarcodePDF menggambar barcode sebagai kotak-kotak vektor (tetap tajam saat dicetak).
+// Barcode 1D memiliki tinggi 1 modul dan direntangkan setinggi h.
+func drawBarcodePDF(pdf *fpdf.Fpdf, code barcode.Barcode, x, y, w, h float64) {
+	bounds := code.Bounds()
+	cols, rows := bounds.Dx(), bounds.Dy()
+	moduleW := w / float64(cols)
+	moduleH := h / float64(rows)
+	if rows > 1 {
+		// 2D (QR): modul persegi
+		moduleW = math.Min(moduleW, moduleH)
+		moduleH = moduleW
+	}
+
+	pdf.SetFillColor(0, 0, 0)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; {
+			if !isDark(code, bounds.Min.X+col, bounds.Min.Y+row) {
+				col++
+				continue
+			}
+			start := col
+			for col < cols && isDark(code, bounds.Min.X+col, bounds.Min.Y+row) {
+				col++
+			}
+			pdf.Rect(x+float64(start)*moduleW, y+float64(row)*moduleH, float64(col-start)*moduleW, moduleH, "F")
+		}
+	}
+}
+
+func isDark(code barcode.Barcode, x, y int) bool {
+	r, _, _, _ := code.At(x, y).RGBA()
+	return r < 0x8000
+}
+
+// fitText memotong teks dengan "..." agar muat di lebar w
+func fitText(pdf *fpdf.Fpdf, s string, w float64) string {
+	if pdf.GetStringWidth(s) <= w {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > w {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// labelZPL membuat satu format ZPL per barang; salinan dicetak printer dengan ^PQ
+func labelZPL(items []models.LabelItem, tpl models.LabelTemplate, symbology string, copies int) string {
+	dpi := tpl.DPI
+	if dpi <= 0 {
+		dpi = 203
+	}
+	dots := func(mm float64) int { return int(math.Round(mm * float64(dpi) / 25.4)) }
+
+	width, height := dots(tpl.LabelWidth), dots(tpl.LabelHeight)
+	pad := dots(2)
+	fontH := dots(tpl.FontSize * 0.3528 * 1.2)
+	priceH := dots((tpl.FontSize + 4) * 0.3528 * 1.2)
+	module := max(dpi/100, 1)
+
+	var sb strings.Builder
+	for _, item := range items {
+		top := pad
+		bottom := height - pad
+
+		sb.WriteString("^XA\n^CI28\n")
+		fmt.Fprintf(&sb, "^PW%d\n^LL%d\n", width, height)
+		if tpl.ShowName {
+			fmt.Fprintf(&sb, "^FO%d,%d^A0N,%d,%d^FB%d,1,0,C^FD%s^FS\n", pad, top, fontH, fontH, width-2*pad, zplText(item.NamaBarang))
+			top += fontH + pad/2
+		}
+		if tpl.ShowPrice {
+			bottom -= priceH
+			fmt.Fprintf(&sb, "^FO%d,%d^A0N,%d,%d^FB%d,1,0,C^FD%s^FS\n", pad, bottom, priceH, priceH, width-2*pad, zplText(formatRupiah(item.HargaJual)))
+			bottom -= pad / 2
+		}
+
+		switch symbology {
+		case "qr":
+			mag := min(max((bottom-top)/30, 1), 10)
+			fmt.Fprintf(&sb, "^FO%d,%d^BQN,2,%d^FDMA,%s^FS\n", pad, top, mag, zplText(item.Barcode))
+			fmt.Fprintf(&sb, "^FO%d,%d^A0N,%d,%d^FD%s^FS\n", width/2, top+pad, fontH, fontH, zplText(item.KodeBarang))
+		case "ean13":
+			// ^BE menghitung sendiri check digit dari 12 digit pertama; baris interpretasi dicetak di bawah barcode
+			barH := max(bottom-top-fontH-pad, 20)
+			fmt.Fprintf(&sb, "^FO%d,%d^BY%d^BEN,%d,Y,N^FD%s^FS\n", pad, top, module, barH, item.Barcode[:12])
+		default:
+			barH := max(bottom-top-fontH-pad, 20)
+			fmt.Fprintf(&sb, "^FO%d,%d^BY%d^BCN,%d,Y,N,N^FD%s^FS\n", pad, top, module, barH, zplText(item.Barcode))
+		}
+
+		if copies > 1 {
+			fmt.Fprintf(&sb, "^PQ%d\n", copies)
+		}
+		sb.WriteString("^XZ\n")
+	}
+	return sb.String()
+}
+
+// zplText membuang karakter perintah ZPL (^ dan ~) dari teks field
+func zplText(s string) string {
+	return strings.NewReplacer("^", " ", "~", " ").Replace(s)
+}
+
+// formatRupiah memformat harga seperti "Rp 17.500.000"
+func formatRupiah(v float64) string {
+	s := strconv.FormatInt(int64(math.Round(math.Abs(v))), 10)
+	var sb strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(r)
+	}
+	if v < 0 {
+		return "-Rp " + sb.String()
+	}
+	return "Rp " + sb.String()
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"warehouse-inventory-server/models"
+)
+
+func TestFormatRupiah(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want string
+	}{
+		{0, "Rp 0"},
+		{999, "Rp 999"},
+		{1000, "Rp 1.000"},
+		{17500000, "Rp 17.500.000"},
+		{123456789, "Rp 123.456.789"},
+		{1234.5, "Rp 1.235"},
+		{-2500, "-Rp 2.500"},
+	}
+	for _, tt := range tests {
+		if got := formatRupiah(tt.v); got != tt.want {
+			t.Errorf("formatRupiah(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestZPLText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Mouse Wireless", "Mouse Wireless"},
+		{"Kopi ^XZ^XA", "Kopi  XZ XA"},
+		{"~JR reset", " JR reset"},
+		{"Kaos ÄÖÜ", "Kaos ÄÖÜ"},
+	}
+	for _, tt := range tests {
+		if got := zplText(tt.in); got != tt.want {
+			t.Errorf("zplText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLabelZPL(t *testing.T) {
+	tpl := models.LabelTemplate{Name: "zpl-50x30", Format: "zpl", LabelWidth: 50, LabelHeight: 30, DPI: 203,
+		ShowName: true, ShowPrice: true, FontSize: 8}
+	items := []models.LabelItem{
+		{KodeBarang: "BRG001", NamaBarang: "Kopi ^XZ~JR", HargaJual: 17500, Barcode: "BRG001"},
+		{KodeBarang: "BRG002", NamaBarang: "Teh", HargaJual: 5000, Barcode: "4006381333931"},
+	}
+
+	tests := []struct {
+		name      string
+		symbology string
+		copies    int
+		contains  []string
+		excludes  []string
+	}{
+		{"code128", "code128", 1,
+			[]string{"^PW400\n^LL240\n", "^FDKopi  XZ JR^FS", "^FDRp 17.500^FS", "^BCN,", "^FDBRG001^FS"},
+			[]string{"^PQ"}},
+		{"salinan", "code128", 3, []string{"^PQ3\n"}, nil},
+		{"ean13 tanpa check digit", "ean13", 1, []string{"^BEN,", "^FD400638133393^FS"}, []string{"^FD4006381333931^FS"}},
+		{"qr", "qr", 1, []string{"^BQN,2,", "^FDMA,BRG001^FS"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := items
+			if tt.symbology == "ean13" {
+				list = items[1:]
+			}
+			out := labelZPL(list, tpl, tt.symbology, tt.copies)
+
+			// Satu format ^XA ... ^XZ per barang; nama barang tidak boleh menutup format lebih awal
+			if n := strings.Count(out, "^XA"); n != len(list) {
+				t.Errorf("jumlah ^XA = %d, want %d", n, len(list))
+			}
+			if n := strings.Count(out, "^XZ"); n != len(list) {
+				t.Errorf("jumlah ^XZ = %d, want %d", n, len(list))
+			}
+			if strings.Contains(out, "~") {
+				t.Error("output mengandung perintah ~")
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(out, s) {
+					t.Errorf("output tidak mengandung %q:\n%s", s, out)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(out, s) {
+					t.Errorf("output mengandung %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Batas limit per halaman untuk endpoint list
+const maxPageLimit = 100
+
+// parsePagination membaca query page (default 1) dan limit (default 10, maksimal maxPageLimit)
+func parsePagination(c *fiber.Ctx) (int, int) {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+	return page, min(limit, maxPageLimit)
+}
+
+// paginationMeta membuat metadata paginasi standar untuk response list mode page/limit
+func paginationMeta(page, limit int, total int64) models.PageMeta {
+	totalPages := (total + int64(limit) - 1) / int64(limit)
+	return models.PageMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      &total,
+		TotalPages: &totalPages,
+	}
+}
+
+// parseCursor membaca query cursor. Mengembalikan nil jika query cursor tidak ada (mode page/limit);
+// cursor kosong (?cursor=) berarti halaman pertama mode cursor.
+func parseCursor(c *fiber.Ctx) (*models.Cursor, error) {
+	if !c.Context().QueryArgs().Has("cursor") {
+		return nil, nil
+	}
+	cursor, err := utils.DecodeCursor(c.Query("cursor"))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "cursor tidak valid")
+	}
+	return cursor, nil
+}
+
+// listMeta membuat metadata list. Repository diminta limit+1 baris; jika baris yang didapat lebih dari limit,
+// ada halaman berikutnya dan next_cursor dibuat dari baris terakhir yang dikembalikan.
+// Mode cursor tidak menghitung total.
+func listMeta(cursor *models.Cursor, page, limit int, total int64, fetched int, lastCursor func() string) models.PageMeta {
+	meta := models.PageMeta{Limit: limit}
+	if cursor == nil {
+		meta = paginationMeta(page, limit, total)
+	}
+	if fetched > limit {
+		next := lastCursor()
+		meta.NextCursor = &next
+	}
+	return meta
+}
+
+// hasPaginationQuery mengecek apakah request mengirim page, limit atau cursor
+func hasPaginationQuery(c *fiber.Ctx) bool {
+	args := c.Context().QueryArgs()
+	return args.Has("page") || args.Has("limit") || args.Has("cursor")
+}
+
+// parseTransaksiFilter membaca query from, to, supplier/customer (party), status, user_id, no_faktur
+// dan include (details) untuk daftar pembelian / penjualan
+func parseTransaksiFilter(c *fiber.Ctx, party string) (models.TransaksiFilter, error) {
+	errMap := make(map[string]string)
+	f := models.TransaksiFilter{
+		Party:    strings.TrimSpace(c.Query(party)),
+		Status:   c.Query("status"),
+		NoFaktur: strings.TrimSpace(c.Query("no_faktur")),
+	}
+
+	switch f.Status {
+	case "", models.StatusPembelianDraft, models.StatusPembelianSelesai:
+	default:
+		errMap["status"] = "status harus draft atau selesai"
+	}
+	switch c.Query("include") {
+	case "":
+	case "details":
+		f.WithDetails = true
+	default:
+		errMap["include"] = "include hanya mendukung details"
+	}
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.ParseInLocation(reportDateLayout, v, time.Local)
+		if err != nil {
+			errMap["from"] = "format from harus YYYY-MM-DD"
+		}
+		f.From = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.ParseInLocation(reportDateLayout, v, time.Local)
+		if err != nil {
+			errMap["to"] = "format to harus YYYY-MM-DD"
+		} else {
+			f.To = t.AddDate(0, 0, 1)
+		}
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.To.After(f.From) {
+		errMap["to"] = "to tidak boleh sebelum from"
+	}
+
+	userID, err := strconv.ParseUint(c.Query("user_id", "0"), 10, 64)
+	if err != nil {
+		errMap["user_id"] = "user_id tidak valid"
+	}
+	f.UserID = uint(userID)
+
+	if len(errMap) > 0 {
+		return f, &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"warehouse-inventory-server/mailer"
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/repositories"
+	"warehouse-inventory-server/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const forgotPasswordMessage = "Jika email terdaftar, link reset password telah dikirim"
+
+// ForgotPassword godoc
+// @Summary Request password reset
+// @Description Mengirim email berisi token reset password (sekali pakai dan kedaluwarsa) di background. Response dan waktunya selalu sama agar tidak membocorkan email terdaftar. Dibatasi per IP (429) dan per email (token baru tidak dikirim selama token sebelumnya masih aktif dan berumur kurang dari PASSWORD_RESET_COOLDOWN_MINUTES)
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.ForgotPasswordRequest true "Forgot Password Request"
+// @Success 200 {object} models.PasswordResetResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 429 {object} middleware.ErrorResponse "Too Many Requests"
+// @Router /api/auth/forgot-password [post]
+func (h *UserHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req models.ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	if req.Email == "" {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  map[string]string{"email": "Email tidak boleh kosong"},
+		}
+	}
+
+	// Token dibuat dan email dikirim di background: response dan waktunya sama untuk email terdaftar maupun tidak.
+	// Nilai dari fiber.Ctx disalin karena tidak boleh dipakai setelah handler selesai.
+	email, ip := strings.Clone(req.Email), strings.Clone(c.IP())
+	go h.issuePasswordReset(email, ip)
+
+	return c.Status(fiber.StatusOK).JSON(models.PasswordResetResponse{Message: forgotPasswordMessage})
+}
+
+// issuePasswordReset membuat token reset dan mengirim email untuk email terdaftar. Tidak ada token baru
+// jika user masih memiliki token aktif yang dibuat dalam PASSWORD_RESET_COOLDOWN_MINUTES terakhir.
+func (h *UserHandler) issuePasswordReset(email, ip string) {
+	user, err := h.repo.FindByEmail(email)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("Error finding user for password reset:", err.Error(), "password_reset_handler.go:issuePasswordReset")
+		}
+		return
+	}
+
+	rawToken, tokenHash, err := newResetToken()
+	if err != nil {
+		log.Println("Error generating reset token:", err.Error(), "password_reset_handler.go:issuePasswordReset")
+		return
+	}
+
+	ttl := passwordResetTTL()
+	now := time.Now()
+	token := models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		RequestIP: ip,
+	}
+	if err := h.repo.CreatePasswordResetToken(&token, now.Add(-passwordResetCooldown())); err != nil {
+		if !errors.Is(err, repositories.ErrResetTokenRecent) {
+			log.Println("Error saving reset token:", err.Error(), "password_reset_handler.go:issuePasswordReset")
+		}
+		return
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset password Warehouse Inventory",
+		Body:    resetPasswordEmailBody(user.FullName, rawToken, ttl),
+	}
+	if err := h.mailer.Send(msg); err != nil {
+		log.Println("Error sending reset password email:", err.Error(), "password_reset_handler.go:issuePasswordReset")
+	}
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Mengganti password menggunakan token dari email reset password. Token hanya bisa dipakai sekali
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.ResetPasswordRequest true "Reset Password Request"
+// @Success 200 {object} models.PasswordResetResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Router /api/auth/reset-password [post]
+func (h *UserHandler) ResetPassword(c *fiber.Ctx) error {
+	var req models.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	errMap := make(map[string]string)
+
+	if req.Token == "" {
+		errMap["token"] = "token tidak boleh kosong"
+	}
+
+	if req.NewPassword == "" {
+		errMap["new_password"] = "password tidak boleh kosong"
+	} else if !utils.ValidatePassword(req.NewPassword) {
+		errMap["new_password"] = "Password minimal 8 karakter, mengandung huruf besar, huruf kecil, angka, dan simbol"
+	}
+
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), 10)
+	if err != nil {
+		log.Println("Error hashing password during reset:", err.Error(), "password_reset_handler.go:ResetPassword", "Error at line 128")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	if err := h.repo.ResetPassword(hashResetToken(req.Token), string(hashed)); err != nil {
+		if errors.Is(err, repositories.ErrInvalidResetToken) {
+			return &middleware.ValidationError{
+				Message: "validation error",
+				Errors:  map[string]string{"token": err.Error()},
+			}
+		}
+		log.Println("Error resetting password:", err.Error(), "password_reset_handler.go:ResetPassword", "Error at line 139")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.PasswordResetResponse{
+		Message: "Password berhasil diubah. Silakan login dengan password baru.",
+	})
+}
+
+// newResetToken membuat token acak 256-bit beserta hash SHA-256 yang disimpan di database
+func newResetToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	raw := hex.EncodeToString(buf)
+	return raw, hashResetToken(raw), nil
+}
+
+func hashResetToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// passwordResetTTL membaca masa berlaku token reset dari env PASSWORD_RESET_TTL_MINUTES (default 30 menit)
+func passwordResetTTL() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_TTL_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 30
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func passwordResetCooldown() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_COOLDOWN_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 5
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func resetPasswordEmailBody(name, token string, ttl time.Duration) string {
+	action := "Gunakan token berikut untuk reset password:\n\n" + token
+	if base := os.Getenv("PASSWORD_RESET_URL"); base != "" {
+		action = "Klik link berikut untuk reset password:\n\n" + base + "?token=" + url.QueryEscape(token)
+	}
+	return fmt.Sprintf("Halo %s,\n\nKami menerima permintaan reset password untuk akun Anda.\n%s\n\nLink/token ini berlaku selama %d menit dan hanya dapat dipakai sekali.\nJika Anda tidak meminta reset password, abaikan email ini.\n",
+		name, action, int(ttl.Minutes()))
+}
@@ -1,16 +1,20 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"warehouse-inventory-server/middleware"
 	"warehouse-inventory-server/models"
 	"warehouse-inventory-server/repositories"
+	"warehouse-inventory-server/utils"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
 type PembelianHandler struct {
@@ -32,6 +36,7 @@ func (h *PembelianHandler) RegisterRoute(r fiber.Router) {
 	r.Post("/", h.CreatePembelian)
 	r.Get("/", h.GetAllPembelian)
 	r.Get("/:id", h.GetPembelianByID)
+	r.Post("/:id/confirm", h.ConfirmPembelian)
 }
 
 // CreatePembelian godoc
@@ -46,6 +51,7 @@ func (h *PembelianHandler) RegisterRoute(r fiber.Router) {
 // @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
 // @Failure 500 {object} middleware.ValidationError "Internal Server Error"
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/pembelian [post]
 func (h *PembelianHandler) CreatePembelian(c *fiber.Ctx) error {
 	var req models.BeliHeaderRequest
@@ -84,7 +90,8 @@ func (h *PembelianHandler) CreatePembelian(c *fiber.Ctx) error {
 	header := models.BeliHeader{
 		Supplier:  req.Supplier,
 		UserID:    userID,
-		Status:    "selesai",
+		Status:    models.StatusPembelianSelesai,
+		APIKeyID:  claimsAPIKeyID(c),
 		CreatedAt: time.Now(),
 	}
 
@@ -96,7 +103,10 @@ func (h *PembelianHandler) CreatePembelian(c *fiber.Ctx) error {
 		}
 
 		// Validasi harga beli sesuai dengan harga di master barang
-		barang, err := h.barangRepo.GetByID(d.BarangID)
+		barang, err := h.barangRepo.GetByIDOrBarcode(d.BarangID, strings.TrimSpace(d.Barcode))
+		if errors.Is(err, repositories.ErrBarcodeBarangBerbeda) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, fmt.Sprintf("Barcode %s bukan milik barang_id %d", strings.TrimSpace(d.Barcode), d.BarangID))
+		}
 		if err != nil {
 			return fiber.NewError(fiber.StatusNotFound, "Barang tidak ditemukan")
 		}
@@ -108,7 +118,7 @@ func (h *PembelianHandler) CreatePembelian(c *fiber.Ctx) error {
 		subtotal := float64(d.Qty) * d.Harga
 		total += subtotal
 		detail := models.BeliDetail{
-			BarangID: d.BarangID,
+			BarangID: barang.ID,
 			Qty:      d.Qty,
 			Harga:    d.Harga,
 			Subtotal: subtotal,
@@ -134,27 +144,101 @@ func (h *PembelianHandler) CreatePembelian(c *fiber.Ctx) error {
 
 // GetAllPembelian godoc
 // @Summary Get all purchases
-// @Description Get a list of all purchase transactions
+// @Description Get a paginated list of purchase transaction headers (tanpa detail). Detail diambil lewat GET /api/pembelian/{id} atau include=details
 // @Tags Pembelian
 // @Produce json
-// @Success 200 {object} models.PembelianResponse "OK"
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param page query int false "Page number (mode page/limit)"
+// @Param limit query int false "Items per page (default 10, maksimal 100)"
+// @Param cursor query string false "Cursor dari meta.next_cursor; kirim kosong (?cursor=) untuk halaman pertama mode cursor"
+// @Param format query string false "json (default), csv atau xlsx. csv/xlsx mengunduh seluruh hasil filter tanpa paginasi"
+// @Param from query string false "Tanggal awal YYYY-MM-DD"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif"
+// @Param supplier query string false "Filter supplier (sebagian nama)"
+// @Param status query string false "Filter status (draft, selesai)"
+// @Param user_id query int false "Filter user"
+// @Param no_faktur query string false "Filter no faktur (sebagian)"
+// @Param include query string false "details: setiap item berisi header dan details (bentuk response lama). Tanpa page/limit/cursor, seluruh data dikembalikan tanpa paginasi"
+// @Success 200 {object} models.BeliHeaderResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
 // @Failure 500 {object} middleware.ValidationError "Internal Server Error"
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/pembelian [get]
 func (h *PembelianHandler) GetAllPembelian(c *fiber.Ctx) error {
-	data, err := h.repo.GetAllPembelian()
+	page, limit := parsePagination(c)
+	filter, err := parseTransaksiFilter(c, "supplier")
 	if err != nil {
-		log.Println("Error fetching all pembelian:", err.Error(), "pembelian_handler.go:GetAllPembelian", "Error at line 144")
+		return err
+	}
+
+	format, err := parseExportFormat(c)
+	if err != nil {
+		return err
+	}
+	if format != "" {
+		header := []string{"ID", "No Faktur", "Supplier", "Total", "Status", "Jumlah Item", "User", "Tanggal"}
+		return streamExport(c, format, "pembelian", header, func(write func(row ...any) error) error {
+			return h.repo.EachPembelian(filter, func(r models.TransaksiExportRow) error {
+				return write(r.ID, r.NoFaktur, r.Pihak, r.Total, r.Status, r.JumlahItem, r.Username, r.CreatedAt)
+			})
+		})
+	}
+	// Kompatibilitas: include=details tanpa page/limit/cursor mengembalikan seluruh data dengan detail seperti sebelumnya
+	if filter.WithDetails && !hasPaginationQuery(c) {
+		data, _, err := h.repo.GetAllPembelian(filter)
+		if err != nil {
+			log.Println("Error fetching all pembelian:", err.Error(), "pembelian_handler.go:GetAllPembelian")
+			return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+		}
+		response := make([]models.PembelianResponse, 0, len(data))
+		for _, p := range data {
+			response = append(response, mapToPembelianResponse(&p))
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"data": response,
+		})
+	}
+
+	filter.Cursor, err = parseCursor(c)
+	if err != nil {
+		return err
+	}
+	filter.Limit = limit + 1
+	filter.Offset = (page - 1) * limit
+
+	data, total, err := h.repo.GetAllPembelian(filter)
+	if err != nil {
+		log.Println("Error fetching all pembelian:", err.Error(), "pembelian_handler.go:GetAllPembelian")
 		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
 	}
+	fetched := len(data)
+	data = data[:min(fetched, limit)]
 
-	var response []models.PembelianResponse
-	for _, p := range data {
-		response = append(response, mapToPembelianResponse(&p))
+	var response any
+	if filter.WithDetails {
+		list := make([]models.PembelianResponse, 0, len(data))
+		for _, p := range data {
+			list = append(list, mapToPembelianResponse(&p))
+		}
+		response = list
+	} else {
+		list := make([]models.BeliHeaderResponse, 0, len(data))
+		for _, p := range data {
+			header := mapToBeliHeaderResponse(&p)
+			header.JumlahItem = p.JumlahItem
+			list = append(list, header)
+		}
+		response = list
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"data": response,
+		"meta": listMeta(filter.Cursor, page, limit, total, fetched, func() string {
+			last := data[len(data)-1]
+			return utils.EncodeTimeCursor(last.CreatedAt, last.ID)
+		}),
 	})
 }
 
@@ -168,6 +252,7 @@ func (h *PembelianHandler) GetAllPembelian(c *fiber.Ctx) error {
 // @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/pembelian/{id} [get]
 func (h *PembelianHandler) GetPembelianByID(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
@@ -184,6 +269,50 @@ func (h *PembelianHandler) GetPembelianByID(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// ConfirmPembelian godoc
+// @Summary Confirm draft purchase
+// @Description Mengubah pembelian berstatus "draft" (misal dari saran pembelian) menjadi "selesai" dan menambahkan stok.
+// @Description Pembuat draft dan created_at tidak berubah; user dan waktu konfirmasi dicatat di confirmed_by dan confirmed_at.
+// @Description Ditolak (409) jika harga di draft sudah tidak sama dengan harga beli master barang
+// @Tags Pembelian
+// @Produce json
+// @Param id path int true "Purchase ID"
+// @Success 200 {object} models.PembelianResponse "OK"
+// @Failure 404 {object} middleware.ErrorResponse "Not Found"
+// @Failure 409 {object} middleware.ErrorResponse "Conflict"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/pembelian/{id}/confirm [post]
+func (h *PembelianHandler) ConfirmPembelian(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	if err := h.repo.ConfirmPembelian(uint(id), claimsUserID(c), claimsAPIKeyID(c)); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return fiber.NewError(fiber.StatusNotFound, "Pembelian tidak ditemukan")
+		case errors.Is(err, repositories.ErrPembelianBukanDraft):
+			return fiber.NewError(fiber.StatusConflict, "Pembelian sudah dikonfirmasi")
+		case errors.Is(err, repositories.ErrPembelianHargaBerubah):
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		log.Println("Error ConfirmPembelian:", err.Error(), "pembelian_handler.go:ConfirmPembelian")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	confirmed, err := h.repo.GetPembelianByID(uint(id))
+	if err != nil {
+		log.Println("Error fetching confirmed pembelian:", err.Error(), "pembelian_handler.go:ConfirmPembelian")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(mapToPembelianResponse(confirmed))
+}
+
 // Private helper functions untuk mapping struct response
 func mapToPembelianResponse(p *models.BeliHeader) models.PembelianResponse {
 	details := make([]models.BeliDetailResponse, len(p.Details))
@@ -203,16 +332,24 @@ func mapToPembelianResponse(p *models.BeliHeader) models.PembelianResponse {
 	}
 
 	return models.PembelianResponse{
-		Header: models.BeliHeaderResponse{
-			ID:        p.ID,
-			NoFaktur:  p.NoFaktur,
-			UserID:    p.UserID,
-			Supplier:  p.Supplier,
-			Status:    p.Status,
-			User:      models.UserSimpleResponse{Username: p.User.Username, FullName: p.User.FullName},
-			Total:     p.Total,
-			CreatedAt: p.CreatedAt,
-		},
+		Header:  mapToBeliHeaderResponse(p),
 		Details: details,
 	}
 }
+
+func mapToBeliHeaderResponse(p *models.BeliHeader) models.BeliHeaderResponse {
+	return models.BeliHeaderResponse{
+		ID:        p.ID,
+		NoFaktur:  p.NoFaktur,
+		UserID:    p.UserID,
+		Supplier:  p.Supplier,
+		Status:    p.Status,
+		APIKeyID:  p.APIKeyID,
+		User:      models.UserSimpleResponse{Username: p.User.Username, FullName: p.User.FullName},
+		Total:     p.Total,
+		CreatedAt: p.CreatedAt,
+
+		ConfirmedAt: p.ConfirmedAt,
+		ConfirmedBy: p.ConfirmedBy,
+	}
+}
@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"warehouse-inventory-server/middleware"
 	"warehouse-inventory-server/models"
 	"warehouse-inventory-server/repositories"
+	"warehouse-inventory-server/utils"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
@@ -45,6 +48,7 @@ func (h *PenjualanHandler) RegisterRoute(r fiber.Router) {
 // @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
 // @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/penjualan [post]
 func (h *PenjualanHandler) CreatePenjualan(c *fiber.Ctx) error {
 	var req models.JualHeaderRequest
@@ -84,6 +88,7 @@ func (h *PenjualanHandler) CreatePenjualan(c *fiber.Ctx) error {
 		Customer:  req.Customer,
 		UserID:    userID,
 		Status:    "selesai",
+		APIKeyID:  claimsAPIKeyID(c),
 		CreatedAt: time.Now(),
 	}
 
@@ -95,7 +100,10 @@ func (h *PenjualanHandler) CreatePenjualan(c *fiber.Ctx) error {
 		}
 
 		// Validasi harga jual sesuai dengan harga di master barang
-		barang, err := h.barangRepo.GetByID(d.BarangID)
+		barang, err := h.barangRepo.GetByIDOrBarcode(d.BarangID, strings.TrimSpace(d.Barcode))
+		if errors.Is(err, repositories.ErrBarcodeBarangBerbeda) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, fmt.Sprintf("Barcode %s bukan milik barang_id %d", strings.TrimSpace(d.Barcode), d.BarangID))
+		}
 		if err != nil {
 			return fiber.NewError(fiber.StatusNotFound, "Barang tidak ditemukan")
 		}
@@ -105,11 +113,13 @@ func (h *PenjualanHandler) CreatePenjualan(c *fiber.Ctx) error {
 
 		subtotal := float64(d.Qty) * d.Harga
 		total += subtotal
+		hargaPokok := barang.HargaBeli
 		detail := models.JualDetail{
-			BarangID: d.BarangID,
-			Qty:      d.Qty,
-			Harga:    d.Harga,
-			Subtotal: subtotal,
+			BarangID:   barang.ID,
+			Qty:        d.Qty,
+			Harga:      d.Harga,
+			Subtotal:   subtotal,
+			HargaPokok: &hargaPokok,
 		}
 		details = append(details, detail)
 	}
@@ -132,27 +142,101 @@ func (h *PenjualanHandler) CreatePenjualan(c *fiber.Ctx) error {
 
 // GetAllPenjualan godoc
 // @Summary Get all sales
-// @Description Get a list of all sale transactions
+// @Description Get a paginated list of sale transaction headers (tanpa detail). Detail diambil lewat GET /api/penjualan/{id} atau include=details
 // @Tags Penjualan
 // @Produce json
-// @Success 200 {object} models.PenjualanResponse "OK"
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param page query int false "Page number (mode page/limit)"
+// @Param limit query int false "Items per page (default 10, maksimal 100)"
+// @Param cursor query string false "Cursor dari meta.next_cursor; kirim kosong (?cursor=) untuk halaman pertama mode cursor"
+// @Param format query string false "json (default), csv atau xlsx. csv/xlsx mengunduh seluruh hasil filter tanpa paginasi"
+// @Param from query string false "Tanggal awal YYYY-MM-DD"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif"
+// @Param customer query string false "Filter customer (sebagian nama)"
+// @Param status query string false "Filter status (draft, selesai)"
+// @Param user_id query int false "Filter user"
+// @Param no_faktur query string false "Filter no faktur (sebagian)"
+// @Param include query string false "details: setiap item berisi header dan details (bentuk response lama). Tanpa page/limit/cursor, seluruh data dikembalikan tanpa paginasi"
+// @Success 200 {object} models.JualHeaderResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
 // @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/penjualan [get]
 func (h *PenjualanHandler) GetAllPenjualan(c *fiber.Ctx) error {
-	data, err := h.repo.GetAllPenjualan()
+	page, limit := parsePagination(c)
+	filter, err := parseTransaksiFilter(c, "customer")
+	if err != nil {
+		return err
+	}
+
+	format, err := parseExportFormat(c)
+	if err != nil {
+		return err
+	}
+	if format != "" {
+		header := []string{"ID", "No Faktur", "Customer", "Total", "Status", "Jumlah Item", "User", "Tanggal"}
+		return streamExport(c, format, "penjualan", header, func(write func(row ...any) error) error {
+			return h.repo.EachPenjualan(filter, func(r models.TransaksiExportRow) error {
+				return write(r.ID, r.NoFaktur, r.Pihak, r.Total, r.Status, r.JumlahItem, r.Username, r.CreatedAt)
+			})
+		})
+	}
+	// Kompatibilitas: include=details tanpa page/limit/cursor mengembalikan seluruh data dengan detail seperti sebelumnya
+	if filter.WithDetails && !hasPaginationQuery(c) {
+		data, _, err := h.repo.GetAllPenjualan(filter)
+		if err != nil {
+			log.Println("Error fetching all penjualan:", err.Error(), "penjualan_handler.go:GetAllPenjualan")
+			return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+		}
+		response := make([]models.PenjualanResponse, 0, len(data))
+		for _, p := range data {
+			response = append(response, mapToPenjualanResponse(&p))
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"data": response,
+		})
+	}
+
+	filter.Cursor, err = parseCursor(c)
+	if err != nil {
+		return err
+	}
+	filter.Limit = limit + 1
+	filter.Offset = (page - 1) * limit
+
+	data, total, err := h.repo.GetAllPenjualan(filter)
 	if err != nil {
-		log.Println("Error fetching all penjualan:", err.Error(), "penjualan_handler.go:GetAllPenjualan", "Error at line 143")
+		log.Println("Error fetching all penjualan:", err.Error(), "penjualan_handler.go:GetAllPenjualan")
 		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
 	}
+	fetched := len(data)
+	data = data[:min(fetched, limit)]
 
-	var response []models.PenjualanResponse
-	for _, p := range data {
-		response = append(response, mapToPenjualanResponse(&p))
+	var response any
+	if filter.WithDetails {
+		list := make([]models.PenjualanResponse, 0, len(data))
+		for _, p := range data {
+			list = append(list, mapToPenjualanResponse(&p))
+		}
+		response = list
+	} else {
+		list := make([]models.JualHeaderResponse, 0, len(data))
+		for _, p := range data {
+			header := mapToJualHeaderResponse(&p)
+			header.JumlahItem = p.JumlahItem
+			list = append(list, header)
+		}
+		response = list
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"data": response,
+		"meta": listMeta(filter.Cursor, page, limit, total, fetched, func() string {
+			last := data[len(data)-1]
+			return utils.EncodeTimeCursor(last.CreatedAt, last.ID)
+		}),
 	})
 }
 
@@ -166,6 +250,7 @@ func (h *PenjualanHandler) GetAllPenjualan(c *fiber.Ctx) error {
 // @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
 // @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/penjualan/{id} [get]
 func (h *PenjualanHandler) GetPenjualanByID(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
@@ -201,16 +286,21 @@ func mapToPenjualanResponse(p *models.JualHeader) models.PenjualanResponse {
 	}
 
 	return models.PenjualanResponse{
-		Header: models.JualHeaderResponse{
-			ID:        p.ID,
-			NoFaktur:  p.NoFaktur,
-			Customer:  p.Customer,
-			UserID:    p.UserID,
-			User:      models.UserSimpleResponse{Username: p.User.Username, FullName: p.User.FullName},
-			Total:     p.Total,
-			Status:    p.Status,
-			CreatedAt: p.CreatedAt,
-		},
+		Header:  mapToJualHeaderResponse(p),
 		Details: details,
 	}
 }
+
+func mapToJualHeaderResponse(p *models.JualHeader) models.JualHeaderResponse {
+	return models.JualHeaderResponse{
+		ID:        p.ID,
+		NoFaktur:  p.NoFaktur,
+		Customer:  p.Customer,
+		UserID:    p.UserID,
+		User:      models.UserSimpleResponse{Username: p.User.Username, FullName: p.User.FullName},
+		Total:     p.Total,
+		Status:    p.Status,
+		APIKeyID:  p.APIKeyID,
+		CreatedAt: p.CreatedAt,
+	}
+}
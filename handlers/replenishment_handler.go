@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/repositories"
+	"warehouse-inventory-server/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ReplenishmentHandler struct {
+	repo          *repositories.ReplenishmentRepository
+	pembelianRepo *repositories.PembelianRepository
+}
+
+func NewReplenishmentHandler(repo *repositories.ReplenishmentRepository, pembelianRepo *repositories.PembelianRepository) *ReplenishmentHandler {
+	return &ReplenishmentHandler{repo: repo, pembelianRepo: pembelianRepo}
+}
+
+// RegisterRoute mendaftarkan seluruh endpoint "/api/replenishment"
+func (h *ReplenishmentHandler) RegisterRoute(r fiber.Router) {
+	r.Get("/suggestions", h.GetSuggestions)
+	r.Post("/draft", h.CreateDraft)
+	r.Get("/lead-times", h.GetLeadTimes)
+	r.Put("/lead-times", middleware.GuardAdmin(), h.UpsertLeadTime)
+	r.Delete("/lead-times/:id", middleware.GuardAdmin(), h.DeleteLeadTime)
+}
+
+// GetSuggestions godoc
+// @Summary Purchase suggestions
+// @Description Saran pembelian berdasarkan rata-rata barang keluar harian, stok akhir, lead time supplier dan safety stock. Hanya barang yang stoknya sudah mencapai reorder point yang ditampilkan, kecuali all=true
+// @Tags Replenishment
+// @Produce json
+// @Param window_days query int false "Periode rata-rata pemakaian dalam hari (default REPLENISHMENT_WINDOW_DAYS)"
+// @Param safety_days query int false "Safety stock dalam hari pemakaian (default REPLENISHMENT_SAFETY_DAYS)"
+// @Param cover_days query int false "Hari pemakaian yang ditutup satu kali pesan (default REPLENISHMENT_COVER_DAYS)"
+// @Param supplier query string false "Hanya barang dari supplier ini"
+// @Param all query bool false "Tampilkan semua barang termasuk yang belum perlu dipesan"
+// @Success 200 {object} models.ReplenishmentResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/replenishment/suggestions [get]
+func (h *ReplenishmentHandler) GetSuggestions(c *fiber.Ctx) error {
+	params := utils.LoadReplenishmentParams()
+	for key, target := range map[string]*int{
+		"window_days": &params.WindowDays,
+		"safety_days": &params.SafetyDays,
+		"cover_days":  &params.CoverDays,
+	} {
+		if raw := c.Query(key); raw != "" {
+			val, err := strconv.Atoi(raw)
+			if err != nil || val < 0 || (key == "window_days" && val == 0) {
+				return fiber.NewError(fiber.StatusBadRequest, key+" tidak valid")
+			}
+			*target = val
+		}
+	}
+	params.Supplier = strings.TrimSpace(c.Query("supplier"))
+
+	suggestions, err := h.suggest(params, nil, c.QueryBool("all"))
+	if err != nil {
+		log.Println("Error computing replenishment:", err.Error(), "replenishment_handler.go:GetSuggestions")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.ReplenishmentResponse{
+		Params: params,
+		Data:   suggestions,
+	})
+}
+
+// CreateDraft godoc
+// @Summary Create draft purchases from suggestions
+// @Description Menghitung saran pembelian dan membuat satu pembelian berstatus "draft" per supplier. Draft dikonfirmasi lewat POST /api/pembelian/{id}/confirm
+// @Tags Replenishment
+// @Accept json
+// @Produce json
+// @Param body body models.ReplenishmentDraftRequest true "Draft Request"
+// @Success 201 {object} models.ReplenishmentDraftResponse "Created"
+// @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/replenishment/draft [post]
+func (h *ReplenishmentHandler) CreateDraft(c *fiber.Ctx) error {
+	var req models.ReplenishmentDraftRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	errMap := make(map[string]string)
+
+	switch {
+	case req.WindowDays < 0:
+		errMap["window_days"] = "window_days tidak boleh kurang dari 0"
+	case req.SafetyDays < 0:
+		errMap["safety_days"] = "safety_days tidak boleh kurang dari 0"
+	case req.CoverDays < 0:
+		errMap["cover_days"] = "cover_days tidak boleh kurang dari 0"
+	}
+
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	params := utils.LoadReplenishmentParams()
+	if req.WindowDays > 0 {
+		params.WindowDays = req.WindowDays
+	}
+	if req.SafetyDays > 0 {
+		params.SafetyDays = req.SafetyDays
+	}
+	if req.CoverDays > 0 {
+		params.CoverDays = req.CoverDays
+	}
+	params.Supplier = strings.TrimSpace(req.Supplier)
+
+	suggestions, err := h.suggest(params, req.BarangIDs, false)
+	if err != nil {
+		log.Println("Error computing replenishment:", err.Error(), "replenishment_handler.go:CreateDraft")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	// Kelompokkan saran per supplier, urutan supplier mengikuti urutan kemunculan
+	defaultSupplier := strings.TrimSpace(req.DefaultSupplier)
+	var suppliers []string
+	bySupplier := make(map[string][]models.ReplenishmentSuggestion)
+	skipped := []models.ReplenishmentSuggestion{}
+	for _, s := range suggestions {
+		supplier := s.Supplier
+		if supplier == "" {
+			supplier = defaultSupplier
+		}
+		if supplier == "" {
+			skipped = append(skipped, s)
+			continue
+		}
+		if _, ok := bySupplier[supplier]; !ok {
+			suppliers = append(suppliers, supplier)
+		}
+		bySupplier[supplier] = append(bySupplier[supplier], s)
+	}
+
+	drafts := []models.PembelianResponse{}
+	for _, supplier := range suppliers {
+		header := models.BeliHeader{
+			Supplier:  supplier,
+			UserID:    claimsUserID(c),
+			APIKeyID:  claimsAPIKeyID(c),
+			CreatedAt: time.Now(),
+		}
+
+		var details []models.BeliDetail
+		for _, s := range bySupplier[supplier] {
+			details = append(details, models.BeliDetail{
+				BarangID: s.BarangID,
+				Qty:      s.SaranQty,
+				Harga:    s.HargaBeli,
+				Subtotal: s.Subtotal,
+			})
+			header.Total += s.Subtotal
+		}
+
+		if err := h.pembelianRepo.CreateDraftPembelian(&header, details); err != nil {
+			log.Println("Error creating draft pembelian:", err.Error(), "replenishment_handler.go:CreateDraft")
+			return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+		}
+
+		created, err := h.pembelianRepo.GetPembelianByID(header.ID)
+		if err != nil {
+			log.Println("Error fetching draft pembelian:", err.Error(), "replenishment_handler.go:CreateDraft")
+			return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+		}
+		drafts = append(drafts, mapToPembelianResponse(created))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.ReplenishmentDraftResponse{
+		Drafts:  drafts,
+		Skipped: skipped,
+	})
+}
+
+// GetLeadTimes godoc
+// @Summary Get supplier lead times
+// @Description Daftar lead time (hari) per supplier. Supplier yang tidak terdaftar memakai REPLENISHMENT_LEAD_TIME_DAYS
+// @Tags Replenishment
+// @Produce json
+// @Success 200 {object} models.SupplierLeadTime "OK"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/replenishment/lead-times [get]
+func (h *ReplenishmentHandler) GetLeadTimes(c *fiber.Ctx) error {
+	list, err := h.repo.ListLeadTimes()
+	if err != nil {
+		log.Println("Error fetching lead times:", err.Error(), "replenishment_handler.go:GetLeadTimes")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": list,
+	})
+}
+
+// UpsertLeadTime godoc
+// @Summary Set supplier lead time
+// @Description Membuat atau memperbarui lead time untuk supplier (Admin only)
+// @Tags Replenishment
+// @Accept json
+// @Produce json
+// @Param body body models.SupplierLeadTimeRequest true "Lead Time Request"
+// @Success 200 {object} models.SupplierLeadTime "OK"
+// @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/replenishment/lead-times [put]
+func (h *ReplenishmentHandler) UpsertLeadTime(c *fiber.Ctx) error {
+	var req models.SupplierLeadTimeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	errMap := make(map[string]string)
+
+	switch {
+	case strings.TrimSpace(req.Supplier) == "":
+		errMap["supplier"] = "Nama supplier tidak boleh kosong"
+	case req.LeadTimeDays < 0:
+		errMap["lead_time_days"] = "lead time tidak boleh kurang dari 0"
+	}
+
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	leadTime := models.SupplierLeadTime{
+		Supplier:     strings.TrimSpace(req.Supplier),
+		LeadTimeDays: req.LeadTimeDays,
+		UpdatedAt:    time.Now(),
+	}
+	if err := h.repo.UpsertLeadTime(&leadTime); err != nil {
+		log.Println("Error saving lead time:", err.Error(), "replenishment_handler.go:UpsertLeadTime")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(leadTime)
+}
+
+// DeleteLeadTime godoc
+// @Summary Delete supplier lead time
+// @Description Menghapus lead time supplier sehingga kembali memakai lead time default (Admin only)
+// @Tags Replenishment
+// @Produce json
+// @Param id path int true "Lead Time ID"
+// @Success 200 {object} map[string]interface{} "OK"
+// @Failure 404 {object} middleware.ErrorResponse "Not Found"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/replenishment/lead-times/{id} [delete]
+func (h *ReplenishmentHandler) DeleteLeadTime(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	if err := h.repo.DeleteLeadTime(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Lead time tidak ditemukan")
+		}
+		log.Println("Error deleting lead time:", err.Error(), "replenishment_handler.go:DeleteLeadTime")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Lead time berhasil dihapus",
+	})
+}
+
+// suggest menghitung saran pembelian untuk seluruh barang (atau barangIDs), difilter per supplier.
+// Jika all=false hanya barang dengan saran qty > 0 yang dikembalikan.
+func (h *ReplenishmentHandler) suggest(params models.ReplenishmentParams, barangIDs []uint, all bool) ([]models.ReplenishmentSuggestion, error) {
+	from := time.Now().AddDate(0, 0, -params.WindowDays)
+	usage, err := h.repo.GetUsage(from, barangIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []models.ReplenishmentSuggestion{}
+	for _, u := range usage {
+		s := utils.SuggestReplenishment(u, params)
+		if params.Supplier != "" && !strings.EqualFold(s.Supplier, params.Supplier) {
+			continue
+		}
+		if !all && s.SaranQty <= 0 {
+			continue
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/repositories"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const reportDateLayout = "2006-01-02"
+
+type ReportHandler struct {
+	repo *repositories.ReportRepository
+}
+
+func NewReportHandler(repo *repositories.ReportRepository) *ReportHandler {
+	return &ReportHandler{repo: repo}
+}
+
+// RegisterRoute mendaftarkan seluruh endpoint "/api/reports"
+func (h *ReportHandler) RegisterRoute(r fiber.Router) {
+	r.Get("/penjualan", h.GetPenjualanReport)
+	r.Get("/pembelian", h.GetPembelianReport)
+	r.Get("/margin", h.GetMarginReport)
+	r.Get("/abc", h.GetABCReport)
+	r.Get("/dead-stock", h.GetDeadStockReport)
+	r.Get("/aging", h.GetStockAgingReport)
+	r.Get("/turnover", h.GetTurnoverReport)
+}
+
+// GetPenjualanReport godoc
+// @Summary Sales report
+// @Description Total penjualan (jumlah transaksi, qty, nilai) per periode, barang, customer, user, kategori, brand atau atribut barang dalam rentang tanggal
+// @Tags Reports
+// @Produce json
+// @Param group_by query string false "day, week, month (default), barang, customer, user, kategori, brand, atribut"
+// @Param from query string false "Tanggal awal YYYY-MM-DD (default awal bulan ini)"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)"
+// @Param barang_id query int false "Filter barang"
+// @Param user_id query int false "Filter user"
+// @Param customer query string false "Filter customer (sebagian nama)"
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)"
+// @Param kategori_level query int false "group_by kategori: level kategori (default 1 = kategori utama)"
+// @Param atribut_key query string false "group_by atribut: nama atribut"
+// @Success 200 {object} models.TransactionReportResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/reports/penjualan [get]
+func (h *ReportHandler) GetPenjualanReport(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c, "customer", []string{"day", "week", "month", "barang", "customer", "user", "kategori", "brand", "atribut"}, "month")
+	if err != nil {
+		return err
+	}
+
+	rows, summary, err := h.repo.PenjualanReport(filter)
+	if err != nil {
+		log.Println("Error fetching penjualan report:", err.Error(), "report_handler.go:GetPenjualanReport", "Error at line 57")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newTransactionReportResponse(c, filter, rows, summary))
+}
+
+// GetPembelianReport godoc
+// @Summary Purchase report
+// @Description Total pembelian (jumlah transaksi, qty, nilai) per periode, barang, supplier, user, kategori, brand atau atribut barang dalam rentang tanggal
+// @Tags Reports
+// @Produce json
+// @Param group_by query string false "day, week, month (default), barang, supplier, user, kategori, brand, atribut"
+// @Param from query string false "Tanggal awal YYYY-MM-DD (default awal bulan ini)"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)"
+// @Param barang_id query int false "Filter barang"
+// @Param user_id query int false "Filter user"
+// @Param supplier query string false "Filter supplier (sebagian nama)"
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)"
+// @Param kategori_level query int false "group_by kategori: level kategori (default 1 = kategori utama)"
+// @Param atribut_key query string false "group_by atribut: nama atribut"
+// @Success 200 {object} models.TransactionReportResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/reports/pembelian [get]
+func (h *ReportHandler) GetPembelianReport(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c, "supplier", []string{"day", "week", "month", "barang", "supplier", "user", "kategori", "brand", "atribut"}, "month")
+	if err != nil {
+		return err
+	}
+
+	rows, summary, err := h.repo.PembelianReport(filter)
+	if err != nil {
+		log.Println("Error fetching pembelian report:", err.Error(), "report_handler.go:GetPembelianReport", "Error at line 88")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newTransactionReportResponse(c, filter, rows, summary))
+}
+
+// GetMarginReport godoc
+// @Summary Gross margin report
+// @Description Penjualan, HPP, margin dan persentase margin per barang, customer, faktur penjualan, kategori, brand atau atribut barang dalam rentang tanggal
+// @Tags Reports
+// @Produce json
+// @Param group_by query string false "barang (default), customer, invoice, kategori, brand, atribut"
+// @Param from query string false "Tanggal awal YYYY-MM-DD (default awal bulan ini)"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)"
+// @Param barang_id query int false "Filter barang"
+// @Param user_id query int false "Filter user"
+// @Param customer query string false "Filter customer (sebagian nama)"
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)"
+// @Param kategori_level query int false "group_by kategori: level kategori (default 1 = kategori utama)"
+// @Param atribut_key query string false "group_by atribut: nama atribut"
+// @Success 200 {object} models.MarginReportResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/reports/margin [get]
+func (h *ReportHandler) GetMarginReport(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c, "customer", []string{"barang", "customer", "invoice", "kategori", "brand", "atribut"}, "barang")
+	if err != nil {
+		return err
+	}
+
+	rows, summary, err := h.repo.MarginReport(filter)
+	if err != nil {
+		log.Println("Error fetching margin report:", err.Error(), "report_handler.go:GetMarginReport", "Error at line 132")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	if rows == nil {
+		rows = []models.MarginReportRow{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.MarginReportResponse{
+		GroupBy: c.Query("group_by", "barang"),
+		From:    filter.From.Format(reportDateLayout),
+		To:      filter.To.AddDate(0, 0, -1).Format(reportDateLayout),
+		Rows:    rows,
+		Summary: *summary,
+	})
+}
+
+// parseReportFilter membaca query from, to, group_by, barang_id, user_id, customer/supplier (party),
+// filter kategori/brand/atribut serta kategori_level/atribut_key.
+// group_by harus salah satu dari groupBys; nilai customer/supplier diubah menjadi "party" untuk repository.
+func parseReportFilter(c *fiber.Ctx, party string, groupBys []string, defaultGroupBy string) (models.ReportFilter, error) {
+	errMap := make(map[string]string)
+
+	from, to, dateErrs := parseDateRange(c)
+	for k, v := range dateErrs {
+		errMap[k] = v
+	}
+
+	groupBy := c.Query("group_by", defaultGroupBy)
+	if !slices.Contains(groupBys, groupBy) {
+		errMap["group_by"] = "group_by harus salah satu dari " + strings.Join(groupBys, ", ")
+	} else if groupBy == party {
+		groupBy = "party"
+	}
+
+	barangID, err := strconv.ParseUint(c.Query("barang_id", "0"), 10, 64)
+	if err != nil {
+		errMap["barang_id"] = "barang_id tidak valid"
+	}
+	userID, err := strconv.ParseUint(c.Query("user_id", "0"), 10, 64)
+	if err != nil {
+		errMap["user_id"] = "user_id tidak valid"
+	}
+
+	f := models.ReportFilter{
+		From:              from,
+		To:                to,
+		GroupBy:           groupBy,
+		BarangID:          uint(barangID),
+		UserID:            uint(userID),
+		Party:             c.Query(party),
+		KlasifikasiFilter: parseKlasifikasiFilter(c, errMap),
+	}
+	parseBarangGroupBy(c, &f, errMap)
+
+	if len(errMap) > 0 {
+		return models.ReportFilter{}, &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+	return f, nil
+}
+
+// parseDateRange membaca query from/to (YYYY-MM-DD). Default: awal bulan ini sampai hari ini.
+// Nilai to yang dikembalikan adalah awal hari setelah tanggal "to" (batas eksklusif).
+func parseDateRange(c *fiber.Ctx) (time.Time, time.Time, map[string]string) {
+	errMap := make(map[string]string)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	if v := c.Query("from"); v != "" {
+		t, err := time.ParseInLocation(reportDateLayout, v, time.Local)
+		if err != nil {
+			errMap["from"] = "format from harus YYYY-MM-DD"
+		}
+		from = t
+	}
+
+	to := today
+	if v := c.Query("to"); v != "" {
+		t, err := time.ParseInLocation(reportDateLayout, v, time.Local)
+		if err != nil {
+			errMap["to"] = "format to harus YYYY-MM-DD"
+		}
+		to = t
+	}
+
+	if len(errMap) == 0 && to.Before(from) {
+		errMap["to"] = "to tidak boleh sebelum from"
+	}
+
+	return from, to.AddDate(0, 0, 1), errMap
+}
+
+func newTransactionReportResponse(c *fiber.Ctx, f models.ReportFilter, rows []models.TransactionReportRow, summary *models.TransactionReportSummary) models.TransactionReportResponse {
+	if rows == nil {
+		rows = []models.TransactionReportRow{}
+	}
+	return models.TransactionReportResponse{
+		GroupBy: c.Query("group_by", "month"),
+		From:    f.From.Format(reportDateLayout),
+		To:      f.To.AddDate(0, 0, -1).Format(reportDateLayout),
+		Rows:    rows,
+		Summary: *summary,
+	}
+}
@@ -1,13 +1,20 @@
 package handlers
 
 import (
+	"errors"
 	"log"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	"warehouse-inventory-server/middleware"
 	"warehouse-inventory-server/models"
 	"warehouse-inventory-server/repositories"
+	"warehouse-inventory-server/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 )
 
 type StokHandler struct {
@@ -21,7 +28,16 @@ func NewStokHandler(repo *repositories.StokRepository) *StokHandler {
 // Route Handlers - Stock
 func (h *StokHandler) RegisterStockRoute(r fiber.Router) {
 	r.Get("/", h.GetAllStok)
+	r.Get("/low", h.GetLowStock)
+	r.Get("/alerts", h.GetStockAlerts)
+	r.Post("/snapshots", middleware.GuardAdmin(), h.CreateStockSnapshot)
+	r.Get("/reconcile", middleware.GuardAdmin(), h.CheckLedger)
+	r.Post("/reconcile", middleware.GuardAdmin(), h.RepairLedger)
+	r.Post("/alerts/:id/resolve", middleware.GuardAdmin(), h.ResolveStockAlert)
 	r.Get("/:barang_id", h.GetStokByBarangID)
+	r.Get("/:barang_id/kartu", h.GetKartuStok)
+	r.Put("/:barang_id/reorder", middleware.GuardAdmin(), h.UpdateReorderLevel)
+	r.Post("/:barang_id/adjustment", middleware.GuardAdmin(), h.AdjustStok)
 }
 
 // Route Handlers - History
@@ -32,15 +48,59 @@ func (h *StokHandler) RegisterHistoryRoute(r fiber.Router) {
 
 // GetAllStok godoc
 // @Summary Get all stock
-// @Description Get a list of all stock items
+// @Description Get a list of all stock items. Dengan as_of, stok direkonstruksi dari snapshot dan history stok pada waktu tersebut. Dengan group_by, setiap baris mendapat group_key/group_label dan response berisi total stok dan nilai stok (harga beli) per kelompok di field groups
 // @Tags Stok
 // @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param as_of query string false "Tanggal YYYY-MM-DD (stok akhir hari itu) atau waktu RFC3339"
+// @Param format query string false "json (default), csv atau xlsx"
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)"
+// @Param group_by query string false "Total per kategori, brand atau atribut (di field groups)"
+// @Param kategori_level query int false "group_by kategori: level kategori (default 1 = kategori utama)"
+// @Param atribut_key query string false "group_by atribut: nama atribut"
 // @Success 200 {object} models.MstokResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
 // @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/stok [get]
 func (h *StokHandler) GetAllStok(c *fiber.Ctx) error {
-	data, err := h.repo.GetAllStok()
+	format, err := parseExportFormat(c)
+	if err != nil {
+		return err
+	}
+	errMap := make(map[string]string)
+	filter := parseStockReportFilter(c, errMap)
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+	if c.Query("as_of") != "" {
+		return h.getStokAsOf(c, 0, format, filter)
+	}
+	if format != "" {
+		header := []string{"Barang ID", "Kode Barang", "Nama Barang", "Satuan", "Stok Akhir", "Stok Minimum", "Stok Maksimum", "Reorder Qty", "Harga Beli", "Nilai Stok", "Updated At"}
+		if filter.GroupBy != "" {
+			header = append(header, "Group")
+		}
+		return streamExport(c, format, "stok", header, func(write func(row ...any) error) error {
+			return h.repo.EachStok(filter, func(s models.StokExportRow) error {
+				row := []any{s.BarangID, s.KodeBarang, s.NamaBarang, s.Satuan, s.StokAkhir, s.StokMinimum, s.StokMaksimum,
+					s.ReorderQty, s.HargaBeli, float64(s.StokAkhir) * s.HargaBeli, s.UpdatedAt}
+				if filter.GroupBy != "" {
+					row = append(row, s.GroupLabel)
+				}
+				return write(row...)
+			})
+		})
+	}
+
+	data, err := h.repo.GetAllStok(filter)
 	if err != nil {
 		log.Println("Error fetching all stok:", err.Error(), "stok_handler.go:GetAllStok", "Error at line 43")
 		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
@@ -48,23 +108,19 @@ func (h *StokHandler) GetAllStok(c *fiber.Ctx) error {
 
 	var response []models.MstokResponse
 	for _, item := range data {
-		response = append(response, models.MstokResponse{
-			ID:        item.ID,
-			BarangID:  item.BarangID,
-			StokAkhir: item.StokAkhir,
-			UpdatedAt: item.UpdatedAt,
-			Barang: models.BarangStokResponse{
-				KodeBarang: item.MasterBarang.KodeBarang,
-				NamaBarang: item.MasterBarang.NamaBarang,
-				Satuan:     item.MasterBarang.Satuan,
-				HargaJual:  item.MasterBarang.HargaJual,
-			},
-		})
+		response = append(response, mapToMstokResponse(item))
 	}
 
-	return c.Status(200).JSON(fiber.Map{
+	result := fiber.Map{
 		"data": response,
-	})
+	}
+	if filter.GroupBy != "" {
+		result["group_by"] = filter.GroupBy
+		result["groups"] = stockReportGroups(data, func(s models.Mstok) (string, string, int64, float64) {
+			return s.GroupKey, s.GroupLabel, int64(s.StokAkhir), float64(s.StokAkhir) * s.MasterBarang.HargaBeli
+		})
+	}
+	return c.Status(200).JSON(result)
 }
 
 // GetStokByBarangID godoc
@@ -73,10 +129,12 @@ func (h *StokHandler) GetAllStok(c *fiber.Ctx) error {
 // @Tags Stok
 // @Produce json
 // @Param barang_id path int true "Barang ID"
+// @Param as_of query string false "Tanggal YYYY-MM-DD (stok akhir hari itu) atau waktu RFC3339"
 // @Success 200 {object} models.MstokResponse "OK"
 // @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
 // @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/stok/{barang_id} [get]
 func (h *StokHandler) GetStokByBarangID(c *fiber.Ctx) error {
 	barangIDStr := c.Params("barang_id")
@@ -85,104 +143,523 @@ func (h *StokHandler) GetStokByBarangID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
 	}
 
+	if c.Query("as_of") != "" {
+		return h.getStokAsOf(c, uint(barangID64), "", models.ReportFilter{})
+	}
+
 	stok, err := h.repo.GetByBarangID(uint(barangID64))
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Barang tidak ditemukan")
 	}
 
-	response := models.MstokResponse{
-		ID:        stok.ID,
-		BarangID:  stok.BarangID,
-		StokAkhir: stok.StokAkhir,
-		UpdatedAt: stok.UpdatedAt,
-		Barang: models.BarangStokResponse{
-			KodeBarang: stok.MasterBarang.KodeBarang,
-			NamaBarang: stok.MasterBarang.NamaBarang,
-			Satuan:     stok.MasterBarang.Satuan,
-			HargaJual:  stok.MasterBarang.HargaJual,
-		},
-	}
+	response := mapToMstokResponse(*stok)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"data": []models.MstokResponse{response},
 	})
 }
 
-// GetHistoryAll godoc
-// @Summary Get all stock history
-// @Description Get a list of stock history with pagination
-// @Tags History Stok
+// getStokAsOf mengembalikan stok seluruh barang (atau satu barang) pada waktu query as_of
+func (h *StokHandler) getStokAsOf(c *fiber.Ctx, barangID uint, format string, filter models.ReportFilter) error {
+	asOf := c.Query("as_of")
+	var before time.Time
+	if t, err := time.ParseInLocation(reportDateLayout, asOf, time.Local); err == nil {
+		before = t.AddDate(0, 0, 1) // akhir hari: seluruh pergerakan pada tanggal tersebut ikut dihitung
+	} else if t, err := time.Parse(time.RFC3339, asOf); err == nil {
+		before = t.Add(time.Microsecond)
+	} else {
+		return fiber.NewError(fiber.StatusBadRequest, "format as_of harus YYYY-MM-DD atau RFC3339")
+	}
+
+	rows, err := h.repo.GetStokAsOf(before, barangID, filter)
+	if err != nil {
+		log.Println("Error fetching stok as of:", err.Error(), "stok_handler.go:getStokAsOf")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	if barangID != 0 && len(rows) == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "Barang tidak ditemukan")
+	}
+	if format != "" {
+		header := []string{"Barang ID", "Kode Barang", "Nama Barang", "Satuan", "Harga Jual", "Stok Akhir"}
+		if filter.GroupBy != "" {
+			header = append(header, "Group")
+		}
+		return streamExport(c, format, "stok-as-of", header, func(write func(row ...any) error) error {
+			for _, row := range rows {
+				values := []any{row.BarangID, row.KodeBarang, row.NamaBarang, row.Satuan, row.HargaJual, row.Stok}
+				if filter.GroupBy != "" {
+					values = append(values, row.GroupLabel)
+				}
+				if err := write(values...); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	response := make([]models.StokAsOfResponse, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, models.StokAsOfResponse{
+			BarangID:   row.BarangID,
+			StokAkhir:  row.Stok,
+			GroupKey:   row.GroupKey,
+			GroupLabel: row.GroupLabel,
+			Barang: models.BarangStokResponse{
+				KodeBarang: row.KodeBarang,
+				NamaBarang: row.NamaBarang,
+				Satuan:     row.Satuan,
+				HargaJual:  row.HargaJual,
+			},
+		})
+	}
+
+	result := fiber.Map{
+		"as_of": asOf,
+		"data":  response,
+	}
+	if filter.GroupBy != "" {
+		result["group_by"] = filter.GroupBy
+		result["groups"] = stockReportGroups(rows, func(r models.StokAsOfRow) (string, string, int64, float64) {
+			return r.GroupKey, r.GroupLabel, int64(r.Stok), float64(r.Stok) * r.HargaBeli
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// CreateStockSnapshot godoc
+// @Summary Create stock snapshot
+// @Description Menyimpan snapshot stok seluruh barang sekarang (Admin only). Snapshot juga dibuat otomatis setiap STOCK_SNAPSHOT_INTERVAL_HOURS dan dipakai untuk mempercepat query as_of
+// @Tags Stok
 // @Produce json
-// @Param page query int false "Page number"
-// @Param limit query int false "Items per page"
-// @Success 200 {object} models.HistoryStokResponse "OK"
+// @Success 201 {object} models.StockSnapshotResponse "Created"
 // @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
 // @Security BearerAuth
-// @Router /api/history-stok [get]
-func (h *StokHandler) GetHistoryAll(c *fiber.Ctx) error {
+// @Security ApiKeyAuth
+// @Router /api/stok/snapshots [post]
+func (h *StokHandler) CreateStockSnapshot(c *fiber.Ctx) error {
+	at := time.Now().Add(-time.Minute)
+	count, err := h.repo.CreateSnapshot(at)
+	if err != nil {
+		log.Println("Error creating stock snapshot:", err.Error(), "stok_handler.go:CreateStockSnapshot")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.StockSnapshotResponse{
+		SnapshotAt: at,
+		JumlahItem: count,
+	})
+}
+
+// CheckLedger godoc
+// @Summary Check stock ledger
+// @Description Rekonsiliasi ledger tanpa perubahan data (Admin only): chain break (stok_sebelum tidak sama dengan stok_sesudah baris sebelumnya), row mismatch (stok_sesudah tidak sama dengan stok_sebelum +/- jumlah) dan drift (stok_akhir tidak sama dengan history terakhir)
+// @Tags Stok
+// @Produce json
+// @Param barang_id query int false "Hanya barang ini"
+// @Success 200 {object} models.LedgerReconcileResponse "OK"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/stok/reconcile [get]
+func (h *StokHandler) CheckLedger(c *fiber.Ctx) error {
+	return h.reconcileLedger(c, false)
+}
+
+// RepairLedger godoc
+// @Summary Repair stock ledger
+// @Description Rekonsiliasi ledger dan membuat history "adjustment" sebagai koreksi untuk chain break dan drift (Admin only). Row mismatch hanya dilaporkan
+// @Tags Stok
+// @Produce json
+// @Param barang_id query int false "Hanya barang ini"
+// @Success 200 {object} models.LedgerReconcileResponse "OK"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/stok/reconcile [post]
+func (h *StokHandler) RepairLedger(c *fiber.Ctx) error {
+	return h.reconcileLedger(c, true)
+}
+
+func (h *StokHandler) reconcileLedger(c *fiber.Ctx, fix bool) error {
+	barangID, err := strconv.ParseUint(c.Query("barang_id", "0"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	result, err := h.repo.ReconcileLedger(uint(barangID), fix, claimsUserID(c), claimsAPIKeyID(c))
+	if err != nil {
+		log.Println("Error reconciling stock ledger:", err.Error(), "stok_handler.go:reconcileLedger")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// GetLowStock godoc
+// @Summary Get low stock items
+// @Description Daftar barang dengan stok di bawah atau sama dengan titik reorder (stok_minimum, atau LOW_STOCK_THRESHOLD jika belum diset) beserta saran jumlah pesan. Dengan group_by, response berisi jumlah barang dan saran pesan per kelompok di field groups
+// @Tags Stok
+// @Produce json
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)"
+// @Param group_by query string false "Total per kategori, brand atau atribut (di field groups)"
+// @Param kategori_level query int false "group_by kategori: level kategori (default 1 = kategori utama)"
+// @Param atribut_key query string false "group_by atribut: nama atribut"
+// @Success 200 {object} models.LowStockResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/stok/low [get]
+func (h *StokHandler) GetLowStock(c *fiber.Ctx) error {
+	errMap := make(map[string]string)
+	filter := parseStockReportFilter(c, errMap)
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	threshold := utils.LowStockThreshold()
+	data, err := h.repo.GetLowStock(threshold, filter)
+	if err != nil {
+		log.Println("Error fetching low stock:", err.Error(), "stok_handler.go:GetLowStock")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	response := make([]models.LowStockResponse, 0, len(data))
+	for _, item := range data {
+		response = append(response, models.LowStockResponse{
+			BarangID:     item.BarangID,
+			StokAkhir:    item.StokAkhir,
+			ReorderPoint: item.ReorderPoint(threshold),
+			StokMaksimum: item.StokMaksimum,
+			ReorderQty:   item.ReorderQty,
+			SaranPesan:   saranPesan(item),
+			GroupKey:     item.GroupKey,
+			GroupLabel:   item.GroupLabel,
+			Barang: models.BarangStokResponse{
+				KodeBarang: item.MasterBarang.KodeBarang,
+				NamaBarang: item.MasterBarang.NamaBarang,
+				Satuan:     item.MasterBarang.Satuan,
+				HargaJual:  item.MasterBarang.HargaJual,
+			},
+		})
+	}
+
+	result := fiber.Map{
+		"data": response,
+	}
+	if filter.GroupBy != "" {
+		// total_qty berisi jumlah saran pesan, total_nilai nilainya pada harga beli
+		result["group_by"] = filter.GroupBy
+		result["groups"] = stockReportGroups(data, func(s models.Mstok) (string, string, int64, float64) {
+			return s.GroupKey, s.GroupLabel, int64(saranPesan(s)), float64(saranPesan(s)) * s.MasterBarang.HargaBeli
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// saranPesan: isi sampai stok maksimum, atau reorder qty jika stok maksimum belum diset
+func saranPesan(item models.Mstok) int {
+	if item.StokMaksimum > 0 {
+		return max(item.StokMaksimum-item.StokAkhir, 0)
+	}
+	return item.ReorderQty
+}
+
+// UpdateReorderLevel godoc
+// @Summary Update reorder level
+// @Description Mengatur stok minimum (titik reorder), stok maksimum dan reorder qty untuk barang (Admin only)
+// @Tags Stok
+// @Accept json
+// @Produce json
+// @Param barang_id path int true "Barang ID"
+// @Param body body models.ReorderLevelRequest true "Reorder Level Request"
+// @Success 200 {object} models.MstokResponse "OK"
+// @Failure 404 {object} middleware.ErrorResponse "Not Found"
+// @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/stok/{barang_id}/reorder [put]
+func (h *StokHandler) UpdateReorderLevel(c *fiber.Ctx) error {
+	barangID64, err := strconv.ParseUint(c.Params("barang_id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	var req models.ReorderLevelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	errMap := make(map[string]string)
 
-	// Note: Pagination tidak ada di requirement. Namun, untuk frontend agar tidak load data terlalu banyak.
+	switch {
+	case req.StokMinimum < 0:
+		errMap["stok_minimum"] = "stok minimum tidak boleh kurang dari 0"
+	case req.StokMaksimum < 0:
+		errMap["stok_maksimum"] = "stok maksimum tidak boleh kurang dari 0"
+	case req.ReorderQty < 0:
+		errMap["reorder_qty"] = "reorder qty tidak boleh kurang dari 0"
+	case req.StokMaksimum > 0 && req.StokMaksimum < req.StokMinimum:
+		errMap["stok_maksimum"] = "stok maksimum tidak boleh lebih kecil dari stok minimum"
+	}
+
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	if err := h.repo.UpdateReorderLevel(uint(barangID64), req); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Barang tidak ditemukan")
+		}
+		log.Println("Error updating reorder level:", err.Error(), "stok_handler.go:UpdateReorderLevel")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	stok, err := h.repo.GetByBarangID(uint(barangID64))
+	if err != nil {
+		log.Println("Error fetching updated stok:", err.Error(), "stok_handler.go:UpdateReorderLevel")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(mapToMstokResponse(*stok))
+}
+
+// AdjustStok godoc
+// @Summary Adjust stock
+// @Description Koreksi stok manual (stock opname). Jumlah positif menambah stok, negatif mengurangi stok. Dicatat di history stok sebagai "adjustment" (Admin only)
+// @Tags Stok
+// @Accept json
+// @Produce json
+// @Param barang_id path int true "Barang ID"
+// @Param body body models.StokAdjustmentRequest true "Stok Adjustment Request"
+// @Success 201 {object} models.HistoryStokResponse "Created"
+// @Failure 400 {object} middleware.ErrorResponse "Bad Request"
+// @Failure 404 {object} middleware.ErrorResponse "Not Found"
+// @Failure 422 {object} middleware.ValidationError "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/stok/{barang_id}/adjustment [post]
+func (h *StokHandler) AdjustStok(c *fiber.Ctx) error {
+	barangID64, err := strconv.ParseUint(c.Params("barang_id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	var req models.StokAdjustmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	errMap := make(map[string]string)
+
+	switch {
+	case req.Jumlah == 0:
+		errMap["jumlah"] = "jumlah tidak boleh 0"
+	case strings.TrimSpace(req.Keterangan) == "":
+		errMap["keterangan"] = "keterangan tidak boleh kosong"
+	}
+
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+
+	history, err := h.repo.AdjustStok(uint(barangID64), claimsUserID(c), claimsAPIKeyID(c), req.Jumlah, req.Keterangan)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return fiber.NewError(fiber.StatusNotFound, "Barang tidak ditemukan")
+		case errors.Is(err, repositories.ErrStokTidakMencukupi):
+			return fiber.NewError(fiber.StatusBadRequest, "Stok tidak mencukupi")
+		}
+		log.Println("Error adjusting stok:", err.Error(), "stok_handler.go:AdjustStok")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
 
-	pageStr := c.Query("page", "1")
-	limitStr := c.Query("limit", "10")
+	return c.Status(fiber.StatusCreated).JSON(mapToHistoryStokResponse(*history))
+}
 
-	page, err := strconv.Atoi(pageStr)
+// GetStockAlerts godoc
+// @Summary Get stock alerts
+// @Description Daftar stock alert yang dibuat ketika penjualan atau adjustment membuat stok turun melewati titik reorder
+// @Tags Stok
+// @Produce json
+// @Param status query string false "open (default) atau all"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param kategori_id query int false "Filter kategori (termasuk sub kategori)"
+// @Param brand_id query int false "Filter brand"
+// @Param atribut query string false "Filter atribut nama:nilai, pisahkan dengan koma (misal warna:hitam)"
+// @Param group_by query string false "Total per kategori, brand atau atribut (di field groups)"
+// @Param kategori_level query int false "group_by kategori: level kategori (default 1 = kategori utama)"
+// @Param atribut_key query string false "group_by atribut: nama atribut"
+// @Success 200 {object} models.StockAlertResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/stok/alerts [get]
+func (h *StokHandler) GetStockAlerts(c *fiber.Ctx) error {
+	page, err := strconv.Atoi(c.Query("page", "1"))
 	if err != nil || page < 1 {
 		page = 1
 	}
-	limit, err := strconv.Atoi(limitStr)
+	limit, err := strconv.Atoi(c.Query("limit", "10"))
 	if err != nil || limit < 1 {
 		limit = 10
 	}
+	openOnly := c.Query("status", "open") != "all"
+	errMap := make(map[string]string)
+	filter := parseStockReportFilter(c, errMap)
+	if len(errMap) > 0 {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
 
-	offset := (page - 1) * limit
-	data, total, err := h.repo.GetHistory(0, limit, offset)
+	data, total, err := h.repo.GetAlerts(openOnly, filter, limit, (page-1)*limit)
 	if err != nil {
-		log.Println("Error fetching all history stok:", err.Error(), "stok_handler.go:GetHistoryAll", "Error at line 150")
+		log.Println("Error fetching stock alerts:", err.Error(), "stok_handler.go:GetStockAlerts")
 		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
 	}
 
-	var response []models.HistoryStokResponse
+	response := make([]models.StockAlertResponse, 0, len(data))
 	for _, item := range data {
-		response = append(response, models.HistoryStokResponse{
-			ID:             item.ID,
-			BarangID:       item.BarangID,
-			UserID:         item.UserID,
-			JenisTransaksi: item.JenisTransaksi,
-			Jumlah:         item.Jumlah,
-			StokSebelum:    item.StokSebelum,
-			StokSesudah:    item.StokSesudah,
-			Keterangan:     item.Keterangan,
-			CreatedAt:      item.CreatedAt,
+		response = append(response, models.StockAlertResponse{
+			ID:           item.ID,
+			BarangID:     item.BarangID,
+			StokSebelum:  item.StokSebelum,
+			StokSesudah:  item.StokSesudah,
+			ReorderPoint: item.ReorderPoint,
+			Keterangan:   item.Keterangan,
+			ResolvedAt:   item.ResolvedAt,
+			CreatedAt:    item.CreatedAt,
+			GroupKey:     item.GroupKey,
+			GroupLabel:   item.GroupLabel,
 			Barang: models.BarangSimpleResponse{
 				KodeBarang: item.MasterBarang.KodeBarang,
 				NamaBarang: item.MasterBarang.NamaBarang,
 			},
-			User: models.UserSimpleResponse{
-				Username: item.Users.Username,
-				FullName: item.Users.FullName,
-			},
 		})
 	}
 
-	return c.Status(200).JSON(fiber.Map{
-		"data":  response,
-		"total": total,
+	result := fiber.Map{
+		"data": response,
+		"meta": paginationMeta(page, limit, total),
+	}
+	if filter.GroupBy != "" {
+		// Group dihitung dari seluruh alert yang cocok dengan filter, bukan hanya halaman ini
+		groups, err := h.repo.AlertGroups(openOnly, filter)
+		if err != nil {
+			log.Println("Error fetching stock alert groups:", err.Error(), "stok_handler.go:GetStockAlerts")
+			return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+		}
+		result["group_by"] = filter.GroupBy
+		result["groups"] = groups
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// ResolveStockAlert godoc
+// @Summary Resolve stock alert
+// @Description Menandai stock alert sebagai sudah ditangani (Admin only). Alert juga otomatis ditutup ketika stok kembali di atas titik reorder
+// @Tags Stok
+// @Produce json
+// @Param id path int true "Stock Alert ID"
+// @Success 200 {object} map[string]interface{} "OK"
+// @Failure 404 {object} middleware.ErrorResponse "Not Found"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/stok/alerts/{id}/resolve [post]
+func (h *StokHandler) ResolveStockAlert(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	if err := h.repo.ResolveAlert(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Stock alert tidak ditemukan atau sudah ditangani")
+		}
+		log.Println("Error resolving stock alert:", err.Error(), "stok_handler.go:ResolveStockAlert")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Stock alert berhasil ditandai selesai",
 	})
 }
 
+// GetHistoryAll godoc
+// @Summary Get all stock history
+// @Description Get a list of stock history with filters, sorting and pagination
+// @Tags History Stok
+// @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param page query int false "Page number (mode page/limit)"
+// @Param limit query int false "Items per page (default 10, max 100)"
+// @Param cursor query string false "Cursor dari meta.next_cursor; kirim kosong (?cursor=) untuk halaman pertama mode cursor (hanya sort created_at)"
+// @Param format query string false "json (default), csv atau xlsx. csv/xlsx mengunduh seluruh hasil filter tanpa paginasi"
+// @Param jenis_transaksi query string false "masuk, keluar, adjustment (pisahkan dengan koma untuk lebih dari satu)"
+// @Param user_id query int false "Filter user"
+// @Param from query string false "Tanggal awal YYYY-MM-DD"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif"
+// @Param search query string false "Cari di keterangan / no faktur"
+// @Param sort query string false "created_at (default), jumlah, jenis_transaksi, barang_id"
+// @Param order query string false "desc (default) atau asc"
+// @Success 200 {object} models.HistoryStokResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/history-stok [get]
+func (h *StokHandler) GetHistoryAll(c *fiber.Ctx) error {
+	return h.getHistory(c, 0)
+}
+
 // GetHistoryByBarangID godoc
 // @Summary Get stock history by barang ID
-// @Description Get stock history for a specific barang with pagination
+// @Description Get stock history for a specific barang with filters, sorting and pagination
 // @Tags History Stok
 // @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param barang_id path int true "Barang ID"
-// @Param page query int false "Page number"
-// @Param limit query int false "Items per page"
+// @Param page query int false "Page number (mode page/limit)"
+// @Param limit query int false "Items per page (default 10, max 100)"
+// @Param cursor query string false "Cursor dari meta.next_cursor; kirim kosong (?cursor=) untuk halaman pertama mode cursor (hanya sort created_at)"
+// @Param format query string false "json (default), csv atau xlsx. csv/xlsx mengunduh seluruh hasil filter tanpa paginasi"
+// @Param jenis_transaksi query string false "masuk, keluar, adjustment (pisahkan dengan koma untuk lebih dari satu)"
+// @Param user_id query int false "Filter user"
+// @Param from query string false "Tanggal awal YYYY-MM-DD"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif"
+// @Param search query string false "Cari di keterangan / no faktur"
+// @Param sort query string false "created_at (default), jumlah, jenis_transaksi, barang_id"
+// @Param order query string false "desc (default) atau asc"
 // @Success 200 {object} models.HistoryStokResponse "OK"
 // @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
 // @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/history-stok/{barang_id} [get]
 func (h *StokHandler) GetHistoryByBarangID(c *fiber.Ctx) error {
 	barangIDStr := c.Params("barang_id")
@@ -198,54 +675,174 @@ func (h *StokHandler) GetHistoryByBarangID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Barang tidak ditemukan")
 	}
 
-	pageStr := c.Query("page", "1")
-	limitStr := c.Query("limit", "10")
+	return h.getHistory(c, uint(barangID64))
+}
 
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
+// getHistory membaca filter dari query dan mengembalikan history stok dengan metadata paginasi yang sama
+// untuk semua barang maupun satu barang
+func (h *StokHandler) getHistory(c *fiber.Ctx, barangID uint) error {
+	page, limit := parsePagination(c)
+
+	filter, err := parseHistoryFilter(c)
+	if err != nil {
+		return err
 	}
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 {
-		limit = 10
+	filter.Cursor, err = parseCursor(c)
+	if err != nil {
+		return err
+	}
+	if filter.Cursor != nil && filter.Sort != "created_at" {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  map[string]string{"sort": "mode cursor hanya mendukung sort created_at"},
+		}
+	}
+	filter.BarangID = barangID
+
+	format, err := parseExportFormat(c)
+	if err != nil {
+		return err
+	}
+	if format != "" {
+		header := []string{"ID", "Tanggal", "Kode Barang", "Nama Barang", "Jenis Transaksi", "Jumlah", "Stok Sebelum", "Stok Sesudah", "Keterangan", "User"}
+		return streamExport(c, format, "history-stok", header, func(write func(row ...any) error) error {
+			return h.repo.EachHistory(filter, func(r models.HistoryStokExportRow) error {
+				return write(r.ID, r.CreatedAt, r.KodeBarang, r.NamaBarang, r.JenisTransaksi, r.Jumlah, r.StokSebelum, r.StokSesudah, r.Keterangan, r.Username)
+			})
+		})
 	}
 
-	offset := (page - 1) * limit
-	data, total, err := h.repo.GetHistory(uint(barangID64), limit, offset)
+	filter.Limit = limit + 1
+	filter.Offset = (page - 1) * limit
+
+	data, total, err := h.repo.GetHistory(filter)
 	if err != nil {
-		log.Println("Error fetching history by barang ID:", err.Error(), "stok_handler.go:GetHistoryByBarangID", "Error at line 219")
+		log.Println("Error fetching history stok:", err.Error(), "stok_handler.go:getHistory")
 		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
 	}
+	fetched := len(data)
+	data = data[:min(fetched, limit)]
 
-	var response []models.HistoryStokResponse
+	response := make([]models.HistoryStokResponse, 0, len(data))
 	for _, item := range data {
-		response = append(response, models.HistoryStokResponse{
-			ID:             item.ID,
-			BarangID:       item.BarangID,
-			UserID:         item.UserID,
-			JenisTransaksi: item.JenisTransaksi,
-			Jumlah:         item.Jumlah,
-			StokSebelum:    item.StokSebelum,
-			StokSesudah:    item.StokSesudah,
-			Keterangan:     item.Keterangan,
-			CreatedAt:      item.CreatedAt,
-			Barang: models.BarangSimpleResponse{
-				KodeBarang: item.MasterBarang.KodeBarang,
-				NamaBarang: item.MasterBarang.NamaBarang,
-			},
-			User: models.UserSimpleResponse{
-				Username: item.Users.Username,
-				FullName: item.Users.FullName,
-			},
-		})
+		response = append(response, mapToHistoryStokResponse(item))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"data": response,
-		"meta": fiber.Map{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
+	meta := listMeta(filter.Cursor, page, limit, total, fetched, func() string {
+		last := data[len(data)-1]
+		return utils.EncodeTimeCursor(last.CreatedAt, last.ID)
 	})
+	result := fiber.Map{"data": response, "meta": meta}
+	if filter.Cursor == nil {
+		result["total"] = total // deprecated: gunakan meta.total
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// parseHistoryFilter membaca query jenis_transaksi, user_id, from, to, search, sort dan order
+func parseHistoryFilter(c *fiber.Ctx) (models.HistoryStokFilter, error) {
+	errMap := make(map[string]string)
+	var f models.HistoryStokFilter
+
+	if v := c.Query("jenis_transaksi"); v != "" {
+		for _, jenis := range strings.Split(v, ",") {
+			jenis = strings.TrimSpace(jenis)
+			if !slices.Contains([]string{"masuk", "keluar", "adjustment"}, jenis) {
+				errMap["jenis_transaksi"] = "jenis_transaksi harus masuk, keluar atau adjustment"
+				break
+			}
+			f.JenisTransaksi = append(f.JenisTransaksi, jenis)
+		}
+	}
+
+	userID, err := strconv.ParseUint(c.Query("user_id", "0"), 10, 64)
+	if err != nil {
+		errMap["user_id"] = "user_id tidak valid"
+	}
+	f.UserID = uint(userID)
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.ParseInLocation(reportDateLayout, v, time.Local)
+		if err != nil {
+			errMap["from"] = "format from harus YYYY-MM-DD"
+		}
+		f.From = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.ParseInLocation(reportDateLayout, v, time.Local)
+		if err != nil {
+			errMap["to"] = "format to harus YYYY-MM-DD"
+		} else {
+			f.To = t.AddDate(0, 0, 1)
+		}
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.To.After(f.From) {
+		errMap["to"] = "to tidak boleh sebelum from"
+	}
+
+	f.Search = strings.TrimSpace(c.Query("search"))
+
+	f.Sort = c.Query("sort", "created_at")
+	if !slices.Contains([]string{"created_at", "jumlah", "jenis_transaksi", "barang_id"}, f.Sort) {
+		errMap["sort"] = "sort harus salah satu dari created_at, jumlah, jenis_transaksi, barang_id"
+	}
+	order := c.Query("order", "desc")
+	if order != "asc" && order != "desc" {
+		errMap["order"] = "order harus asc atau desc"
+	}
+	f.Desc = order == "desc"
+
+	if len(errMap) > 0 {
+		return f, &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  errMap,
+		}
+	}
+	return f, nil
+}
+
+// Private helper functions untuk mapping struct response
+func mapToMstokResponse(item models.Mstok) models.MstokResponse {
+	return models.MstokResponse{
+		ID:           item.ID,
+		BarangID:     item.BarangID,
+		StokAkhir:    item.StokAkhir,
+		StokMinimum:  item.StokMinimum,
+		StokMaksimum: item.StokMaksimum,
+		ReorderQty:   item.ReorderQty,
+		UpdatedAt:    item.UpdatedAt,
+		GroupKey:     item.GroupKey,
+		GroupLabel:   item.GroupLabel,
+		Barang: models.BarangStokResponse{
+			KodeBarang: item.MasterBarang.KodeBarang,
+			NamaBarang: item.MasterBarang.NamaBarang,
+			Satuan:     item.MasterBarang.Satuan,
+			HargaJual:  item.MasterBarang.HargaJual,
+		},
+	}
+}
+
+func mapToHistoryStokResponse(item models.HistoryStok) models.HistoryStokResponse {
+	return models.HistoryStokResponse{
+		ID:             item.ID,
+		BarangID:       item.BarangID,
+		UserID:         item.UserID,
+		JenisTransaksi: item.JenisTransaksi,
+		Jumlah:         item.Jumlah,
+		StokSebelum:    item.StokSebelum,
+		StokSesudah:    item.StokSesudah,
+		Keterangan:     item.Keterangan,
+		APIKeyID:       item.APIKeyID,
+		RefType:        item.RefType,
+		RefID:          item.RefID,
+		CreatedAt:      item.CreatedAt,
+		Barang: models.BarangSimpleResponse{
+			KodeBarang: item.MasterBarang.KodeBarang,
+			NamaBarang: item.MasterBarang.NamaBarang,
+		},
+		User: models.UserSimpleResponse{
+			Username: item.Users.Username,
+			FullName: item.Users.FullName,
+		},
+	}
 }
@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"warehouse-inventory-server/middleware"
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const backupCodeCount = 10
+
+// LoginTwoFactor godoc
+// @Summary Complete login with 2FA code
+// @Description Menukar challenge token (dikirim sebagai Bearer token) dan kode TOTP atau backup code dengan JWT akses
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.TwoFactorCodeRequest true "2FA Code"
+// @Success 200 {object} models.LoginResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
+// @Failure 423 {object} middleware.ErrorResponse "Locked"
+// @Failure 429 {object} middleware.ErrorResponse "Too Many Requests"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Router /api/auth/login/2fa [post]
+func (h *UserHandler) LoginTwoFactor(c *fiber.Ctx) error {
+	var req models.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+	code := strings.TrimSpace(req.Code)
+	if code == "" {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  map[string]string{"code": "kode 2FA tidak boleh kosong"},
+		}
+	}
+
+	user, err := h.repo.FindByID(claimsUserID(c))
+	if err != nil || !user.TOTPEnabled {
+		return fiber.NewError(fiber.StatusUnauthorized, "Token tidak valid")
+	}
+
+	if err := h.checkSecondFactor(c, user, code, "two_factor_handler.go:LoginTwoFactor"); err != nil {
+		return err
+	}
+
+	h.completeLogin(c, user)
+
+	signedToken, err := h.issueToken(user, middleware.TokenTypeAccess, accessTokenTTL)
+	if err != nil {
+		log.Println("Error signing JWT token during 2FA login:", err.Error(), "two_factor_handler.go:LoginTwoFactor", "Error at line 76")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.LoginResponse{
+		Token: signedToken,
+	})
+}
+
+// EnrollTwoFactor godoc
+// @Summary Start 2FA enrollment
+// @Description Membuat secret TOTP baru dan mengembalikan otpauth URI. 2FA baru aktif setelah kode diverifikasi di /api/auth/2fa/activate
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} models.TwoFactorEnrollResponse "OK"
+// @Failure 400 {object} middleware.ErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Router /api/auth/2fa/enroll [post]
+func (h *UserHandler) EnrollTwoFactor(c *fiber.Ctx) error {
+	user, err := h.repo.FindByID(claimsUserID(c))
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Token tidak valid")
+	}
+	if user.TOTPEnabled {
+		return fiber.NewError(fiber.StatusBadRequest, "2FA sudah aktif")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		log.Println("Error generating TOTP secret:", err.Error(), "two_factor_handler.go:EnrollTwoFactor", "Error at line 106")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	if err := h.repo.SaveTOTPSecret(user.ID, secret); err != nil {
+		log.Println("Error saving TOTP secret:", err.Error(), "two_factor_handler.go:EnrollTwoFactor", "Error at line 110")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.TwoFactorEnrollResponse{
+		Secret:     secret,
+		OtpauthURI: utils.TOTPURI(totpIssuer(), user.Email, secret),
+	})
+}
+
+// ActivateTwoFactor godoc
+// @Summary Activate 2FA
+// @Description Memverifikasi kode TOTP pertama, mengaktifkan 2FA dan mengembalikan backup code (hanya ditampilkan sekali)
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.TwoFactorCodeRequest true "TOTP Code"
+// @Success 200 {object} models.TwoFactorActivateResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Router /api/auth/2fa/activate [post]
+func (h *UserHandler) ActivateTwoFactor(c *fiber.Ctx) error {
+	var req models.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	user, err := h.repo.FindByID(claimsUserID(c))
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Token tidak valid")
+	}
+	if user.TOTPEnabled {
+		return fiber.NewError(fiber.StatusBadRequest, "2FA sudah aktif")
+	}
+	if user.TOTPSecret == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Lakukan enrollment 2FA terlebih dahulu")
+	}
+
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, req.Code, time.Now())
+	if !ok {
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  map[string]string{"code": "Kode 2FA salah"},
+		}
+	}
+
+	codes, hashes, err := newBackupCodes()
+	if err != nil {
+		log.Println("Error generating backup codes:", err.Error(), "two_factor_handler.go:ActivateTwoFactor", "Error at line 157")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	if err := h.repo.EnableTOTP(user.ID, step, hashes); err != nil {
+		log.Println("Error enabling 2FA:", err.Error(), "two_factor_handler.go:ActivateTwoFactor", "Error at line 161")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.TwoFactorActivateResponse{
+		Message:     "2FA berhasil diaktifkan. Simpan backup code di tempat yang aman lalu login kembali.",
+		BackupCodes: codes,
+	})
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable 2FA
+// @Description Menonaktifkan 2FA milik user yang sedang login dengan kode TOTP atau backup code. Tidak bisa dilakukan jika 2FA diwajibkan
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.TwoFactorCodeRequest true "TOTP Code"
+// @Success 200 {object} models.TwoFactorMessageResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 403 {object} middleware.ErrorResponse "Forbidden"
+// @Failure 423 {object} middleware.ErrorResponse "Locked"
+// @Failure 429 {object} middleware.ErrorResponse "Too Many Requests"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Router /api/auth/2fa/disable [post]
+func (h *UserHandler) DisableTwoFactor(c *fiber.Ctx) error {
+	var req models.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	user, err := h.repo.FindByID(claimsUserID(c))
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Token tidak valid")
+	}
+	if !user.TOTPEnabled {
+		return fiber.NewError(fiber.StatusBadRequest, "2FA belum aktif")
+	}
+	if h.twoFactorRequired(user) {
+		return fiber.NewError(fiber.StatusForbidden, "2FA wajib untuk akun ini dan tidak dapat dinonaktifkan")
+	}
+
+	if err := h.checkSecondFactor(c, user, strings.TrimSpace(req.Code), "two_factor_handler.go:DisableTwoFactor"); err != nil {
+		return err
+	}
+
+	if err := h.repo.DisableTOTP(user.ID); err != nil {
+		log.Println("Error disabling 2FA:", err.Error(), "two_factor_handler.go:DisableTwoFactor", "Error at line 209")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.TwoFactorMessageResponse{
+		Message: "2FA berhasil dinonaktifkan",
+	})
+}
+
+// RegenerateBackupCodes godoc
+// @Summary Regenerate 2FA backup codes
+// @Description Mengganti seluruh backup code lama dengan yang baru (hanya ditampilkan sekali). Membutuhkan kode TOTP atau backup code
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.TwoFactorCodeRequest true "TOTP Code"
+// @Success 200 {object} models.TwoFactorActivateResponse "OK"
+// @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
+// @Failure 423 {object} middleware.ErrorResponse "Locked"
+// @Failure 429 {object} middleware.ErrorResponse "Too Many Requests"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Router /api/auth/2fa/backup-codes [post]
+func (h *UserHandler) RegenerateBackupCodes(c *fiber.Ctx) error {
+	var req models.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	user, err := h.repo.FindByID(claimsUserID(c))
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "Token tidak valid")
+	}
+	if !user.TOTPEnabled {
+		return fiber.NewError(fiber.StatusBadRequest, "2FA belum aktif")
+	}
+	if err := h.checkSecondFactor(c, user, strings.TrimSpace(req.Code), "two_factor_handler.go:RegenerateBackupCodes"); err != nil {
+		return err
+	}
+
+	codes, hashes, err := newBackupCodes()
+	if err != nil {
+		log.Println("Error generating backup codes:", err.Error(), "two_factor_handler.go:RegenerateBackupCodes", "Error at line 252")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	if err := h.repo.ReplaceBackupCodes(user.ID, hashes); err != nil {
+		log.Println("Error replacing backup codes:", err.Error(), "two_factor_handler.go:RegenerateBackupCodes", "Error at line 256")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.TwoFactorActivateResponse{
+		Message:     "Backup code berhasil dibuat ulang",
+		BackupCodes: codes,
+	})
+}
+
+// SetTwoFactorRequirement godoc
+// @Summary Set 2FA requirement for a user (Admin only)
+// @Description Mewajibkan atau membebaskan user dari 2FA. Jika wajib, user harus enrollment 2FA saat login berikutnya
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param body body models.TwoFactorRequirementRequest true "Requirement"
+// @Success 200 {object} models.TwoFactorMessageResponse "OK"
+// @Failure 404 {object} middleware.ErrorResponse "Not Found"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Router /api/auth/users/{id}/2fa-requirement [put]
+func (h *UserHandler) SetTwoFactorRequirement(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	var req models.TwoFactorRequirementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	user, err := h.repo.FindByID(uint(id))
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "User tidak ditemukan")
+	}
+
+	if err := h.repo.SetTOTPRequired(user.ID, req.Required); err != nil {
+		log.Println("Error updating 2FA requirement:", err.Error(), "two_factor_handler.go:SetTwoFactorRequirement", "Error at line 296")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	message := "2FA tidak lagi diwajibkan untuk " + user.Username
+	if req.Required {
+		message = "2FA diwajibkan untuk " + user.Username
+	}
+	return c.Status(fiber.StatusOK).JSON(models.TwoFactorMessageResponse{
+		Message: message,
+	})
+}
+
+// twoFactorRequired mengecek apakah user wajib memakai 2FA, baik diset per user oleh admin
+// maupun untuk seluruh admin melalui env TOTP_REQUIRED_FOR_ADMIN=true
+func (h *UserHandler) twoFactorRequired(user *models.User) bool {
+	if user.TOTPRequired {
+		return true
+	}
+	return strings.ToLower(user.Role) == "admin" && os.Getenv("TOTP_REQUIRED_FOR_ADMIN") == "true"
+}
+
+// checkSecondFactor memvalidasi kode 2FA dengan throttle yang sama seperti login: akun terkunci dan jeda progresif
+// ditolak, dan kode salah menambah counter gagal login serta dicatat sebagai invalid_2fa
+func (h *UserHandler) checkSecondFactor(c *fiber.Ctx, user *models.User, code string, source string) error {
+	if err := h.checkAccountThrottle(c, user); err != nil {
+		return err
+	}
+
+	valid, err := h.verifySecondFactor(user, code)
+	if err != nil {
+		log.Println("Error verifying 2FA code:", err.Error(), source)
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	if !valid {
+		if err := h.repo.RegisterFailedLogin(user.ID, h.policy); err != nil {
+			log.Println("Error registering failed 2FA:", err.Error(), source)
+		}
+		h.recordLoginAttempt(c, &user.ID, user.Email, false, "invalid_2fa")
+		return &middleware.ValidationError{
+			Message: "validation error",
+			Errors:  map[string]string{"code": "Kode 2FA salah"},
+		}
+	}
+	return nil
+}
+
+// verifySecondFactor memvalidasi kode TOTP (menolak kode yang sudah pernah dipakai) atau backup code
+func (h *UserHandler) verifySecondFactor(user *models.User, code string) (bool, error) {
+	if code == "" {
+		return false, nil
+	}
+	if step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		if step <= user.TOTPLastStep {
+			return false, nil
+		}
+		return h.repo.UpdateTOTPLastStep(user.ID, step)
+	}
+	return h.repo.ConsumeBackupCode(user.ID, strings.ToLower(code))
+}
+
+// newBackupCodes membuat backup code baru beserta hash bcrypt-nya untuk disimpan
+func newBackupCodes() ([]string, []string, error) {
+	codes, err := utils.GenerateBackupCodes(backupCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(code), 10)
+		if err != nil {
+			return nil, nil, err
+		}
+		hashes[i] = string(hashed)
+	}
+	return codes, hashes, nil
+}
+
+func totpIssuer() string {
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "Warehouse Inventory"
+}
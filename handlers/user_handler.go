@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"log"
-	"os"
 	"regexp"
+	"strconv"
 	"time"
 
+	"warehouse-inventory-server/config"
+	"warehouse-inventory-server/mailer"
 	"warehouse-inventory-server/middleware"
 	"warehouse-inventory-server/models"
 	"warehouse-inventory-server/repositories"
@@ -18,16 +20,40 @@ import (
 
 // Route Handlers
 func (h *UserHandler) RegisterRoute(r fiber.Router) {
-	r.Post("/register", middleware.Authentication(), middleware.GuardAdmin(), h.Register) // Simple Authorization: Only admin can register new staff
+	// Endpoint admin dan 2FA hanya untuk user (JWT), tidak menerima API key
+	userAuth := middleware.AuthenticationFor(middleware.TokenTypeAccess)
+	setupAuth := middleware.AuthenticationFor(middleware.TokenTypeAccess, middleware.TokenTypeTwoFactorSetup)
+
+	r.Post("/register", userAuth, middleware.GuardAdmin(), h.Register) // Simple Authorization: Only admin can register new staff
 	r.Post("/login", h.Login)
+	r.Post("/forgot-password", middleware.PasswordResetLimiter(), h.ForgotPassword)
+	r.Post("/reset-password", h.ResetPassword)
+	r.Post("/users/:id/unlock", userAuth, middleware.GuardAdmin(), h.UnlockUser)
+	r.Get("/login-audit", userAuth, middleware.GuardAdmin(), h.GetLoginAudit)
+
+	// Two-factor authentication (TOTP)
+	r.Post("/login/2fa", middleware.AuthenticationFor(middleware.TokenTypeTwoFactorChallenge), h.LoginTwoFactor)
+	r.Post("/2fa/enroll", setupAuth, h.EnrollTwoFactor)
+	r.Post("/2fa/activate", setupAuth, h.ActivateTwoFactor)
+	r.Post("/2fa/disable", userAuth, h.DisableTwoFactor)
+	r.Post("/2fa/backup-codes", userAuth, h.RegenerateBackupCodes)
+	r.Put("/users/:id/2fa-requirement", userAuth, middleware.GuardAdmin(), h.SetTwoFactorRequirement)
 }
 
 type UserHandler struct {
-	repo *repositories.UserRepository
+	repo   *repositories.UserRepository
+	mailer mailer.Mailer
+	keys   *config.JWTKeySet
+	policy utils.LoginPolicy
 }
 
-func NewUserHandler(repo *repositories.UserRepository) *UserHandler {
-	return &UserHandler{repo: repo}
+func NewUserHandler(repo *repositories.UserRepository, mail mailer.Mailer, keys *config.JWTKeySet) *UserHandler {
+	return &UserHandler{
+		repo:   repo,
+		mailer: mail,
+		keys:   keys,
+		policy: utils.LoadLoginPolicy(),
+	}
 }
 
 // Register godoc
@@ -142,6 +168,8 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 // @Failure 400 {object} middleware.SpecificErrorResponse "Bad Request"
 // @Failure 401 {object} middleware.ErrorResponse "Unauthorized"
 // @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 423 {object} middleware.ErrorResponse "Locked"
+// @Failure 429 {object} middleware.ErrorResponse "Too Many Requests"
 // @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
 // @Router /api/auth/login [post]
 func (h *UserHandler) Login(c *fiber.Ctx) error {
@@ -181,11 +209,24 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 		}
 	}
 
+	// Proteksi per IP: blokir IP yang terlalu banyak gagal login dalam jendela waktu tertentu
+	ipFailures, err := h.repo.CountFailedLoginsByIP(c.IP(), time.Now().Add(-h.policy.IPWindow))
+	if err != nil {
+		log.Println("Error counting failed login by IP:", err.Error(), "user_handler.go:Login", "Error at line 186")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+	if ipFailures >= int64(h.policy.IPMaxAttempts) {
+		h.recordLoginAttempt(c, nil, req.Email, false, "ip_blocked")
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(h.policy.IPWindow.Seconds())))
+		return fiber.NewError(fiber.StatusTooManyRequests, "Terlalu banyak percobaan login gagal. Silakan coba lagi nanti.")
+	}
+
 	// Authenticate user
 	user, err := h.repo.FindByEmail(req.Email)
 	if err != nil || user == nil {
 		// Jangan lanjut ke pengecekan password jika user tidak ditemukan
 		// Agar tidak terjadi panic nil pointer dan pesan tetap generic
+		h.recordLoginAttempt(c, nil, req.Email, false, "invalid_credentials")
 		return &middleware.ValidationError{
 			Message: "validation error",
 			Errors: map[string]string{
@@ -195,7 +236,15 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 		}
 	}
 
+	if err := h.checkAccountThrottle(c, user); err != nil {
+		return err
+	}
+
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		if err := h.repo.RegisterFailedLogin(user.ID, h.policy); err != nil {
+			log.Println("Error registering failed login:", err.Error(), "user_handler.go:Login", "Error at line 230")
+		}
+		h.recordLoginAttempt(c, &user.ID, req.Email, false, "invalid_credentials")
 		return &middleware.ValidationError{
 			Message: "validation error",
 			Errors: map[string]string{
@@ -205,28 +254,37 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 		}
 	}
 
-	if len(errMap) > 0 {
-		return &middleware.ValidationError{
-			Message: "validation error",
-			Errors:  errMap,
+	// Akun dengan 2FA aktif: counter gagal login baru di-reset setelah kode 2FA valid
+	if user.TOTPEnabled {
+		challengeToken, err := h.issueToken(user, middleware.TokenTypeTwoFactorChallenge, twoFactorChallengeTTL)
+		if err != nil {
+			log.Println("Error signing 2FA challenge token:", err.Error(), "user_handler.go:Login", "Error at line 253")
+			return fiber.NewError(fiber.StatusInternalServerError, "Server error")
 		}
+		return c.Status(fiber.StatusOK).JSON(models.LoginResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		})
 	}
 
-	// JWT generation
-	secret := os.Getenv("JWT_SECRET")
+	h.completeLogin(c, user)
 
-	claims := jwt.MapClaims{
-		"id":    user.ID,
-		"email": user.Email,
-		"role":  user.Role,
-		"exp":   time.Now().Add(24 * time.Hour).Unix(),
+	// 2FA diwajibkan tetapi belum diaktifkan: hanya terbitkan token untuk enrollment
+	if h.twoFactorRequired(user) {
+		setupToken, err := h.issueToken(user, middleware.TokenTypeTwoFactorSetup, twoFactorSetupTTL)
+		if err != nil {
+			log.Println("Error signing 2FA setup token:", err.Error(), "user_handler.go:Login", "Error at line 268")
+			return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+		}
+		return c.Status(fiber.StatusOK).JSON(models.LoginResponse{
+			TwoFactorSetupRequired: true,
+			SetupToken:             setupToken,
+		})
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	signedToken, err := token.SignedString([]byte(secret))
+	signedToken, err := h.issueToken(user, middleware.TokenTypeAccess, accessTokenTTL)
 	if err != nil {
-		log.Println("Error signing JWT token during login:", err.Error(), "user_handler.go:Login", "Error at line 190")
+		log.Println("Error signing JWT token during login:", err.Error(), "user_handler.go:Login", "Error at line 279")
 		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
 	}
 
@@ -236,3 +294,143 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(loginResponse)
 }
+
+// UnlockUser godoc
+// @Summary Unlock user account (Admin only)
+// @Description Membuka kunci akun yang terkunci karena terlalu banyak gagal login
+// @Tags Auth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} models.UnlockUserResponse "OK"
+// @Failure 404 {object} middleware.ErrorResponse "Not Found"
+// @Failure 422 {object} middleware.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Router /api/auth/users/{id}/unlock [post]
+func (h *UserHandler) UnlockUser(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Data input tidak valid")
+	}
+
+	user, err := h.repo.FindByID(uint(id))
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "User tidak ditemukan")
+	}
+
+	if err := h.repo.ResetFailedLogin(user.ID); err != nil {
+		log.Println("Error unlocking user:", err.Error(), "user_handler.go:UnlockUser", "Error at line 296")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.UnlockUserResponse{
+		Message: "Akun " + user.Username + " berhasil dibuka",
+	})
+}
+
+// GetLoginAudit godoc
+// @Summary Get login audit (Admin only)
+// @Description Daftar percobaan login (sukses dan gagal) beserta IP dan user agent
+// @Tags Auth
+// @Produce json
+// @Param email query string false "Filter by email"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} models.LoginAudit "OK"
+// @Failure 500 {object} middleware.ErrorResponse "Internal Server Error"
+// @Security BearerAuth
+// @Router /api/auth/login-audit [get]
+func (h *UserHandler) GetLoginAudit(c *fiber.Ctx) error {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	data, total, err := h.repo.GetLoginAudit(c.Query("email"), limit, (page-1)*limit)
+	if err != nil {
+		log.Println("Error fetching login audit:", err.Error(), "user_handler.go:GetLoginAudit", "Error at line 329")
+		return fiber.NewError(fiber.StatusInternalServerError, "Server error")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": data,
+		"meta": fiber.Map{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// recordLoginAttempt mencatat percobaan login ke tabel login_audit. Kegagalan pencatatan hanya di-log agar tidak menggagalkan login.
+func (h *UserHandler) recordLoginAttempt(c *fiber.Ctx, userID *uint, email string, success bool, reason string) {
+	audit := models.LoginAudit{
+		UserID:    userID,
+		Email:     email,
+		IPAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		Success:   success,
+		Reason:    reason,
+	}
+	if err := h.repo.CreateLoginAudit(&audit); err != nil {
+		log.Println("Error recording login audit:", err.Error(), "user_handler.go:recordLoginAttempt")
+	}
+}
+
+// completeLogin me-reset counter gagal login dan mencatat login sukses
+func (h *UserHandler) completeLogin(c *fiber.Ctx, user *models.User) {
+	if user.FailedLoginCount > 0 || user.LockedUntil != nil {
+		if err := h.repo.ResetFailedLogin(user.ID); err != nil {
+			log.Println("Error resetting failed login:", err.Error(), "user_handler.go:completeLogin")
+		}
+	}
+	h.recordLoginAttempt(c, &user.ID, user.Email, true, "success")
+}
+
+// Masa berlaku token per jenis
+const (
+	accessTokenTTL        = 24 * time.Hour
+	twoFactorChallengeTTL = 5 * time.Minute
+	twoFactorSetupTTL     = 15 * time.Minute
+)
+
+// issueToken membuat JWT untuk user dengan jenis token (claim "typ") dan masa berlaku tertentu
+func (h *UserHandler) issueToken(user *models.User, tokenType string, ttl time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"id":    user.ID,
+		"email": user.Email,
+		"role":  user.Role,
+		"typ":   tokenType,
+		"exp":   time.Now().Add(ttl).Unix(),
+	}
+
+	return h.keys.Sign(claims)
+}
+
+// checkAccountThrottle menolak percobaan login untuk akun yang sedang terkunci atau belum melewati jeda progresif
+func (h *UserHandler) checkAccountThrottle(c *fiber.Ctx, user *models.User) error {
+	now := time.Now()
+
+	// Proteksi per akun: akun terkunci sementara setelah terlalu banyak gagal login
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		h.recordLoginAttempt(c, &user.ID, user.Email, false, "locked")
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(user.LockedUntil.Sub(now).Seconds())+1))
+		return fiber.NewError(fiber.StatusLocked, "Akun terkunci sementara karena terlalu banyak percobaan login gagal")
+	}
+
+	// Jeda progresif: percobaan berikutnya baru diterima setelah jeda yang makin panjang
+	if user.LastFailedLoginAt != nil {
+		retryAt := user.LastFailedLoginAt.Add(h.policy.Delay(user.FailedLoginCount))
+		if now.Before(retryAt) {
+			h.recordLoginAttempt(c, &user.ID, user.Email, false, "too_fast")
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(retryAt.Sub(now).Seconds())+1))
+			return fiber.NewError(fiber.StatusTooManyRequests, "Terlalu cepat mencoba login kembali. Silakan tunggu beberapa saat.")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer menyimpan setiap email sebagai file .eml di sebuah direktori, untuk testing lokal tanpa mail server
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), sanitizeFileName(msg.To))
+	return os.WriteFile(filepath.Join(m.dir, name), render(m.from, msg), 0o600)
+}
+
+// ConsoleMailer menulis email ke log aplikasi
+type ConsoleMailer struct {
+	from string
+}
+
+func NewConsoleMailer(from string) *ConsoleMailer {
+	return &ConsoleMailer{from: from}
+}
+
+func (m *ConsoleMailer) Send(msg Message) error {
+	log.Printf("----- email -----\n%s\n-----------------", render(m.from, msg))
+	return nil
+}
+
+func sanitizeFileName(s string) string {
+	out := []rune(s)
+	for i, r := range out {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '@') {
+			out[i] = '_'
+		}
+	}
+	return string(out)
+}
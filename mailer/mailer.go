@@ -0,0 +1,64 @@
+// Package mailer menyediakan interface pengiriman email beserta implementasi SMTP, file dan console.
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Message adalah email plain text yang akan dikirim
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer adalah interface pengirim email. Implementasi dipilih lewat env MAIL_DRIVER
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// NewFromEnv membuat Mailer sesuai env MAIL_DRIVER: "smtp", "file" atau "console" (default)
+func NewFromEnv() (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "no-reply@warehouse.local"
+	}
+
+	switch strings.ToLower(os.Getenv("MAIL_DRIVER")) {
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, fmt.Errorf("SMTP_HOST is not set")
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return NewSMTPMailer(host, port, os.Getenv("SMTP_USER"), os.Getenv("SMTP_PASSWORD"), from), nil
+	case "file":
+		dir := os.Getenv("MAIL_FILE_DIR")
+		if dir == "" {
+			dir = "mail_outbox"
+		}
+		return NewFileMailer(dir, from)
+	case "", "console":
+		return NewConsoleMailer(from), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q", os.Getenv("MAIL_DRIVER"))
+	}
+}
+
+// render membentuk email RFC 5322 sederhana (plain text, UTF-8)
+func render(from string, msg Message) []byte {
+	var sb strings.Builder
+	sb.WriteString("From: " + from + "\r\n")
+	sb.WriteString("To: " + msg.To + "\r\n")
+	sb.WriteString("Subject: " + msg.Subject + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(sb.String())
+}
@@ -3,11 +3,16 @@ package main
 import (
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"warehouse-inventory-server/config"
 	"warehouse-inventory-server/handlers"
+	"warehouse-inventory-server/mailer"
 	"warehouse-inventory-server/middleware"
 	"warehouse-inventory-server/repositories"
+	"warehouse-inventory-server/utils"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -31,6 +36,10 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
 	// Load .env file
 	_ = godotenv.Load()
@@ -56,6 +65,11 @@ func main() {
 		log.Println("Initial migration completed.")
 	}
 
+	// Run schema migrations that have not been applied yet (see migrations/)
+	if err := config.RunMigrations(db); err != nil {
+		log.Fatalf("failed to run migrations: %v", err)
+	}
+
 	// Initialize Fiber app with Custom Error Handler
 	app := fiber.New(fiber.Config{
 		ErrorHandler: middleware.ErrorHandler,
@@ -78,44 +92,135 @@ func main() {
 		})
 	})
 
+	// JWT signing/verification keys (JWT_KEYS_DIR, JWT_SIGNING_KID, JWT_SECRET)
+	jwtKeys, err := config.LoadJWTKeySet()
+	if err != nil {
+		log.Fatalf("failed to load JWT keys: %v", err)
+	}
+	middleware.SetJWTKeySet(jwtKeys)
+
+	// Reload JWT keys from disk on SIGHUP (key rotation without restart)
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if err := jwtKeys.Reload(); err != nil {
+				log.Printf("failed to reload JWT keys: %v", err)
+				continue
+			}
+			log.Println("JWT keys reloaded")
+		}
+	}()
+
+	// Stock repository, shared by the snapshot scheduler and the stock routes
+	stokRepo := repositories.NewStokRepository(db)
+
+	// Periodic stock snapshots (STOCK_SNAPSHOT_INTERVAL_HOURS) to keep point-in-time stock queries fast.
+	// Skipped when another replica holds the snapshot lock or a snapshot was taken within half an interval (e.g. after a restart).
+	go func() {
+		interval := utils.StockSnapshotInterval()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			at := utils.StockSnapshotTime(time.Now())
+			if count, err := stokRepo.CreateScheduledSnapshot(at, interval/2); err != nil {
+				log.Printf("failed to create stock snapshot: %v", err)
+			} else if count > 0 {
+				log.Printf("stock snapshot %s created for %d items", at.Format(time.RFC3339), count)
+			}
+			<-ticker.C
+		}
+	}()
+
+	// JWKS endpoint for other internal services verifying our tokens
+	jwksHandler := handlers.NewJWKSHandler(jwtKeys)
+	app.Get("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
+	// Mailer (MAIL_DRIVER: smtp, file, console)
+	mail, err := mailer.NewFromEnv()
+	if err != nil {
+		log.Fatalf("failed to initialize mailer: %v", err)
+	}
+
 	// Auth routes
 	userRepo := repositories.NewUserRepository(db)
-	userHandler := handlers.NewUserHandler(userRepo)
+	userHandler := handlers.NewUserHandler(userRepo, mail, jwtKeys)
 
 	authRoute := app.Group("/api/auth")
 	userHandler.RegisterRoute(authRoute)
 
+	// API key routes (machine-to-machine). Only admins with a user JWT can manage keys.
+	apiKeyRepo := repositories.NewAPIKeyRepository(db)
+	middleware.SetAPIKeyValidator(apiKeyRepo)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyRepo, userRepo)
+
+	apiKeyRoute := app.Group("/api/api-keys", middleware.AuthenticationFor(middleware.TokenTypeAccess), middleware.GuardAdmin())
+	apiKeyHandler.RegisterRoute(apiKeyRoute)
+
 	// Barang routes
 	barangRepo := repositories.NewBarangRepository(db)
 	barangHandler := handlers.NewBarangHandler(barangRepo)
 
-	barangRoute := app.Group("/api/barang", middleware.Authentication())
+	barangRoute := app.Group("/api/barang", middleware.Authentication(), middleware.RequireScope("barang"))
 	barangHandler.RegisterRoute(barangRoute)
 
+	// Kategori & brand routes
+	kategoriRepo := repositories.NewKategoriRepository(db)
+	kategoriHandler := handlers.NewKategoriHandler(kategoriRepo)
+
+	kategoriRoute := app.Group("/api/kategori", middleware.Authentication(), middleware.RequireScope("barang"))
+	kategoriHandler.RegisterRoute(kategoriRoute)
+
+	brandRepo := repositories.NewBrandRepository(db)
+	brandHandler := handlers.NewBrandHandler(brandRepo)
+
+	brandRoute := app.Group("/api/brand", middleware.Authentication(), middleware.RequireScope("barang"))
+	brandHandler.RegisterRoute(brandRoute)
+
 	// Stock routes
-	stokRepo := repositories.NewStokRepository(db)
 	stokHandler := handlers.NewStokHandler(stokRepo)
 
-	stokRoute := app.Group("/api/stok", middleware.Authentication())
+	stokRoute := app.Group("/api/stok", middleware.Authentication(), middleware.RequireScope("stok"))
 	stokHandler.RegisterStockRoute(stokRoute)
 
-	historyRoute := app.Group("/api/history-stok", middleware.Authentication())
+	historyRoute := app.Group("/api/history-stok", middleware.Authentication(), middleware.RequireScope("history"))
 	stokHandler.RegisterHistoryRoute(historyRoute)
 
 	// Pembelian routes
 	pembelianRepo := repositories.NewPembelianRepository(db)
 	pembelianHandler := handlers.NewPembelianHandler(pembelianRepo, stokRepo, barangRepo)
 
-	pembelianRoute := app.Group("/api/pembelian", middleware.Authentication())
+	pembelianRoute := app.Group("/api/pembelian", middleware.Authentication(), middleware.RequireScope("pembelian"))
 	pembelianHandler.RegisterRoute(pembelianRoute)
 
+	// Replenishment routes (saran pembelian)
+	replenishmentRepo := repositories.NewReplenishmentRepository(db)
+	replenishmentHandler := handlers.NewReplenishmentHandler(replenishmentRepo, pembelianRepo)
+
+	replenishmentRoute := app.Group("/api/replenishment", middleware.Authentication(), middleware.RequireScope("pembelian"))
+	replenishmentHandler.RegisterRoute(replenishmentRoute)
+
 	// Penjualan routes
 	penjualanRepo := repositories.NewPenjualanRepository(db)
 	penjualanHandler := handlers.NewPenjualanHandler(penjualanRepo, stokRepo, barangRepo)
 
-	penjualanRoute := app.Group("/api/penjualan", middleware.Authentication())
+	penjualanRoute := app.Group("/api/penjualan", middleware.Authentication(), middleware.RequireScope("penjualan"))
 	penjualanHandler.RegisterRoute(penjualanRoute)
 
+	// Report routes
+	reportRepo := repositories.NewReportRepository(db)
+	reportHandler := handlers.NewReportHandler(reportRepo)
+
+	reportRoute := app.Group("/api/reports", middleware.Authentication(), middleware.RequireScope("reports"))
+	reportHandler.RegisterRoute(reportRoute)
+
+	// Dashboard routes
+	dashboardRepo := repositories.NewDashboardRepository(db)
+	dashboardHandler := handlers.NewDashboardHandler(dashboardRepo)
+
+	dashboardRoute := app.Group("/api/dashboard", middleware.Authentication(), middleware.RequireScope("reports"))
+	dashboardHandler.RegisterRoute(dashboardRoute)
+
 	port := os.Getenv("PORT")
 
 	if err := app.Listen(":" + port); err != nil {
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"strings"
+
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/repositories"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// APIKeyValidator memvalidasi API key mentah dan mengembalikan data key-nya
+type APIKeyValidator interface {
+	ValidateAPIKey(raw string) (*models.APIKey, error)
+}
+
+var apiKeyValidator APIKeyValidator
+
+// SetAPIKeyValidator mendaftarkan validator API key yang dipakai oleh Authentication
+func SetAPIKeyValidator(v APIKeyValidator) {
+	apiKeyValidator = v
+}
+
+func extractAPIKey(c *fiber.Ctx) string {
+	if key := c.Get("X-API-Key"); key != "" {
+		return key
+	}
+	if authHeader := c.Get("Authorization"); strings.HasPrefix(authHeader, "ApiKey ") {
+		return strings.TrimSpace(authHeader[len("ApiKey "):])
+	}
+	return ""
+}
+
+// authenticateAPIKey memvalidasi API key lalu menyimpan claims setara JWT ke context,
+// sehingga handler dapat membaca user_id dan api_key_id dengan cara yang sama
+func authenticateAPIKey(c *fiber.Ctx, rawKey string) error {
+	if apiKeyValidator == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error":   "Unauthorized",
+			"message": "API key tidak didukung",
+		})
+	}
+
+	key, err := apiKeyValidator.ValidateAPIKey(rawKey)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrInvalidAPIKey) {
+			log.Println("Error validating API key:", err.Error(), "api_key.go:authenticateAPIKey")
+		}
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error":   "Unauthorized",
+			"message": "API key tidak valid",
+		})
+	}
+
+	// Role mengikuti user yang diwakili key. Scope "admin" hanya berlaku untuk user admin,
+	// dan user admin hanya mendapat role admin jika key-nya memiliki scope "admin".
+	scopes := key.ScopeList()
+	role := key.User.Role
+	if role == "admin" && !slices.Contains(scopes, "admin") {
+		role = "staff"
+	}
+	if role != "admin" {
+		scopes = slices.DeleteFunc(scopes, func(s string) bool { return s == "admin" })
+	}
+
+	c.Locals("user", jwt.MapClaims{
+		"id":         float64(key.UserID),
+		"role":       role,
+		"typ":        TokenTypeAPIKey,
+		"api_key_id": float64(key.ID),
+		"scopes":     scopes,
+	})
+
+	return c.Next()
+}
+
+// RequireScope membatasi akses API key berdasarkan scope "<resource>:read" (GET) atau "<resource>:write" (selain GET).
+// Request dengan JWT user tidak dibatasi scope.
+func RequireScope(resource string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("user").(jwt.MapClaims)
+		if !ok || claims["typ"] != TokenTypeAPIKey {
+			return c.Next()
+		}
+
+		scope := resource + ":write"
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			scope = resource + ":read"
+		}
+
+		scopes, _ := claims["scopes"].([]string)
+		if !slices.Contains(scopes, scope) && !slices.Contains(scopes, "admin") {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":   "Akses ditolak",
+				"message": "API key tidak memiliki scope " + scope,
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -2,15 +2,50 @@ package middleware
 
 import (
 	"log"
-	"os"
+	"slices"
 	"strings"
 
+	"warehouse-inventory-server/config"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Jenis token yang disimpan pada claim "typ"
+const (
+	TokenTypeAccess             = "access"        // token akses penuh
+	TokenTypeTwoFactorChallenge = "2fa_challenge" // token sementara setelah password benar, ditukar dengan kode 2FA
+	TokenTypeTwoFactorSetup     = "2fa_setup"     // token sementara untuk enrollment 2FA yang diwajibkan
+	TokenTypeAPIKey             = "api_key"       // bukan JWT: request diautentikasi dengan API key
+)
+
+var jwtKeys *config.JWTKeySet
+
+// SetJWTKeySet mendaftarkan key set yang dipakai untuk memverifikasi JWT
+func SetJWTKeySet(ks *config.JWTKeySet) {
+	jwtKeys = ks
+}
+
+// Authentication memvalidasi Bearer token akses atau API key
 func Authentication() fiber.Handler {
+	return AuthenticationFor(TokenTypeAccess, TokenTypeAPIKey)
+}
+
+// AuthenticationFor memvalidasi Bearer token dan hanya menerima jenis token (claim "typ") yang disebutkan.
+// Token tanpa claim "typ" (diterbitkan sebelum 2FA ada) dianggap sebagai token akses.
+func AuthenticationFor(tokenTypes ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		// API key (header X-API-Key atau Authorization: ApiKey <key>)
+		if rawKey := extractAPIKey(c); rawKey != "" {
+			if !slices.Contains(tokenTypes, TokenTypeAPIKey) {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   "Unauthorized",
+					"message": "API key tidak diizinkan untuk endpoint ini",
+				})
+			}
+			return authenticateAPIKey(c, rawKey)
+		}
+
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -29,19 +64,12 @@ func Authentication() fiber.Handler {
 			})
 		}
 
-		secret := os.Getenv("JWT_SECRET")
-
-		if secret == "" {
-			log.Println("Environment variable error: JWT_SECRET is empty", "middleware.go:Authentication", "Error at line 34")
+		if jwtKeys == nil {
+			log.Println("JWT key set is not configured", "middleware.go:Authentication", "Error at line 64")
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Server error"})
 		}
 
-		parsed, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fiber.ErrUnauthorized
-			}
-			return []byte(secret), nil
-		})
+		parsed, err := jwt.Parse(tokenString, jwtKeys.Keyfunc, jwt.WithValidMethods(jwtKeys.ValidMethods()))
 		if err != nil || !parsed.Valid {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error":   "Unauthorized",
@@ -49,10 +77,27 @@ func Authentication() fiber.Handler {
 			})
 		}
 
-		// Simpan claims ke fiber context
-		if claims, ok := parsed.Claims.(jwt.MapClaims); ok {
-			c.Locals("user", claims)
+		claims, ok := parsed.Claims.(jwt.MapClaims)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Unauthorized",
+				"message": "Token tidak valid",
+			})
+		}
+
+		tokenType, _ := claims["typ"].(string)
+		if tokenType == "" {
+			tokenType = TokenTypeAccess
 		}
+		if !slices.Contains(tokenTypes, tokenType) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Unauthorized",
+				"message": "Jenis token tidak diizinkan untuk endpoint ini",
+			})
+		}
+
+		// Simpan claims ke fiber context
+		c.Locals("user", claims)
 
 		return c.Next()
 	}
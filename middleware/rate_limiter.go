@@ -28,3 +28,23 @@ func RateLimiter() fiber.Handler {
 		},
 	})
 }
+
+// PasswordResetLimiter membatasi permintaan reset password dari satu IP - 5 request per 15 menit,
+// tanpa melihat email agar batasnya tidak membocorkan email terdaftar
+func PasswordResetLimiter() fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        5,
+		Expiration: 15 * time.Minute,
+
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return "forgot-password:" + c.IP()
+		},
+
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"status":  "Too Many Requests",
+				"message": "Terlalu banyak permintaan reset password. Silakan coba lagi nanti.",
+			})
+		},
+	})
+}
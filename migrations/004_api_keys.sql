@@ -0,0 +1,19 @@
+-- Table API Key untuk integrasi machine-to-machine (key disimpan sebagai hash SHA-256)
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) UNIQUE NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    scopes VARCHAR(500) NOT NULL DEFAULT '',
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    created_by INTEGER REFERENCES users(id),
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Catat API key yang melakukan transaksi / perubahan stok
+ALTER TABLE history_stok ADD COLUMN IF NOT EXISTS api_key_id INTEGER REFERENCES api_keys(id);
+ALTER TABLE beli_header ADD COLUMN IF NOT EXISTS api_key_id INTEGER REFERENCES api_keys(id);
+ALTER TABLE jual_header ADD COLUMN IF NOT EXISTS api_key_id INTEGER REFERENCES api_keys(id);
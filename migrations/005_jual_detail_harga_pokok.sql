@@ -0,0 +1,3 @@
+-- Harga pokok (HPP per unit) disimpan saat penjualan agar laporan margin tidak berubah ketika harga beli master diubah.
+-- Baris lama bernilai NULL dan laporan memakai master_barang.harga_beli sebagai gantinya.
+ALTER TABLE jual_detail ADD COLUMN IF NOT EXISTS harga_pokok DECIMAL(15,2);
@@ -0,0 +1,18 @@
+-- Reorder point per barang: stok_minimum (titik reorder), stok_maksimum dan reorder_qty (jumlah pesan standar)
+ALTER TABLE mstok ADD COLUMN IF NOT EXISTS stok_minimum INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE mstok ADD COLUMN IF NOT EXISTS stok_maksimum INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE mstok ADD COLUMN IF NOT EXISTS reorder_qty INTEGER NOT NULL DEFAULT 0;
+
+-- Table Stock Alert: dibuat ketika stok turun melewati titik reorder
+CREATE TABLE IF NOT EXISTS stock_alerts (
+    id SERIAL PRIMARY KEY,
+    barang_id INTEGER NOT NULL REFERENCES master_barang(id),
+    stok_sebelum INTEGER NOT NULL,
+    stok_sesudah INTEGER NOT NULL,
+    reorder_point INTEGER NOT NULL,
+    keterangan TEXT,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_alerts_unresolved ON stock_alerts (barang_id) WHERE resolved_at IS NULL;
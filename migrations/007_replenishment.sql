@@ -0,0 +1,10 @@
+-- Lead time (hari) per supplier untuk perhitungan saran pembelian
+CREATE TABLE IF NOT EXISTS supplier_lead_times (
+    id SERIAL PRIMARY KEY,
+    supplier VARCHAR(200) UNIQUE NOT NULL,
+    lead_time_days INTEGER NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index untuk menghitung barang keluar per periode
+CREATE INDEX IF NOT EXISTS idx_history_stok_jenis_created ON history_stok (jenis_transaksi, created_at);
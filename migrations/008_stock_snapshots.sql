@@ -0,0 +1,12 @@
+-- Snapshot stok per barang: stok = level stok setelah semua history_stok dengan created_at < snapshot_at
+CREATE TABLE IF NOT EXISTS stock_snapshots (
+    id SERIAL PRIMARY KEY,
+    barang_id INTEGER NOT NULL REFERENCES master_barang(id),
+    snapshot_at TIMESTAMP NOT NULL,
+    stok INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (barang_id, snapshot_at)
+);
+
+-- Index untuk mencari pergerakan stok terakhir sebelum waktu tertentu
+CREATE INDEX IF NOT EXISTS idx_history_stok_barang_created ON history_stok (barang_id, created_at);
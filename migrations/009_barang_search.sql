@@ -0,0 +1,17 @@
+-- Full-text search dan trigram similarity untuk pencarian barang
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Kode dan nama berbobot A, deskripsi berbobot B. Konfigurasi 'simple' karena nama barang campuran Indonesia/Inggris.
+ALTER TABLE master_barang ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(kode_barang, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(nama_barang, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(deskripsi, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_master_barang_search_vector ON master_barang USING GIN (search_vector);
+
+-- Index trigram untuk ILIKE '%term%' dan pencarian typo (word_similarity)
+CREATE INDEX IF NOT EXISTS idx_master_barang_kode_trgm ON master_barang USING GIN (kode_barang gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_master_barang_nama_trgm ON master_barang USING GIN (nama_barang gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_master_barang_deskripsi_trgm ON master_barang USING GIN (deskripsi gin_trgm_ops);
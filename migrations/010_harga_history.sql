@@ -0,0 +1,16 @@
+-- Riwayat perubahan harga barang (update manual, bulk update dan import)
+CREATE TABLE IF NOT EXISTS harga_history (
+    id SERIAL PRIMARY KEY,
+    barang_id INTEGER NOT NULL REFERENCES master_barang(id) ON DELETE CASCADE,
+    harga_beli_lama DECIMAL(15,2) NOT NULL,
+    harga_beli_baru DECIMAL(15,2) NOT NULL,
+    harga_jual_lama DECIMAL(15,2) NOT NULL,
+    harga_jual_baru DECIMAL(15,2) NOT NULL,
+    sumber VARCHAR(20) NOT NULL, -- 'manual', 'bulk', 'import'
+    keterangan TEXT,
+    user_id INTEGER REFERENCES users(id),
+    api_key_id INTEGER REFERENCES api_keys(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_harga_history_barang_created ON harga_history (barang_id, created_at);
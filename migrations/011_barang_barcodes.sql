@@ -0,0 +1,9 @@
+-- Barcode (EAN/UPC) barang; satu barang bisa memiliki lebih dari satu barcode
+CREATE TABLE IF NOT EXISTS barang_barcodes (
+    id SERIAL PRIMARY KEY,
+    barang_id INTEGER NOT NULL REFERENCES master_barang(id) ON DELETE CASCADE,
+    barcode VARCHAR(14) UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_barang_barcodes_barang ON barang_barcodes (barang_id);
@@ -0,0 +1,31 @@
+-- Kategori barang bertingkat. path menyimpan ID leluhur sampai kategori itu sendiri, misal '/1/5/'
+-- (sub kategori dicari dengan path LIKE '/1/%', level kategori = jumlah segmen path)
+CREATE TABLE IF NOT EXISTS kategori (
+    id SERIAL PRIMARY KEY,
+    nama VARCHAR(100) NOT NULL,
+    parent_id INTEGER REFERENCES kategori(id),
+    path VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kategori_parent_nama ON kategori (COALESCE(parent_id, 0), LOWER(nama));
+CREATE INDEX IF NOT EXISTS idx_kategori_path ON kategori (path varchar_pattern_ops);
+
+CREATE TABLE IF NOT EXISTS brand (
+    id SERIAL PRIMARY KEY,
+    nama VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_brand_nama ON brand (LOWER(nama));
+
+-- Kategori, brand dan atribut bebas (warna, ukuran, voltase, ...) pada barang
+ALTER TABLE master_barang ADD COLUMN IF NOT EXISTS kategori_id INTEGER REFERENCES kategori(id);
+ALTER TABLE master_barang ADD COLUMN IF NOT EXISTS brand_id INTEGER REFERENCES brand(id);
+ALTER TABLE master_barang ADD COLUMN IF NOT EXISTS atribut JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_master_barang_kategori ON master_barang (kategori_id);
+CREATE INDEX IF NOT EXISTS idx_master_barang_brand ON master_barang (brand_id);
+CREATE INDEX IF NOT EXISTS idx_master_barang_atribut ON master_barang USING GIN (atribut jsonb_path_ops);
@@ -0,0 +1,4 @@
+-- Konfirmasi pembelian draft dicatat terpisah agar pembuat draft (user_id) dan waktu pembuatannya (created_at) tidak berubah.
+-- Laporan pembelian memakai COALESCE(confirmed_at, created_at) sebagai tanggal barang diterima.
+ALTER TABLE beli_header ADD COLUMN IF NOT EXISTS confirmed_at TIMESTAMP;
+ALTER TABLE beli_header ADD COLUMN IF NOT EXISTS confirmed_by INTEGER REFERENCES users(id);
@@ -0,0 +1,3 @@
+-- Nilai atribut barang disimpan dalam huruf kecil (seperti namanya) agar filter atribut tidak case-sensitive
+UPDATE master_barang SET atribut = CAST(LOWER(CAST(atribut AS text)) AS jsonb)
+WHERE CAST(atribut AS text) <> LOWER(CAST(atribut AS text));
@@ -0,0 +1,25 @@
+-- Barcode disimpan sebagai GTIN-14 (diawali nol) agar UPC-A, EAN-13 dan GTIN-14 untuk produk yang sama tidak terdaftar dua kali.
+-- Barcode yang menjadi sama setelah dinormalisasi pada barang yang sama cukup disimpan yang paling awal didaftarkan.
+DELETE FROM barang_barcodes a USING barang_barcodes b
+WHERE LPAD(a.barcode, 14, '0') = LPAD(b.barcode, 14, '0') AND a.barang_id = b.barang_id AND a.id > b.id;
+
+-- Jika sama tetapi milik barang berbeda, migrasi dibatalkan agar dipilih manual barcode mana yang dihapus
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(gtin || ' (' || pairs || ')', '; ' ORDER BY gtin) INTO conflicts
+    FROM (
+        SELECT LPAD(barcode, 14, '0') AS gtin,
+            string_agg('barang_id ' || barang_id || ': ' || barcode, ', ' ORDER BY id) AS pairs
+        FROM barang_barcodes
+        GROUP BY LPAD(barcode, 14, '0')
+        HAVING COUNT(*) > 1
+    ) c;
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'barcode sama setelah dinormalisasi ke GTIN-14 terdaftar pada barang berbeda, hapus salah satu lalu jalankan ulang: %', conflicts;
+    END IF;
+END $$;
+
+UPDATE barang_barcodes SET barcode = LPAD(barcode, 14, '0') WHERE LENGTH(barcode) < 14;
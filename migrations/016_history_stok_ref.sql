@@ -0,0 +1,14 @@
+-- Referensi dokumen sumber history stok (pembelian / penjualan) agar kartu stok tidak bergantung pada teks keterangan
+ALTER TABLE history_stok ADD COLUMN IF NOT EXISTS ref_type VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE history_stok ADD COLUMN IF NOT EXISTS ref_id INTEGER;
+
+-- History lama: dokumen diambil dari keterangan "Pembelian BLI001" / "Penjualan JUAL001"
+UPDATE history_stok h SET ref_type = 'pembelian', ref_id = bh.id
+FROM beli_header bh
+WHERE h.ref_type = '' AND h.jenis_transaksi = 'masuk' AND h.keterangan = 'Pembelian ' || bh.no_faktur;
+
+UPDATE history_stok h SET ref_type = 'penjualan', ref_id = jh.id
+FROM jual_header jh
+WHERE h.ref_type = '' AND h.jenis_transaksi = 'keluar' AND h.keterangan = 'Penjualan ' || jh.no_faktur;
+
+CREATE INDEX IF NOT EXISTS idx_history_stok_ref ON history_stok (ref_type, ref_id);
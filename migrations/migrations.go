@@ -0,0 +1,8 @@
+// Package migrations berisi perubahan skema database yang dijalankan setelah db_migration.sql.
+// Setiap file *.sql dijalankan sekali secara berurutan berdasarkan nama file.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Scope yang dapat diberikan ke API key. "<resource>:read" untuk GET, "<resource>:write" untuk selain GET
+var APIKeyScopes = []string{
+	"barang:read", "barang:write",
+	"stok:read", "stok:write",
+	"history:read",
+	"pembelian:read", "pembelian:write",
+	"penjualan:read", "penjualan:write",
+	"reports:read",
+	"admin",
+}
+
+// Model struct for api_keys table
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:16;unique;not null" json:"prefix"`
+	KeyHash    string     `gorm:"size:64;not null" json:"-"`
+	Scopes     string     `gorm:"size:500;not null" json:"-"` // dipisah koma, misal "stok:read,penjualan:write"
+	UserID     uint       `gorm:"not null" json:"user_id"`    // user yang diwakili API key (tercatat di history & transaksi)
+	CreatedBy  *uint      `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Associations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"` // APIKey many to one User
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList mengembalikan scopes dalam bentuk slice
+func (k APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// Request and Response structs for API key API
+type APIKeyRequest struct {
+	Name      string   `json:"name"`
+	UserID    uint     `json:"user_id"` // opsional, default user admin yang membuat
+	Scopes    []string `json:"scopes"`
+	ExpiresAt *string  `json:"expires_at"` // format RFC3339, opsional
+}
+
+type APIKeyResponse struct {
+	ID         uint               `json:"id"`
+	Name       string             `json:"name"`
+	Prefix     string             `json:"prefix"`
+	Scopes     []string           `json:"scopes"`
+	UserID     uint               `json:"user_id"`
+	ExpiresAt  *time.Time         `json:"expires_at"`
+	LastUsedAt *time.Time         `json:"last_used_at"`
+	RevokedAt  *time.Time         `json:"revoked_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	User       UserSimpleResponse `json:"user"`
+}
+
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"` // hanya ditampilkan sekali saat dibuat
+}
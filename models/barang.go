@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 type MasterBarang struct {
 	ID         uint      `gorm:"primaryKey" json:"id"`
@@ -10,6 +15,9 @@ type MasterBarang struct {
 	Satuan     string    `gorm:"size:50;not null" json:"satuan"`
 	HargaBeli  float64   `gorm:"default:0" json:"harga_beli"`
 	HargaJual  float64   `gorm:"default:0" json:"harga_jual"`
+	KategoriID *uint     `json:"kategori_id"`
+	BrandID    *uint     `json:"brand_id"`
+	Atribut    Atribut   `gorm:"type:jsonb;not null;default:'{}'" json:"atribut"`
 	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -18,39 +26,98 @@ func (MasterBarang) TableName() string {
 	return "master_barang"
 }
 
+// Atribut adalah atribut bebas barang (misal warna, ukuran, voltase), disimpan sebagai JSONB
+type Atribut map[string]string
+
+func (a Atribut) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(a)
+	return string(b), err
+}
+
+func (a *Atribut) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*a = Atribut{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("atribut: tipe %T tidak didukung", value)
+	}
+}
+
 // Request and Response structs for barang API
 type BarangRequest struct {
-	NamaBarang string  `json:"nama_barang"`
-	Deskripsi  string  `json:"deskripsi"`
-	Satuan     string  `json:"satuan"`
-	HargaBeli  float64 `json:"harga_beli"`
-	HargaJual  float64 `json:"harga_jual"`
+	NamaBarang string            `json:"nama_barang"`
+	Deskripsi  string            `json:"deskripsi"`
+	Satuan     string            `json:"satuan"`
+	HargaBeli  float64           `json:"harga_beli"`
+	HargaJual  float64           `json:"harga_jual"`
+	KategoriID *uint             `json:"kategori_id"`
+	BrandID    *uint             `json:"brand_id"`
+	Atribut    map[string]string `json:"atribut"` // misal {"warna": "hitam", "voltase": "220v"}; nama dan nilai disimpan dalam huruf kecil
 }
 
 type CreatedBarangResponse struct {
-	ID         uint    `json:"id"`
-	KodeBarang string  `json:"kode_barang"`
-	NamaBarang string  `json:"nama_barang"`
-	Deskripsi  string  `json:"deskripsi"`
-	Satuan     string  `json:"satuan"`
-	HargaBeli  float64 `json:"harga_beli"`
-	HargaJual  float64 `json:"harga_jual"`
+	ID         uint              `json:"id"`
+	KodeBarang string            `json:"kode_barang"`
+	NamaBarang string            `json:"nama_barang"`
+	Deskripsi  string            `json:"deskripsi"`
+	Satuan     string            `json:"satuan"`
+	HargaBeli  float64           `json:"harga_beli"`
+	HargaJual  float64           `json:"harga_jual"`
+	KategoriID *uint             `json:"kategori_id"`
+	BrandID    *uint             `json:"brand_id"`
+	Atribut    map[string]string `json:"atribut"`
 }
 
 type BarangResponse struct {
-	ID         uint    `json:"id"`
-	KodeBarang string  `json:"kode_barang"`
-	NamaBarang string  `json:"nama_barang"`
-	Deskripsi  string  `json:"deskripsi"`
-	Satuan     string  `json:"satuan"`
-	HargaBeli  float64 `json:"harga_beli"`
-	HargaJual  float64 `json:"harga_jual"`
-	Stok       int     `json:"stok"`
+	ID         uint              `json:"id"`
+	KodeBarang string            `json:"kode_barang"`
+	NamaBarang string            `json:"nama_barang"`
+	Deskripsi  string            `json:"deskripsi"`
+	Satuan     string            `json:"satuan"`
+	HargaBeli  float64           `json:"harga_beli"`
+	HargaJual  float64           `json:"harga_jual"`
+	KategoriID *uint             `json:"kategori_id"`
+	Kategori   string            `json:"kategori,omitempty"`
+	BrandID    *uint             `json:"brand_id"`
+	Brand      string            `json:"brand,omitempty"`
+	Atribut    map[string]string `json:"atribut"`
+	Stok       int               `json:"stok"`
+	Barcodes   []string          `json:"barcodes,omitempty"`
+}
+
+// Status stok untuk filter daftar barang
+const (
+	StockStatusInStock = "in_stock" // stok > 0
+	StockStatusLow     = "low"      // stok > 0 dan sudah mencapai titik reorder
+	StockStatusOut     = "out"      // stok habis
+)
+
+// BarangFilter adalah filter dan paginasi untuk daftar barang
+type BarangFilter struct {
+	Search      string
+	Satuan      []string // huruf kecil
+	MinHarga    *float64 // harga jual
+	MaxHarga    *float64
+	StockStatus string
+	KlasifikasiFilter
+	Limit  int
+	Offset int
+	Cursor *Cursor // mode cursor (urut kode_barang): Offset diabaikan dan total tidak dihitung
 }
 
 type BarangWithStock struct {
 	MasterBarang
-	StokAkhir int `json:"stok_akhir"`
+	StokAkhir    int    `json:"stok_akhir"`
+	NamaKategori string `json:"nama_kategori"`
+	NamaBrand    string `json:"nama_brand"`
 }
 
 type DeleteBarangResponse struct {
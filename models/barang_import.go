@@ -0,0 +1,37 @@
+package models
+
+// BarangImportRow adalah satu baris file import barang yang sudah lolos validasi
+type BarangImportRow struct {
+	Row        int // nomor baris di file (header = baris 1)
+	KodeBarang string
+	Kategori   string // nama kategori / brand dari file; diubah menjadi Request.KategoriID / BrandID oleh handler
+	Brand      string
+	Request    BarangRequest
+	StokAwal   int             // hanya untuk barang baru, dicatat sebagai history "adjustment"
+	Filled     map[string]bool // kolom opsional yang terisi; saat update, kolom kosong atau tidak ada tidak mengubah nilai lama
+}
+
+// Aksi hasil import per baris
+const (
+	ImportAksiCreate = "create"
+	ImportAksiUpdate = "update"
+	ImportAksiError  = "error"
+)
+
+// Response structs for barang import API
+type BarangImportRowResult struct {
+	Row        int               `json:"row"`
+	KodeBarang string            `json:"kode_barang,omitempty"`
+	NamaBarang string            `json:"nama_barang"`
+	Aksi       string            `json:"aksi"`
+	Errors     map[string]string `json:"errors,omitempty"`
+}
+
+type BarangImportResponse struct {
+	DryRun    bool                    `json:"dry_run"`
+	TotalRows int                     `json:"total_rows"`
+	Created   int                     `json:"created"`
+	Updated   int                     `json:"updated"`
+	Failed    int                     `json:"failed"`
+	Rows      []BarangImportRowResult `json:"rows"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Model struct for barang_barcodes table
+type BarangBarcode struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	BarangID  uint      `gorm:"not null" json:"barang_id"`
+	Barcode   string    `gorm:"size:14;not null;unique" json:"barcode"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (BarangBarcode) TableName() string {
+	return "barang_barcodes"
+}
+
+// Request struct for barcode API
+type BarcodeRequest struct {
+	Barcode string `json:"barcode"`
+}
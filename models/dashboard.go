@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Response structs for dashboard API
+type DashboardTransactionTotal struct {
+	JumlahTransaksi int64   `json:"jumlah_transaksi"`
+	Total           float64 `json:"total"`
+}
+
+type DashboardTopBarang struct {
+	BarangID   uint    `json:"barang_id"`
+	KodeBarang string  `json:"kode_barang"`
+	NamaBarang string  `json:"nama_barang"`
+	TotalQty   int64   `json:"total_qty"`
+	TotalNilai float64 `json:"total_nilai"`
+}
+
+type DashboardResponse struct {
+	PenjualanHariIni  DashboardTransactionTotal `json:"penjualan_hari_ini"`
+	PenjualanBulanIni DashboardTransactionTotal `json:"penjualan_bulan_ini"`
+	PembelianHariIni  DashboardTransactionTotal `json:"pembelian_hari_ini"`
+	PembelianBulanIni DashboardTransactionTotal `json:"pembelian_bulan_ini"`
+	TotalNilaiStok    float64                   `json:"total_nilai_stok"`
+	JumlahStokRendah  int64                     `json:"jumlah_stok_rendah"`
+	TopBarang         []DashboardTopBarang      `json:"top_barang"`
+	HistoryTerbaru    []HistoryStokResponse     `json:"history_terbaru"`
+	GroupBy           string                    `json:"group_by,omitempty"`
+	Groups            []StockReportGroup        `json:"groups,omitempty"` // jumlah barang, total stok dan nilai stok per kelompok
+	GeneratedAt       time.Time                 `json:"generated_at"`
+}
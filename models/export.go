@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Baris export (CSV/XLSX) yang dibaca langsung dari query repository tanpa preload relasi
+
+type StokExportRow struct {
+	BarangID     uint
+	KodeBarang   string
+	NamaBarang   string
+	Satuan       string
+	StokAkhir    int
+	StokMinimum  int
+	StokMaksimum int
+	ReorderQty   int
+	HargaBeli    float64
+	UpdatedAt    time.Time
+	GroupLabel   string
+}
+
+type HistoryStokExportRow struct {
+	ID             uint
+	CreatedAt      time.Time
+	KodeBarang     string
+	NamaBarang     string
+	JenisTransaksi string
+	Jumlah         int
+	StokSebelum    int
+	StokSesudah    int
+	Keterangan     string
+	Username       string
+}
+
+// TransaksiExportRow dipakai untuk header pembelian maupun penjualan; Pihak berisi supplier atau customer
+type TransaksiExportRow struct {
+	ID         uint
+	NoFaktur   string
+	Pihak      string
+	Total      float64
+	Status     string
+	JumlahItem int
+	Username   string
+	CreatedAt  time.Time
+}
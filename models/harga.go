@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// Sumber perubahan harga
+const (
+	HargaSumberManual = "manual" // PUT /api/barang/:id
+	HargaSumberBulk   = "bulk"   // bulk update harga
+	HargaSumberImport = "import" // import barang
+)
+
+// Model struct for harga_history table
+type HargaHistory struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	BarangID      uint      `gorm:"not null" json:"barang_id"`
+	HargaBeliLama float64   `gorm:"not null" json:"harga_beli_lama"`
+	HargaBeliBaru float64   `gorm:"not null" json:"harga_beli_baru"`
+	HargaJualLama float64   `gorm:"not null" json:"harga_jual_lama"`
+	HargaJualBaru float64   `gorm:"not null" json:"harga_jual_baru"`
+	Sumber        string    `gorm:"not null" json:"sumber"`
+	Keterangan    string    `json:"keterangan"`
+	UserID        uint      `json:"user_id"`
+	APIKeyID      *uint     `json:"api_key_id"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Associations
+	MasterBarang MasterBarang `gorm:"foreignKey:BarangID;references:ID" json:"barang"`
+	User         User         `gorm:"foreignKey:UserID;references:ID" json:"user"`
+}
+
+func (HargaHistory) TableName() string {
+	return "harga_history"
+}
+
+// HargaHistoryFilter adalah filter dan paginasi untuk riwayat harga
+type HargaHistoryFilter struct {
+	BarangID uint
+	UserID   uint
+	Sumber   string
+	From     time.Time // inklusif, zero = tanpa batas
+	To       time.Time // eksklusif, zero = tanpa batas
+	Limit    int
+	Offset   int
+}
+
+// Request struct for bulk harga API. Pilih barang dengan tepat satu dari barang_ids, filter atau supplier.
+type BulkHargaRequest struct {
+	BarangIDs      []uint           `json:"barang_ids"`
+	Filter         *BulkHargaFilter `json:"filter"`
+	Supplier       string           `json:"supplier"`        // barang yang pernah dibeli dari supplier ini
+	Target         string           `json:"target"`          // harga_beli, harga_jual atau keduanya
+	Jenis          string           `json:"jenis"`           // persen atau nominal
+	Nilai          float64          `json:"nilai"`           // persen (8 = naik 8%) atau nominal (boleh negatif)
+	Pembulatan     float64          `json:"pembulatan"`      // kelipatan pembulatan, mis. 100; 0 = 2 desimal
+	ArahPembulatan string           `json:"arah_pembulatan"` // terdekat (default), atas atau bawah
+	Keterangan     string           `json:"keterangan"`
+}
+
+type BulkHargaFilter struct {
+	Search      string   `json:"search"`
+	Satuan      []string `json:"satuan"`
+	StockStatus string   `json:"stock_status"`
+	KategoriID  uint     `json:"kategori_id"` // termasuk sub kategori
+	BrandID     uint     `json:"brand_id"`
+}
+
+// Response structs for bulk harga API
+type PerubahanHarga struct {
+	BarangID      uint    `json:"barang_id"`
+	KodeBarang    string  `json:"kode_barang"`
+	NamaBarang    string  `json:"nama_barang"`
+	HargaBeliLama float64 `json:"harga_beli_lama"`
+	HargaBeliBaru float64 `json:"harga_beli_baru"`
+	HargaJualLama float64 `json:"harga_jual_lama"`
+	HargaJualBaru float64 `json:"harga_jual_baru"`
+}
+
+type BulkHargaResponse struct {
+	DryRun       bool             `json:"dry_run"`
+	JumlahBarang int              `json:"jumlah_barang"`
+	Perubahan    []PerubahanHarga `json:"perubahan"`
+}
+
+// Response struct for harga history API
+type HargaHistoryResponse struct {
+	ID            uint                 `json:"id"`
+	BarangID      uint                 `json:"barang_id"`
+	HargaBeliLama float64              `json:"harga_beli_lama"`
+	HargaBeliBaru float64              `json:"harga_beli_baru"`
+	HargaJualLama float64              `json:"harga_jual_lama"`
+	HargaJualBaru float64              `json:"harga_jual_baru"`
+	Sumber        string               `json:"sumber"`
+	Keterangan    string               `json:"keterangan"`
+	APIKeyID      *uint                `json:"api_key_id,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+	Barang        BarangSimpleResponse `json:"barang"`
+	User          UserSimpleResponse   `json:"user"`
+}
@@ -12,6 +12,9 @@ type HistoryStok struct {
 	StokSebelum    int       `gorm:"not null" json:"stok_sebelum"`
 	StokSesudah    int       `gorm:"not null" json:"stok_sesudah"`
 	Keterangan     string    `json:"keterangan"`
+	APIKeyID       *uint     `json:"api_key_id"`                                  // diisi jika perubahan dilakukan melalui API key
+	RefType        string    `gorm:"size:20;not null;default:''" json:"ref_type"` // dokumen sumber: "pembelian" / "penjualan", kosong untuk adjustment
+	RefID          *uint     `json:"ref_id"`                                      // ID beli_header / jual_header
 	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
 
 	// Associations
@@ -23,6 +26,12 @@ func (HistoryStok) TableName() string {
 	return "history_stok"
 }
 
+// Jenis dokumen sumber history stok (ref_type)
+const (
+	HistoryRefPembelian = "pembelian"
+	HistoryRefPenjualan = "penjualan"
+)
+
 // Response struct for history stok API
 type HistoryStokResponse struct {
 	ID             uint                 `json:"id"`
@@ -33,6 +42,9 @@ type HistoryStokResponse struct {
 	StokSebelum    int                  `json:"stok_sebelum"`
 	StokSesudah    int                  `json:"stok_sesudah"`
 	Keterangan     string               `json:"keterangan"`
+	APIKeyID       *uint                `json:"api_key_id,omitempty"`
+	RefType        string               `json:"ref_type,omitempty"`
+	RefID          *uint                `json:"ref_id,omitempty"`
 	CreatedAt      time.Time            `json:"created_at"`
 	Barang         BarangSimpleResponse `json:"barang"`
 	User           UserSimpleResponse   `json:"user"`
@@ -47,3 +59,18 @@ type UserSimpleResponse struct {
 	Username string `json:"username"`
 	FullName string `json:"full_name"`
 }
+
+// HistoryStokFilter adalah filter, urutan dan paginasi untuk daftar history stok
+type HistoryStokFilter struct {
+	BarangID       uint
+	JenisTransaksi []string
+	UserID         uint
+	From           time.Time // inklusif, zero = tanpa batas
+	To             time.Time // eksklusif, zero = tanpa batas
+	Search         string    // dicari di keterangan (termasuk no faktur)
+	Sort           string
+	Desc           bool
+	Limit          int
+	Offset         int
+	Cursor         *Cursor // mode cursor (hanya sort created_at): Offset diabaikan dan total tidak dihitung
+}
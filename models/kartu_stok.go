@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// KartuStokRow adalah satu pergerakan stok pada kartu stok beserta dokumen sumbernya
+type KartuStokRow struct {
+	HistoryID      uint      `json:"history_id"`
+	Tanggal        time.Time `json:"tanggal"`
+	JenisTransaksi string    `json:"jenis_transaksi"`
+	JenisDokumen   string    `json:"jenis_dokumen,omitempty"` // pembelian / penjualan, kosong untuk adjustment
+	DokumenID      *uint     `json:"dokumen_id,omitempty"`
+	NoDokumen      string    `json:"no_dokumen,omitempty"`
+	Pihak          string    `json:"pihak,omitempty"` // supplier / customer
+	Keterangan     string    `json:"keterangan"`
+	Username       string    `json:"username"`
+	Masuk          int       `json:"masuk"`
+	Keluar         int       `json:"keluar"`
+	Saldo          int       `json:"saldo"`
+	Jumlah         int       `json:"-"` // jumlah pada history_stok, bertanda untuk adjustment
+}
+
+// Response struct for kartu stok API
+type KartuStokResponse struct {
+	Barang      BarangStokResponse `json:"barang"`
+	BarangID    uint               `json:"barang_id"`
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	SaldoAwal   int                `json:"saldo_awal"`
+	TotalMasuk  int                `json:"total_masuk"`
+	TotalKeluar int                `json:"total_keluar"`
+	SaldoAkhir  int                `json:"saldo_akhir"`
+	Mutasi      []KartuStokRow     `json:"mutasi"`
+}
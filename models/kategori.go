@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Model struct for kategori table. Kategori bisa bertingkat lewat ParentID;
+// Path berisi ID leluhur sampai kategori itu sendiri, misal "/1/5/".
+type Kategori struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Nama      string    `gorm:"size:100;not null" json:"nama"`
+	ParentID  *uint     `json:"parent_id"`
+	Path      string    `gorm:"size:255;not null" json:"-"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Kategori) TableName() string {
+	return "kategori"
+}
+
+// Model struct for brand table
+type Brand struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Nama      string    `gorm:"size:100;not null" json:"nama"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Brand) TableName() string {
+	return "brand"
+}
+
+// KlasifikasiFilter adalah filter kategori, brand dan atribut barang (dipakai daftar barang dan laporan)
+type KlasifikasiFilter struct {
+	KategoriID uint // termasuk seluruh sub kategori
+	BrandID    uint
+	Atribut    map[string]string // semua pasangan atribut harus cocok
+}
+
+// KategoriWithCount adalah kategori beserta jumlah barang (termasuk sub kategori)
+type KategoriWithCount struct {
+	Kategori
+	JumlahBarang int64
+}
+
+// Request and Response structs for kategori & brand API
+type KategoriRequest struct {
+	Nama     string `json:"nama"`
+	ParentID *uint  `json:"parent_id"` // kosong = kategori utama
+}
+
+type KategoriResponse struct {
+	ID           uint   `json:"id"`
+	Nama         string `json:"nama"`
+	ParentID     *uint  `json:"parent_id"`
+	Level        int    `json:"level"`        // 1 = kategori utama
+	NamaLengkap  string `json:"nama_lengkap"` // misal "Elektronik > Peripherals > Mouse"
+	JumlahBarang int64  `json:"jumlah_barang"`
+}
+
+type BrandRequest struct {
+	Nama string `json:"nama"`
+}
+
+type BrandResponse struct {
+	ID           uint   `json:"id"`
+	Nama         string `json:"nama"`
+	JumlahBarang int64  `json:"jumlah_barang"`
+}
+
+type DeleteKategoriResponse struct {
+	Message string `json:"message"`
+}
+
+type DeleteBrandResponse struct {
+	Message string `json:"message"`
+}
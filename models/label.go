@@ -0,0 +1,30 @@
+package models
+
+// LabelTemplate adalah tata letak label barang. Ukuran dalam mm.
+// Format "pdf" mengatur label dalam grid pada halaman (kertas label A4); format "zpl" satu label per printer thermal.
+type LabelTemplate struct {
+	Name        string  `json:"name"`
+	Format      string  `json:"format"` // pdf atau zpl
+	PageWidth   float64 `json:"page_width,omitempty"`
+	PageHeight  float64 `json:"page_height,omitempty"`
+	Columns     int     `json:"columns,omitempty"`
+	Rows        int     `json:"rows,omitempty"`
+	LabelWidth  float64 `json:"label_width"`
+	LabelHeight float64 `json:"label_height"`
+	MarginLeft  float64 `json:"margin_left,omitempty"`
+	MarginTop   float64 `json:"margin_top,omitempty"`
+	GapX        float64 `json:"gap_x,omitempty"`
+	GapY        float64 `json:"gap_y,omitempty"`
+	DPI         int     `json:"dpi,omitempty"` // zpl: 203 atau 300
+	ShowName    bool    `json:"show_name"`
+	ShowPrice   bool    `json:"show_price"`
+	FontSize    float64 `json:"font_size"` // pt
+}
+
+// LabelItem adalah data satu barang yang dicetak pada label
+type LabelItem struct {
+	KodeBarang string
+	NamaBarang string
+	HargaJual  float64
+	Barcode    string // isi barcode sesuai simbologi (kode_barang, atau EAN-13 untuk ean13)
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Model struct for login_audit table
+type LoginAudit struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    *uint     `json:"user_id"`
+	Email     string    `gorm:"size:150;not null" json:"email"`
+	IPAddress string    `gorm:"column:ip_address;size:64;not null" json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	Success   bool      `gorm:"not null" json:"success"`
+	Reason    string    `gorm:"size:100" json:"reason"` // "success", "invalid_credentials", "invalid_2fa", "locked", "ip_blocked", "too_fast"
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (LoginAudit) TableName() string {
+	return "login_audit"
+}
@@ -0,0 +1,18 @@
+package models
+
+// Cursor adalah posisi terakhir pada keyset pagination: nilai kolom urutan dan ID baris terakhir.
+// ID 0 berarti halaman pertama.
+type Cursor struct {
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+// PageMeta adalah metadata paginasi pada response list. Mode page/limit mengisi page, total dan total_pages;
+// mode cursor hanya mengisi limit dan next_cursor.
+type PageMeta struct {
+	Page       int     `json:"page,omitempty"`
+	Limit      int     `json:"limit"`
+	Total      *int64  `json:"total,omitempty"`
+	TotalPages *int64  `json:"total_pages,omitempty"`
+	NextCursor *string `json:"next_cursor"` // nil jika tidak ada halaman berikutnya
+}
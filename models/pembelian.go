@@ -2,6 +2,12 @@ package models
 
 import "time"
 
+// Status pembelian. Draft belum menambah stok dan tidak dihitung di laporan
+const (
+	StatusPembelianDraft   = "draft"
+	StatusPembelianSelesai = "selesai"
+)
+
 type BeliHeader struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	NoFaktur  string    `gorm:"type:varchar(100);unique;not null" json:"no_faktur"`
@@ -9,8 +15,14 @@ type BeliHeader struct {
 	Total     float64   `gorm:"type:decimal(15,2);default:0" json:"total"`
 	UserID    uint      `gorm:"not null" json:"user_id"`
 	Status    string    `gorm:"type:varchar(50);default:'selesai'" json:"status"`
+	APIKeyID  *uint     `json:"api_key_id"` // diisi jika transaksi dibuat melalui API key
 	CreatedAt time.Time `json:"created_at"`
 
+	ConfirmedAt *time.Time `json:"confirmed_at"` // diisi saat pembelian draft dikonfirmasi
+	ConfirmedBy *uint      `json:"confirmed_by"` // user yang mengonfirmasi pembelian draft
+
+	JumlahItem int `gorm:"->;-:migration" json:"-"` // jumlah baris detail, hanya diisi oleh query list
+
 	// Associations
 	Details []BeliDetail `gorm:"foreignKey:BeliHeaderID" json:"details,omitempty"` // BeliHeader one to many BeliDetail
 	User    *User        `gorm:"foreignKey:UserID" json:"user,omitempty"`          // BeliHeader many to one User
@@ -39,6 +51,7 @@ func (BeliDetail) TableName() string {
 // Request structs for pembelian API
 type BeliDetailRequest struct {
 	BarangID uint    `json:"barang_id"`
+	Barcode  string  `json:"barcode"` // alternatif barang_id: barcode atau kode_barang; jika keduanya dikirim harus menunjuk barang yang sama
 	Qty      int     `json:"qty"`
 	Harga    float64 `json:"harga"`
 }
@@ -50,14 +63,18 @@ type BeliHeaderRequest struct {
 
 // Response structs for pembelian API
 type BeliHeaderResponse struct {
-	ID        uint               `json:"id"`
-	NoFaktur  string             `json:"no_faktur"`
-	Supplier  string             `json:"supplier"`
-	Total     float64            `json:"total"`
-	UserID    uint               `json:"user_id"`
-	Status    string             `json:"status"`
-	CreatedAt time.Time          `json:"created_at"`
-	User      UserSimpleResponse `json:"user"`
+	ID          uint               `json:"id"`
+	NoFaktur    string             `json:"no_faktur"`
+	Supplier    string             `json:"supplier"`
+	Total       float64            `json:"total"`
+	UserID      uint               `json:"user_id"`
+	Status      string             `json:"status"`
+	APIKeyID    *uint              `json:"api_key_id,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	ConfirmedAt *time.Time         `json:"confirmed_at,omitempty"`
+	ConfirmedBy *uint              `json:"confirmed_by,omitempty"`
+	JumlahItem  int                `json:"jumlah_item,omitempty"` // hanya pada list
+	User        UserSimpleResponse `json:"user"`
 }
 
 type BeliDetailResponse struct {
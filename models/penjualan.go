@@ -9,8 +9,11 @@ type JualHeader struct {
 	Total     float64   `gorm:"type:decimal(15,2);default:0" json:"total"`
 	UserID    uint      `gorm:"not null" json:"user_id"`
 	Status    string    `gorm:"type:varchar(50);default:'selesai'" json:"status"`
+	APIKeyID  *uint     `json:"api_key_id"` // diisi jika transaksi dibuat melalui API key
 	CreatedAt time.Time `json:"created_at"`
 
+	JumlahItem int `gorm:"->;-:migration" json:"-"` // jumlah baris detail, hanya diisi oleh query list
+
 	// Associations
 	Details []JualDetail `gorm:"foreignKey:JualHeaderID" json:"details,omitempty"` // JualHeader one to many JualDetail
 	User    *User        `gorm:"foreignKey:UserID" json:"user,omitempty"`          // JualHeader many to one User
@@ -28,6 +31,9 @@ type JualDetail struct {
 	Harga        float64 `gorm:"type:decimal(15,2);not null" json:"harga"`
 	Subtotal     float64 `gorm:"type:decimal(15,2);not null" json:"subtotal"`
 
+	// HPP per unit saat transaksi (snapshot master_barang.harga_beli), NULL untuk data lama
+	HargaPokok *float64 `gorm:"type:decimal(15,2)" json:"harga_pokok"`
+
 	// Associations
 	MasterBarang *MasterBarang `gorm:"foreignKey:BarangID" json:"barang,omitempty"` // JualDetail many to one MasterBarang
 }
@@ -39,6 +45,7 @@ func (JualDetail) TableName() string {
 // Request structs for penjualan API
 type JualDetailRequest struct {
 	BarangID uint    `json:"barang_id"`
+	Barcode  string  `json:"barcode"` // alternatif barang_id: barcode atau kode_barang; jika keduanya dikirim harus menunjuk barang yang sama
 	Qty      int     `json:"qty"`
 	Harga    float64 `json:"harga"`
 }
@@ -50,14 +57,16 @@ type JualHeaderRequest struct {
 
 // Response structs for penjualan API
 type JualHeaderResponse struct {
-	ID        uint               `json:"id"`
-	NoFaktur  string             `json:"no_faktur"`
-	Customer  string             `json:"customer"`
-	Total     float64            `json:"total"`
-	UserID    uint               `json:"user_id"`
-	Status    string             `json:"status"`
-	CreatedAt time.Time          `json:"created_at"`
-	User      UserSimpleResponse `json:"user"`
+	ID         uint               `json:"id"`
+	NoFaktur   string             `json:"no_faktur"`
+	Customer   string             `json:"customer"`
+	Total      float64            `json:"total"`
+	UserID     uint               `json:"user_id"`
+	Status     string             `json:"status"`
+	APIKeyID   *uint              `json:"api_key_id,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	JumlahItem int                `json:"jumlah_item,omitempty"` // hanya pada list
+	User       UserSimpleResponse `json:"user"`
 }
 
 type JualDetailResponse struct {
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Jenis masalah yang ditemukan saat rekonsiliasi ledger stok
+const (
+	LedgerIssueChainBreak  = "chain_break"  // stok_sebelum tidak sama dengan stok_sesudah baris sebelumnya
+	LedgerIssueRowMismatch = "row_mismatch" // stok_sesudah tidak sama dengan stok_sebelum +/- jumlah
+	LedgerIssueDrift       = "drift"        // mstok.stok_akhir tidak sama dengan stok_sesudah history terakhir
+)
+
+// LedgerBreakRow adalah baris history_stok yang tidak konsisten dengan baris sebelumnya atau dengan jumlahnya sendiri
+type LedgerBreakRow struct {
+	ID             uint
+	BarangID       uint
+	CreatedAt      time.Time
+	JenisTransaksi string
+	Jumlah         int
+	StokSebelum    int
+	StokSesudah    int
+	PrevSesudah    int        // stok_sesudah baris sebelumnya (0 untuk baris pertama)
+	PrevCreatedAt  *time.Time // nil untuk baris pertama
+}
+
+// LedgerDriftRow adalah barang dengan stok_akhir yang berbeda dari ledger history_stok
+type LedgerDriftRow struct {
+	BarangID   uint
+	KodeBarang string
+	NamaBarang string
+	StokAkhir  int
+	StokLedger int // stok_sesudah history terakhir (0 jika belum ada history)
+}
+
+// Response structs for reconciliation API
+type LedgerIssue struct {
+	Type       string     `json:"type"`
+	HistoryID  *uint      `json:"history_id,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	Expected   int        `json:"expected"`
+	Actual     int        `json:"actual"`
+	Fixed      bool       `json:"fixed"`
+	FixID      *uint      `json:"fix_history_id,omitempty"` // history adjustment yang dibuat sebagai koreksi
+	Keterangan string     `json:"keterangan"`
+}
+
+type LedgerReconcileResult struct {
+	BarangID   uint          `json:"barang_id"`
+	KodeBarang string        `json:"kode_barang"`
+	NamaBarang string        `json:"nama_barang"`
+	Issues     []LedgerIssue `json:"issues"`
+}
+
+type LedgerReconcileResponse struct {
+	CheckedAt    time.Time               `json:"checked_at"`
+	Fix          bool                    `json:"fix"`
+	JumlahBarang int                     `json:"jumlah_barang"` // barang yang bermasalah
+	JumlahIssue  int                     `json:"jumlah_issue"`
+	JumlahFixed  int                     `json:"jumlah_fixed"`
+	Results      []LedgerReconcileResult `json:"results"`
+}
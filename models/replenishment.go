@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// Model struct for supplier_lead_times table
+type SupplierLeadTime struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Supplier     string    `gorm:"type:varchar(200);unique;not null" json:"supplier"`
+	LeadTimeDays int       `gorm:"not null" json:"lead_time_days"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (SupplierLeadTime) TableName() string {
+	return "supplier_lead_times"
+}
+
+// Request struct for supplier lead time API
+type SupplierLeadTimeRequest struct {
+	Supplier     string `json:"supplier"`
+	LeadTimeDays int    `json:"lead_time_days"`
+}
+
+// ReplenishmentUsage adalah hasil query pemakaian barang (barang keluar) beserta stok dan supplier terakhir
+type ReplenishmentUsage struct {
+	BarangID     uint
+	KodeBarang   string
+	NamaBarang   string
+	Satuan       string
+	HargaBeli    float64
+	StokAkhir    int
+	StokMinimum  int
+	StokMaksimum int
+	ReorderQty   int
+	TotalKeluar  int
+	Supplier     *string // supplier pembelian terakhir, nil jika belum pernah dibeli
+	LeadTimeDays *int    // lead time supplier, nil jika belum diset
+}
+
+// ReplenishmentParams adalah parameter perhitungan saran pembelian
+type ReplenishmentParams struct {
+	WindowDays      int    `json:"window_days"`       // periode perhitungan rata-rata pemakaian harian
+	SafetyDays      int    `json:"safety_days"`       // safety stock dalam hari pemakaian
+	CoverDays       int    `json:"cover_days"`        // jumlah hari pemakaian yang ditutup oleh satu kali pesan
+	DefaultLeadTime int    `json:"default_lead_time"` // lead time untuk supplier yang belum diset
+	Supplier        string `json:"supplier,omitempty"`
+}
+
+// Response structs for replenishment API
+type ReplenishmentSuggestion struct {
+	BarangID       uint    `json:"barang_id"`
+	KodeBarang     string  `json:"kode_barang"`
+	NamaBarang     string  `json:"nama_barang"`
+	Satuan         string  `json:"satuan"`
+	Supplier       string  `json:"supplier"`
+	StokAkhir      int     `json:"stok_akhir"`
+	TotalKeluar    int     `json:"total_keluar"`
+	RataRataHarian float64 `json:"rata_rata_harian"`
+	LeadTimeDays   int     `json:"lead_time_days"`
+	SafetyStock    int     `json:"safety_stock"`
+	ReorderPoint   int     `json:"reorder_point"`
+	TargetStok     int     `json:"target_stok"`
+	SaranQty       int     `json:"saran_qty"`
+	HargaBeli      float64 `json:"harga_beli"`
+	Subtotal       float64 `json:"subtotal"`
+}
+
+type ReplenishmentResponse struct {
+	Params ReplenishmentParams       `json:"params"`
+	Data   []ReplenishmentSuggestion `json:"data"`
+}
+
+// Request struct for draft pembelian from suggestions
+type ReplenishmentDraftRequest struct {
+	WindowDays      int    `json:"window_days"`
+	SafetyDays      int    `json:"safety_days"`
+	CoverDays       int    `json:"cover_days"`
+	Supplier        string `json:"supplier"`         // hanya saran untuk supplier ini
+	BarangIDs       []uint `json:"barang_ids"`       // opsional, hanya barang tertentu
+	DefaultSupplier string `json:"default_supplier"` // supplier untuk barang yang belum pernah dibeli
+}
+
+type ReplenishmentDraftResponse struct {
+	Drafts  []PembelianResponse       `json:"drafts"`
+	Skipped []ReplenishmentSuggestion `json:"skipped"` // saran tanpa supplier (isi default_supplier)
+}
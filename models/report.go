@@ -0,0 +1,203 @@
+package models
+
+import "time"
+
+// ReportFilter adalah filter umum untuk endpoint laporan
+type ReportFilter struct {
+	From     time.Time // inklusif
+	To       time.Time // eksklusif (hari setelah tanggal "to" pada query)
+	GroupBy  string
+	BarangID uint
+	UserID   uint
+	Party    string // customer (penjualan) atau supplier (pembelian)
+	KlasifikasiFilter
+
+	KategoriLevel int    // group_by kategori: level kategori yang dijadikan kelompok (1 = kategori utama)
+	AtributKey    string // group_by atribut: nama atribut yang dijadikan kelompok
+}
+
+// StockReportGroup adalah total laporan stok per kategori / brand / atribut (group_by)
+type StockReportGroup struct {
+	Key        string  `json:"key"`
+	Label      string  `json:"label"`
+	JumlahItem int     `json:"jumlah_item"`
+	TotalQty   int64   `json:"total_qty"`
+	TotalNilai float64 `json:"total_nilai"`
+}
+
+// Response structs for penjualan/pembelian report API
+type TransactionReportRow struct {
+	Key             string  `json:"key"`
+	Label           string  `json:"label"`
+	JumlahTransaksi int64   `json:"jumlah_transaksi"`
+	TotalQty        int64   `json:"total_qty"`
+	TotalNilai      float64 `json:"total_nilai"`
+}
+
+type TransactionReportSummary struct {
+	JumlahTransaksi int64   `json:"jumlah_transaksi"`
+	TotalQty        int64   `json:"total_qty"`
+	TotalNilai      float64 `json:"total_nilai"`
+}
+
+type TransactionReportResponse struct {
+	GroupBy string                   `json:"group_by"`
+	From    string                   `json:"from"`
+	To      string                   `json:"to"`
+	Rows    []TransactionReportRow   `json:"rows"`
+	Summary TransactionReportSummary `json:"summary"`
+}
+
+// Response structs for margin report API
+type MarginReportRow struct {
+	Key          string  `json:"key"`
+	Label        string  `json:"label"`
+	TotalQty     int64   `json:"total_qty"`
+	Penjualan    float64 `json:"penjualan"`
+	HPP          float64 `json:"hpp"`
+	Margin       float64 `json:"margin"`
+	MarginPersen float64 `json:"margin_persen"`
+}
+
+type MarginReportSummary struct {
+	TotalQty     int64   `json:"total_qty"`
+	Penjualan    float64 `json:"penjualan"`
+	HPP          float64 `json:"hpp"`
+	Margin       float64 `json:"margin"`
+	MarginPersen float64 `json:"margin_persen"`
+}
+
+type MarginReportResponse struct {
+	GroupBy string              `json:"group_by"`
+	From    string              `json:"from"`
+	To      string              `json:"to"`
+	Rows    []MarginReportRow   `json:"rows"`
+	Summary MarginReportSummary `json:"summary"`
+}
+
+// Response structs for ABC analysis API
+type ABCReportRow struct {
+	BarangID        uint    `json:"barang_id"`
+	KodeBarang      string  `json:"kode_barang"`
+	NamaBarang      string  `json:"nama_barang"`
+	TotalQty        int64   `json:"total_qty"`
+	TotalNilai      float64 `json:"total_nilai"`
+	Persen          float64 `json:"persen"`              // kontribusi terhadap total penjualan
+	PersenKumulatif float64 `json:"persen_kumulatif"`    // kumulatif setelah barang ini
+	Kelas           string  `json:"kelas"`               // A, B atau C
+	GroupKey        string  `json:"group_key,omitempty"` // diisi jika group_by kategori, brand atau atribut
+	GroupLabel      string  `json:"group_label,omitempty"`
+}
+
+type ABCClassSummary struct {
+	Kelas      string  `json:"kelas"`
+	JumlahItem int     `json:"jumlah_item"`
+	TotalNilai float64 `json:"total_nilai"`
+	Persen     float64 `json:"persen"`
+}
+
+type ABCReportResponse struct {
+	From    string             `json:"from"`
+	To      string             `json:"to"`
+	BatasA  float64            `json:"batas_a"`
+	BatasB  float64            `json:"batas_b"`
+	Rows    []ABCReportRow     `json:"rows"`
+	Summary []ABCClassSummary  `json:"summary"`
+	GroupBy string             `json:"group_by,omitempty"`
+	Groups  []StockReportGroup `json:"groups,omitempty"` // total_qty dan total_nilai penjualan per kelompok
+}
+
+// Response structs for dead stock API
+type DeadStockRow struct {
+	BarangID        uint       `json:"barang_id"`
+	KodeBarang      string     `json:"kode_barang"`
+	NamaBarang      string     `json:"nama_barang"`
+	Satuan          string     `json:"satuan"`
+	StokAkhir       int        `json:"stok_akhir"`
+	NilaiStok       float64    `json:"nilai_stok"`
+	TerakhirKeluar  *time.Time `json:"terakhir_keluar"` // nil jika belum pernah keluar
+	HariTanpaKeluar *int       `json:"hari_tanpa_keluar"`
+	GroupKey        string     `json:"group_key,omitempty"`
+	GroupLabel      string     `json:"group_label,omitempty"`
+}
+
+type DeadStockResponse struct {
+	Days           int                `json:"days"`
+	JumlahItem     int                `json:"jumlah_item"`
+	TotalNilaiStok float64            `json:"total_nilai_stok"`
+	Rows           []DeadStockRow     `json:"rows"`
+	GroupBy        string             `json:"group_by,omitempty"`
+	Groups         []StockReportGroup `json:"groups,omitempty"` // total_qty dan total_nilai stok per kelompok
+}
+
+// Response structs for stock aging API
+type StockAgingRow struct {
+	BarangID           uint       `json:"barang_id"`
+	KodeBarang         string     `json:"kode_barang"`
+	NamaBarang         string     `json:"nama_barang"`
+	Satuan             string     `json:"satuan"`
+	StokAkhir          int        `json:"stok_akhir"`
+	NilaiStok          float64    `json:"nilai_stok"`
+	PenerimaanTerakhir *time.Time `json:"penerimaan_terakhir"` // pembelian terakhir, nil jika belum pernah dibeli
+	UmurHari           *int       `json:"umur_hari"`
+	Bucket             string     `json:"bucket"`
+	GroupKey           string     `json:"group_key,omitempty"`
+	GroupLabel         string     `json:"group_label,omitempty"`
+}
+
+type StockAgingBucket struct {
+	Bucket     string  `json:"bucket"`
+	JumlahItem int     `json:"jumlah_item"`
+	TotalQty   int     `json:"total_qty"`
+	NilaiStok  float64 `json:"nilai_stok"`
+}
+
+type StockAgingResponse struct {
+	Buckets []StockAgingBucket `json:"buckets"`
+	Rows    []StockAgingRow    `json:"rows"`
+	GroupBy string             `json:"group_by,omitempty"`
+	Groups  []StockReportGroup `json:"groups,omitempty"` // total_qty dan total_nilai stok per kelompok
+}
+
+// Response structs for inventory turnover API
+type TurnoverReportRow struct {
+	BarangID          uint     `json:"barang_id"`
+	KodeBarang        string   `json:"kode_barang"`
+	NamaBarang        string   `json:"nama_barang"`
+	Satuan            string   `json:"satuan"`
+	QtyTerjual        int64    `json:"qty_terjual"`
+	HPP               float64  `json:"hpp"`                  // COGS dalam periode
+	RataRataStok      float64  `json:"rata_rata_stok"`       // rata-rata qty stok tertimbang waktu
+	RataRataNilaiStok float64  `json:"rata_rata_nilai_stok"` // rata_rata_stok x harga_beli
+	Turnover          *float64 `json:"turnover"`             // hpp / rata_rata_nilai_stok, nil jika tidak ada stok
+	DaysOfSupply      *float64 `json:"days_of_supply"`       // hari periode / turnover, nil jika tidak ada penjualan
+	GroupKey          string   `json:"group_key,omitempty"`
+	GroupLabel        string   `json:"group_label,omitempty"`
+}
+
+type TurnoverReportSummary struct {
+	HPP               float64  `json:"hpp"`
+	RataRataNilaiStok float64  `json:"rata_rata_nilai_stok"`
+	Turnover          *float64 `json:"turnover"`
+	DaysOfSupply      *float64 `json:"days_of_supply"`
+}
+
+type TurnoverReportResponse struct {
+	From    string                `json:"from"`
+	To      string                `json:"to"`
+	Days    float64               `json:"days"`
+	Sort    string                `json:"sort"`
+	Order   string                `json:"order"`
+	Rows    []TurnoverReportRow   `json:"rows"`
+	Summary TurnoverReportSummary `json:"summary"`
+	GroupBy string                `json:"group_by,omitempty"`
+	Groups  []TurnoverReportGroup `json:"groups,omitempty"`
+}
+
+// TurnoverReportGroup adalah turnover per kategori / brand / atribut (group_by)
+type TurnoverReportGroup struct {
+	Key        string `json:"key"`
+	Label      string `json:"label"`
+	JumlahItem int    `json:"jumlah_item"`
+	TurnoverReportSummary
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Model struct for stock_alerts table
+type StockAlert struct {
+	ID           uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	BarangID     uint       `gorm:"not null" json:"barang_id"`
+	StokSebelum  int        `gorm:"not null" json:"stok_sebelum"`
+	StokSesudah  int        `gorm:"not null" json:"stok_sesudah"`
+	ReorderPoint int        `gorm:"not null" json:"reorder_point"`
+	Keterangan   string     `json:"keterangan"`
+	ResolvedAt   *time.Time `json:"resolved_at"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	GroupKey   string `gorm:"->;-:migration" json:"-"` // group_by kategori / brand / atribut, hanya diisi oleh query list
+	GroupLabel string `gorm:"->;-:migration" json:"-"`
+
+	// Associations
+	MasterBarang MasterBarang `gorm:"foreignKey:BarangID;references:ID" json:"barang"` // StockAlert many to one MasterBarang
+}
+
+func (StockAlert) TableName() string {
+	return "stock_alerts"
+}
+
+// Response struct for stock alert API
+type StockAlertResponse struct {
+	ID           uint                 `json:"id"`
+	BarangID     uint                 `json:"barang_id"`
+	StokSebelum  int                  `json:"stok_sebelum"`
+	StokSesudah  int                  `json:"stok_sesudah"`
+	ReorderPoint int                  `json:"reorder_point"`
+	Keterangan   string               `json:"keterangan"`
+	ResolvedAt   *time.Time           `json:"resolved_at"`
+	CreatedAt    time.Time            `json:"created_at"`
+	GroupKey     string               `json:"group_key,omitempty"`
+	GroupLabel   string               `json:"group_label,omitempty"`
+	Barang       BarangSimpleResponse `json:"barang"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Model struct for stock_snapshots table
+type StockSnapshot struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	BarangID   uint      `gorm:"not null" json:"barang_id"`
+	SnapshotAt time.Time `gorm:"not null" json:"snapshot_at"` // stok mencakup history dengan created_at < snapshot_at
+	Stok       int       `gorm:"not null" json:"stok"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (StockSnapshot) TableName() string {
+	return "stock_snapshots"
+}
+
+// StokAsOfRow adalah hasil rekonstruksi stok barang pada waktu tertentu
+type StokAsOfRow struct {
+	BarangID   uint
+	KodeBarang string
+	NamaBarang string
+	Satuan     string
+	HargaJual  float64
+	HargaBeli  float64
+	Stok       int
+	GroupKey   string
+	GroupLabel string
+}
+
+// Response structs for point-in-time stock API
+type StokAsOfResponse struct {
+	BarangID   uint               `json:"barang_id"`
+	StokAkhir  int                `json:"stok_akhir"`
+	GroupKey   string             `json:"group_key,omitempty"`
+	GroupLabel string             `json:"group_label,omitempty"`
+	Barang     BarangStokResponse `json:"barang"`
+}
+
+type StockSnapshotResponse struct {
+	SnapshotAt time.Time `json:"snapshot_at"`
+	JumlahItem int64     `json:"jumlah_item"`
+}
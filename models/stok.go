@@ -11,6 +11,15 @@ type Mstok struct {
 	StokAkhir int       `gorm:"default:0" json:"stok_akhir"`
 	UpdatedAt time.Time `json:"updated_at"`
 
+	// Reorder level: stok dianggap rendah jika stok_akhir <= StokMinimum (atau LOW_STOCK_THRESHOLD jika 0)
+	StokMinimum  int `gorm:"default:0" json:"stok_minimum"`
+	StokMaksimum int `gorm:"default:0" json:"stok_maksimum"`
+	ReorderQty   int `gorm:"default:0" json:"reorder_qty"`
+
+	// Kelompok barang (group_by kategori, brand atau atribut), hanya diisi oleh query list stok
+	GroupKey   string `gorm:"->;-:migration" json:"-"`
+	GroupLabel string `gorm:"->;-:migration" json:"-"`
+
 	// Associations
 	MasterBarang MasterBarang `gorm:"foreignKey:BarangID;references:ID" json:"barang"`
 }
@@ -19,13 +28,26 @@ func (Mstok) TableName() string {
 	return "mstok"
 }
 
+// ReorderPoint mengembalikan stok_minimum, atau defaultThreshold jika stok_minimum belum diset
+func (s Mstok) ReorderPoint(defaultThreshold int) int {
+	if s.StokMinimum > 0 {
+		return s.StokMinimum
+	}
+	return defaultThreshold
+}
+
 // Response struct for mstok API
 type MstokResponse struct {
-	ID        uint               `json:"id"`
-	BarangID  uint               `json:"barang_id"`
-	StokAkhir int                `json:"stok_akhir"`
-	UpdatedAt time.Time          `json:"updated_at"`
-	Barang    BarangStokResponse `json:"barang"`
+	ID           uint               `json:"id"`
+	BarangID     uint               `json:"barang_id"`
+	StokAkhir    int                `json:"stok_akhir"`
+	StokMinimum  int                `json:"stok_minimum"`
+	StokMaksimum int                `json:"stok_maksimum"`
+	ReorderQty   int                `json:"reorder_qty"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+	GroupKey     string             `json:"group_key,omitempty"`
+	GroupLabel   string             `json:"group_label,omitempty"`
+	Barang       BarangStokResponse `json:"barang"`
 }
 
 type BarangStokResponse struct {
@@ -34,3 +56,28 @@ type BarangStokResponse struct {
 	Satuan     string  `json:"satuan"`
 	HargaJual  float64 `json:"harga_jual"`
 }
+
+// Request structs for reorder level & adjustment API
+type ReorderLevelRequest struct {
+	StokMinimum  int `json:"stok_minimum"`
+	StokMaksimum int `json:"stok_maksimum"`
+	ReorderQty   int `json:"reorder_qty"`
+}
+
+type StokAdjustmentRequest struct {
+	Jumlah     int    `json:"jumlah"` // positif menambah stok, negatif mengurangi stok
+	Keterangan string `json:"keterangan"`
+}
+
+// Response struct for low stock API
+type LowStockResponse struct {
+	BarangID     uint               `json:"barang_id"`
+	StokAkhir    int                `json:"stok_akhir"`
+	ReorderPoint int                `json:"reorder_point"`
+	StokMaksimum int                `json:"stok_maksimum"`
+	ReorderQty   int                `json:"reorder_qty"`
+	SaranPesan   int                `json:"saran_pesan"` // jumlah yang disarankan untuk dipesan
+	GroupKey     string             `json:"group_key,omitempty"`
+	GroupLabel   string             `json:"group_label,omitempty"`
+	Barang       BarangStokResponse `json:"barang"`
+}
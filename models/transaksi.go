@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// TransaksiFilter adalah filter dan paginasi untuk daftar pembelian / penjualan
+type TransaksiFilter struct {
+	From     time.Time // inklusif, zero = tanpa batas
+	To       time.Time // eksklusif, zero = tanpa batas
+	Party    string    // supplier (pembelian) atau customer (penjualan), sebagian nama
+	Status   string
+	UserID   uint
+	NoFaktur string // sebagian no faktur
+	Limit    int
+	Offset   int
+	Cursor   *Cursor // mode cursor: Offset diabaikan dan total tidak dihitung
+
+	WithDetails bool // include=details: detail dan barang ikut dimuat (bentuk response lama)
+}
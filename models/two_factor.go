@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Model struct for user_backup_codes table
+type UserBackupCode struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint       `gorm:"not null" json:"user_id"`
+	CodeHash  string     `gorm:"not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (UserBackupCode) TableName() string {
+	return "user_backup_codes"
+}
+
+// Request and Response structs for 2FA API
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"` // kode TOTP 6 digit (atau backup code saat login)
+}
+
+type TwoFactorActivateResponse struct {
+	Message     string   `json:"message"`
+	BackupCodes []string `json:"backup_codes"`
+}
+
+type TwoFactorRequirementRequest struct {
+	Required bool `json:"required"`
+}
+
+type TwoFactorMessageResponse struct {
+	Message string `json:"message"`
+}
@@ -11,6 +11,17 @@ type User struct {
 	Role      string    `gorm:"not null" json:"role"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Brute-force protection
+	FailedLoginCount  int        `gorm:"default:0" json:"-"`
+	LastFailedLoginAt *time.Time `json:"-"`
+	LockedUntil       *time.Time `json:"locked_until,omitempty"`
+
+	// Two-factor authentication (TOTP)
+	TOTPSecret   string `gorm:"column:totp_secret" json:"-"`
+	TOTPEnabled  bool   `gorm:"column:totp_enabled;default:false" json:"totp_enabled"`
+	TOTPRequired bool   `gorm:"column:totp_required;default:false" json:"totp_required"`
+	TOTPLastStep int64  `gorm:"column:totp_last_step;default:0" json:"-"`
 }
 
 type RegisterRequest struct {
@@ -32,5 +43,17 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token string `json:"token,omitempty"`
+
+	// Diisi jika akun memakai 2FA: kirim ChallengeToken sebagai Bearer token + kode ke /api/auth/login/2fa
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+
+	// Diisi jika 2FA wajib tetapi belum diaktifkan: SetupToken hanya berlaku untuk endpoint /api/auth/2fa
+	TwoFactorSetupRequired bool   `json:"two_factor_setup_required,omitempty"`
+	SetupToken             string `json:"setup_token,omitempty"`
+}
+
+type UnlockUserResponse struct {
+	Message string `json:"message"`
 }
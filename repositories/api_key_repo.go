@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidAPIKey dikembalikan jika API key tidak dikenal, sudah dicabut atau kedaluwarsa
+var ErrInvalidAPIKey = errors.New("API key tidak valid")
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) Create(key *models.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *APIKeyRepository) GetByID(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.Preload("User").First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// List mengambil semua API key (terbaru dahulu)
+func (r *APIKeyRepository) List() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := r.db.Preload("User").Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Revoke menonaktifkan API key secara permanen
+func (r *APIKeyRepository) Revoke(id uint) error {
+	result := r.db.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ValidateAPIKey mencari API key berdasarkan prefix, mencocokkan hash, mengecek masa berlaku dan mencatat last_used_at.
+// User yang diwakili key ikut dimuat (key.User); key ditolak jika user tersebut sudah tidak ada.
+func (r *APIKeyRepository) ValidateAPIKey(raw string) (*models.APIKey, error) {
+	prefix, ok := utils.APIKeyPrefix(raw)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	var key models.APIKey
+	if err := r.db.Preload("User").Where("prefix = ? AND revoked_at IS NULL", prefix).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(utils.HashAPIKey(raw))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+		return nil, ErrInvalidAPIKey
+	}
+	if key.User == nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// Cukup perbarui last_used_at paling sering sekali per menit agar tidak menulis ke DB di setiap request
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > time.Minute {
+		if err := r.db.Model(&models.APIKey{}).Where("id = ?", key.ID).Update("last_used_at", now).Error; err != nil {
+			return nil, err
+		}
+		key.LastUsedAt = &now
+	}
+
+	return &key, nil
+}
@@ -1,10 +1,15 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+
 	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BarangRepository struct {
@@ -35,8 +40,18 @@ func (r *BarangRepository) Create(b *models.MasterBarang) error {
 	})
 }
 
-func (r *BarangRepository) Update(b *models.MasterBarang) error {
-	return r.db.Save(b).Error
+// Update menyimpan perubahan barang dan mencatat riwayat harga jika harga beli/jual berubah
+func (r *BarangRepository) Update(b *models.MasterBarang, userID uint, apiKeyID *uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before models.MasterBarang
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&before, b.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(b).Error; err != nil {
+			return err
+		}
+		return recordHargaChange(tx, before, *b, models.HargaSumberManual, "", userID, apiKeyID)
+	})
 }
 
 func (r *BarangRepository) Delete(id uint) error {
@@ -76,8 +91,9 @@ func (r *BarangRepository) GetByID(id uint) (*models.MasterBarang, error) {
 func (r *BarangRepository) GetDetailByID(id uint) (*models.BarangWithStock, error) {
 	var b models.BarangWithStock
 	err := r.db.Table("master_barang").
-		Select("master_barang.*, mstok.stok_akhir").
+		Select("master_barang.*, mstok.stok_akhir, "+barangKlasifikasiColumns).
 		Joins("JOIN mstok ON mstok.barang_id = master_barang.id").
+		Joins(barangKlasifikasiJoins).
 		Where("master_barang.id = ?", id).
 		First(&b).Error
 	if err != nil {
@@ -94,35 +110,260 @@ func (r *BarangRepository) GetByKode(kode string) (*models.MasterBarang, error)
 	return &b, nil
 }
 
-func (r *BarangRepository) List(search string, page, limit int) ([]models.BarangWithStock, int64, error) {
+// GetByKodes mengambil barang berdasarkan daftar kode_barang, dikembalikan sebagai map kode -> barang
+func (r *BarangRepository) GetByKodes(kodes []string) (map[string]models.MasterBarang, error) {
+	result := make(map[string]models.MasterBarang, len(kodes))
+	if len(kodes) == 0 {
+		return result, nil
+	}
+	var list []models.MasterBarang
+	if err := r.db.Where("kode_barang IN ?", kodes).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, b := range list {
+		result[b.KodeBarang] = b
+	}
+	return result, nil
+}
+
+// MaxID mengambil ID barang terbesar (0 jika belum ada barang)
+func (r *BarangRepository) MaxID() (uint, error) {
+	var id uint
+	err := r.db.Model(&models.MasterBarang{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
+
+// KategoriExists mengecek apakah kategori dengan id tersebut ada
+func (r *BarangRepository) KategoriExists(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Kategori{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// BrandExists mengecek apakah brand dengan id tersebut ada
+func (r *BarangRepository) BrandExists(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Brand{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// KategoriIDsByNama mengambil ID kategori per nama (huruf kecil). Nama kategori hanya unik per parent,
+// sehingga satu nama bisa memiliki beberapa ID.
+func (r *BarangRepository) KategoriIDsByNama(names []string) (map[string][]uint, error) {
+	result := make(map[string][]uint)
+	if len(names) == 0 {
+		return result, nil
+	}
+	var list []models.Kategori
+	if err := r.db.Where("LOWER(nama) IN ?", names).Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, k := range list {
+		key := strings.ToLower(k.Nama)
+		result[key] = append(result[key], k.ID)
+	}
+	return result, nil
+}
+
+// BrandIDsByNama mengambil ID brand per nama (huruf kecil)
+func (r *BarangRepository) BrandIDsByNama(names []string) (map[string]uint, error) {
+	result := make(map[string]uint)
+	if len(names) == 0 {
+		return result, nil
+	}
+	var list []models.Brand
+	if err := r.db.Where("LOWER(nama) IN ?", names).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, b := range list {
+		result[strings.ToLower(b.Nama)] = b.ID
+	}
+	return result, nil
+}
+
+// Import membuat atau memperbarui barang (dicocokkan berdasarkan kode_barang) dalam satu transaksi.
+// Barang baru dibuat beserta mstok-nya; stok awal dicatat sebagai history "adjustment".
+// Barang tanpa kode_barang mendapat kode otomatis seperti Create.
+func (r *BarangRepository) Import(rows []models.BarangImportRow, userID uint, apiKeyID *uint) (created, updated int, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			var barang models.MasterBarang
+			if row.KodeBarang != "" {
+				err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("kode_barang = ?", row.KodeBarang).First(&barang).Error
+				if err == nil {
+					before := barang
+					barang.NamaBarang = row.Request.NamaBarang
+					barang.Satuan = row.Request.Satuan
+					if row.Filled["deskripsi"] {
+						barang.Deskripsi = row.Request.Deskripsi
+					}
+					if row.Filled["harga_beli"] {
+						barang.HargaBeli = row.Request.HargaBeli
+					}
+					if row.Filled["harga_jual"] {
+						barang.HargaJual = row.Request.HargaJual
+					}
+					if row.Filled["kategori"] {
+						barang.KategoriID = row.Request.KategoriID
+					}
+					if row.Filled["brand"] {
+						barang.BrandID = row.Request.BrandID
+					}
+					if err := tx.Save(&barang).Error; err != nil {
+						return err
+					}
+					if err := recordHargaChange(tx, before, barang, models.HargaSumberImport, "Import barang", userID, apiKeyID); err != nil {
+						return err
+					}
+					updated++
+					continue
+				}
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+			}
+
+			barang = models.MasterBarang{
+				KodeBarang: row.KodeBarang,
+				NamaBarang: row.Request.NamaBarang,
+				Deskripsi:  row.Request.Deskripsi,
+				Satuan:     row.Request.Satuan,
+				HargaBeli:  row.Request.HargaBeli,
+				HargaJual:  row.Request.HargaJual,
+				KategoriID: row.Request.KategoriID,
+				BrandID:    row.Request.BrandID,
+			}
+			if err := tx.Create(&barang).Error; err != nil {
+				return err
+			}
+			if barang.KodeBarang == "" {
+				barang.KodeBarang = fmt.Sprintf("BRG%03d", barang.ID)
+				if err := tx.Save(&barang).Error; err != nil {
+					return err
+				}
+			}
+
+			stok := models.Mstok{
+				BarangID:  barang.ID,
+				StokAkhir: row.StokAwal,
+			}
+			if err := tx.Create(&stok).Error; err != nil {
+				return err
+			}
+			if row.StokAwal > 0 {
+				history := models.HistoryStok{
+					BarangID:       barang.ID,
+					UserID:         userID,
+					JenisTransaksi: "adjustment",
+					Jumlah:         row.StokAwal,
+					StokSebelum:    0,
+					StokSesudah:    row.StokAwal,
+					Keterangan:     "Stok awal (import barang)",
+					APIKeyID:       apiKeyID,
+				}
+				if err := tx.Create(&history).Error; err != nil {
+					return err
+				}
+			}
+			created++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return created, updated, nil
+}
+
+// Kondisi pencarian barang: full-text search (kode, nama, deskripsi), substring kode/nama,
+// atau kemiripan trigram nama/deskripsi untuk salah ketik (mis. "logitek")
+const barangSearchCondition = `(master_barang.search_vector @@ websearch_to_tsquery('simple', ?)
+	OR master_barang.kode_barang ILIKE ? OR master_barang.nama_barang ILIKE ?
+	OR ? <% master_barang.nama_barang OR ? <% master_barang.deskripsi)`
+
+// Nama kategori dan brand untuk daftar/detail barang
+const (
+	barangKlasifikasiJoins   = "LEFT JOIN kategori ON kategori.id = master_barang.kategori_id LEFT JOIN brand ON brand.id = master_barang.brand_id"
+	barangKlasifikasiColumns = "COALESCE(kategori.nama, '') AS nama_kategori, COALESCE(brand.nama, '') AS nama_brand"
+)
+
+// Skor relevansi: rank full-text ditambah kemiripan trigram terbaik antara nama, kode dan deskripsi.
+// Kemiripan deskripsi diberi bobot setengah, seperti bobot B deskripsi di search_vector.
+const barangRelevanceExpr = `ts_rank(master_barang.search_vector, websearch_to_tsquery('simple', ?))
+	+ GREATEST(word_similarity(?, master_barang.nama_barang), similarity(?, master_barang.kode_barang),
+		0.5 * word_similarity(?, master_barang.deskripsi))`
+
+func (r *BarangRepository) List(f models.BarangFilter) ([]models.BarangWithStock, int64, error) {
 	var items []models.BarangWithStock
 	var total int64
 
-	// Base query with join
-	q := r.db.Table("master_barang").
-		Select("master_barang.*, mstok.stok_akhir").
-		Joins("JOIN mstok ON mstok.barang_id = master_barang.id")
+	q := r.listQuery(f)
+
+	if f.Cursor == nil {
+		// Count total matching records
+		if err := r.listQuery(f).Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		q = q.Offset(f.Offset)
+	} else if f.Cursor.ID != 0 {
+		// Keyset pagination: lanjut setelah (kode_barang, id) terakhir
+		q = q.Where("(master_barang.kode_barang, master_barang.id) > (?, ?)", f.Cursor.Value, f.Cursor.ID)
+	}
 
-	if search != "" {
-		like := "%" + search + "%"
-		q = q.Where("master_barang.kode_barang ILIKE ? OR master_barang.nama_barang ILIKE ?", like, like)
+	// Hasil pencarian diurutkan berdasarkan relevansi, kecuali mode cursor yang selalu urut kode_barang
+	if f.Search != "" && f.Cursor == nil {
+		q = q.Select("master_barang.*, mstok.stok_akhir, "+barangKlasifikasiColumns+", "+barangRelevanceExpr+" AS relevansi", f.Search, f.Search, f.Search, f.Search).
+			Order("relevansi DESC")
+	} else {
+		q = q.Select("master_barang.*, mstok.stok_akhir, " + barangKlasifikasiColumns)
 	}
 
-	// Count total matching records
-	if err := q.Count(&total).Error; err != nil {
+	if err := q.Order("master_barang.kode_barang ASC, master_barang.id ASC").Limit(f.Limit).Scan(&items).Error; err != nil {
 		return nil, 0, err
 	}
+	return items, total, nil
+}
 
-	if page <= 0 {
-		page = 1
+// EachBarang memanggil fn untuk setiap barang yang cocok dengan filter (tanpa paginasi), dengan urutan yang sama seperti List
+func (r *BarangRepository) EachBarang(f models.BarangFilter, fn func(models.BarangWithStock) error) error {
+	q := r.listQuery(f)
+	if f.Search != "" {
+		q = q.Select("master_barang.*, mstok.stok_akhir, "+barangKlasifikasiColumns+", "+barangRelevanceExpr+" AS relevansi", f.Search, f.Search, f.Search, f.Search).
+			Order("relevansi DESC")
+	} else {
+		q = q.Select("master_barang.*, mstok.stok_akhir, " + barangKlasifikasiColumns)
 	}
-	if limit <= 0 {
-		limit = 10
+	return eachRow(r.db, q.Order("master_barang.kode_barang ASC, master_barang.id ASC"), fn)
+}
+
+// listQuery menerapkan pencarian dan filter satuan, rentang harga jual, status stok serta kategori, brand dan atribut
+func (r *BarangRepository) listQuery(f models.BarangFilter) *gorm.DB {
+	q := r.db.Table("master_barang").
+		Joins("JOIN mstok ON mstok.barang_id = master_barang.id").
+		Joins(barangKlasifikasiJoins)
+	q = applyKlasifikasiFilter(q, f.KlasifikasiFilter, "master_barang")
+
+	if f.Search != "" {
+		like := "%" + escapeLike(f.Search) + "%"
+		q = q.Where(barangSearchCondition, f.Search, like, like, f.Search, f.Search)
+	}
+	if len(f.Satuan) > 0 {
+		q = q.Where("LOWER(master_barang.satuan) IN ?", f.Satuan)
+	}
+	if f.MinHarga != nil {
+		q = q.Where("master_barang.harga_jual >= ?", *f.MinHarga)
+	}
+	if f.MaxHarga != nil {
+		q = q.Where("master_barang.harga_jual <= ?", *f.MaxHarga)
 	}
-	offset := (page - 1) * limit
 
-	if err := q.Order("master_barang.kode_barang ASC").Limit(limit).Offset(offset).Scan(&items).Error; err != nil {
-		return nil, 0, err
+	switch f.StockStatus {
+	case models.StockStatusInStock:
+		q = q.Where("mstok.stok_akhir > 0")
+	case models.StockStatusLow:
+		q = q.Where("mstok.stok_akhir > 0 AND "+lowStockCondition, utils.LowStockThreshold())
+	case models.StockStatusOut:
+		q = q.Where("mstok.stok_akhir <= 0")
 	}
-	return items, total, nil
+	return q
 }
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"errors"
+
+	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrBarcodeSudahDipakai dikembalikan jika barcode sudah terdaftar untuk barang lain (atau barang yang sama)
+var ErrBarcodeSudahDipakai = errors.New("barcode sudah dipakai")
+
+// ErrBarcodeBarangBerbeda dikembalikan jika barang_id dan barcode yang dikirim bersamaan menunjuk barang yang berbeda
+var ErrBarcodeBarangBerbeda = errors.New("barcode bukan milik barang_id")
+
+// GetBarcodes mengambil seluruh barcode barang
+func (r *BarangRepository) GetBarcodes(barangID uint) ([]models.BarangBarcode, error) {
+	var list []models.BarangBarcode
+	if err := r.db.Where("barang_id = ?", barangID).Order("id ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// AddBarcode menambahkan barcode ke barang. Barcode disimpan sebagai GTIN-14; barcode yang sudah terdaftar
+// (termasuk yang ditambahkan bersamaan oleh request lain) menghasilkan ErrBarcodeSudahDipakai.
+func (r *BarangRepository) AddBarcode(barangID uint, barcode string) (*models.BarangBarcode, error) {
+	b := models.BarangBarcode{BarangID: barangID, Barcode: utils.NormalizeGTIN(barcode)}
+	result := r.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "barcode"}}, DoNothing: true}).Create(&b)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrBarcodeSudahDipakai
+	}
+	return &b, nil
+}
+
+// DeleteBarcode menghapus barcode dari barang (barcode boleh dalam bentuk EAN-8/UPC-A/EAN-13 maupun GTIN-14)
+func (r *BarangRepository) DeleteBarcode(barangID uint, barcode string) error {
+	result := r.db.Where("barang_id = ? AND barcode = ?", barangID, utils.NormalizeGTIN(barcode)).Delete(&models.BarangBarcode{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetByIDs mengambil barang berdasarkan daftar ID, urut kode_barang
+func (r *BarangRepository) GetByIDs(ids []uint) ([]models.MasterBarang, error) {
+	var list []models.MasterBarang
+	if err := r.db.Where("id IN ?", ids).Order("kode_barang ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetBarcodesByBarangIDs mengambil barcode beberapa barang sekaligus, dikembalikan sebagai map barang_id -> barcode
+func (r *BarangRepository) GetBarcodesByBarangIDs(ids []uint) (map[uint][]string, error) {
+	var list []models.BarangBarcode
+	if err := r.db.Where("barang_id IN ?", ids).Order("id ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uint][]string)
+	for _, b := range list {
+		result[b.BarangID] = append(result[b.BarangID], b.Barcode)
+	}
+	return result, nil
+}
+
+// GetDetailByBarcode mengambil barang beserta stok berdasarkan barcode, atau kode_barang jika barcode tidak terdaftar
+// (label barang memakai kode_barang sebagai barcode Code128)
+func (r *BarangRepository) GetDetailByBarcode(barcode string) (*models.BarangWithStock, error) {
+	id, err := r.barangIDByBarcode(barcode)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetDetailByID(id)
+}
+
+// GetByIDOrBarcode mengambil barang berdasarkan barangID dan/atau barcode/kode_barang.
+// Jika keduanya dikirim dan menunjuk barang yang berbeda, ErrBarcodeBarangBerbeda dikembalikan.
+// Dipakai untuk detail pembelian dan penjualan.
+func (r *BarangRepository) GetByIDOrBarcode(barangID uint, barcode string) (*models.MasterBarang, error) {
+	if barcode != "" {
+		id, err := r.barangIDByBarcode(barcode)
+		if err != nil {
+			return nil, err
+		}
+		if barangID != 0 && id != barangID {
+			return nil, ErrBarcodeBarangBerbeda
+		}
+		barangID = id
+	}
+	if barangID == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.GetByID(barangID)
+}
+
+func (r *BarangRepository) barangIDByBarcode(barcode string) (uint, error) {
+	var b models.BarangBarcode
+	err := r.db.Where("barcode = ?", utils.NormalizeGTIN(barcode)).First(&b).Error
+	if err == nil {
+		return b.BarangID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	barang, err := r.GetByKode(barcode)
+	if err != nil {
+		return 0, err
+	}
+	return barang.ID, nil
+}
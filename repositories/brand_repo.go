@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"errors"
+
+	"warehouse-inventory-server/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrBrandDuplikat dikembalikan jika nama brand sudah ada (tidak case-sensitive)
+	ErrBrandDuplikat = errors.New("brand dengan nama yang sama sudah ada")
+	// ErrBrandDipakai dikembalikan saat menghapus brand yang masih dipakai barang
+	ErrBrandDipakai = errors.New("brand masih dipakai barang")
+)
+
+type BrandRepository struct {
+	db *gorm.DB
+}
+
+func NewBrandRepository(db *gorm.DB) *BrandRepository {
+	return &BrandRepository{db: db}
+}
+
+// List mengambil seluruh brand (urut nama) beserta jumlah barangnya
+func (r *BrandRepository) List() ([]models.BrandResponse, error) {
+	var list []models.BrandResponse
+	err := r.db.Table("brand").
+		Select("brand.id, brand.nama, COUNT(b.id) AS jumlah_barang").
+		Joins("LEFT JOIN master_barang b ON b.brand_id = brand.id").
+		Group("brand.id, brand.nama").
+		Order("LOWER(brand.nama) ASC").
+		Scan(&list).Error
+	return list, err
+}
+
+func (r *BrandRepository) GetByID(id uint) (*models.Brand, error) {
+	var b models.Brand
+	if err := r.db.First(&b, id).Error; err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *BrandRepository) Create(b *models.Brand) error {
+	if err := r.checkNama(b.Nama, 0); err != nil {
+		return err
+	}
+	return r.db.Create(b).Error
+}
+
+func (r *BrandRepository) Update(b *models.Brand) error {
+	if err := r.checkNama(b.Nama, b.ID); err != nil {
+		return err
+	}
+	return r.db.Save(b).Error
+}
+
+// Delete menghapus brand yang sudah tidak dipakai barang
+func (r *BrandRepository) Delete(id uint) error {
+	var used int64
+	if err := r.db.Model(&models.MasterBarang{}).Where("brand_id = ?", id).Count(&used).Error; err != nil {
+		return err
+	}
+	if used > 0 {
+		return ErrBrandDipakai
+	}
+
+	result := r.db.Delete(&models.Brand{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *BrandRepository) checkNama(nama string, exceptID uint) error {
+	var count int64
+	if err := r.db.Model(&models.Brand{}).Where("LOWER(nama) = LOWER(?) AND id <> ?", nama, exceptID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrBrandDuplikat
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"time"
+
+	"warehouse-inventory-server/models"
+
+	"gorm.io/gorm"
+)
+
+type DashboardRepository struct {
+	db *gorm.DB
+}
+
+func NewDashboardRepository(db *gorm.DB) *DashboardRepository {
+	return &DashboardRepository{db: db}
+}
+
+// TransactionTotal menghitung jumlah dan total nilai transaksi selesai pada tabel header (jual_header / beli_header) dalam rentang waktu.
+// Pembelian draft yang dikonfirmasi dihitung pada waktu konfirmasinya. Dengan filter kategori/brand/atribut,
+// total dihitung dari subtotal detail barang yang cocok saja.
+func (r *DashboardRepository) TransactionTotal(headerTable string, from, to time.Time, f models.KlasifikasiFilter) (models.DashboardTransactionTotal, error) {
+	src := penjualanSource
+	if headerTable == pembelianSource.header {
+		src = pembelianSource
+	}
+
+	var result models.DashboardTransactionTotal
+	q := r.db.Table(src.header+" h").
+		Where("h.status = ?", "selesai").
+		Where(src.tanggal+" >= ? AND "+src.tanggal+" < ?", from, to)
+	if f.KategoriID == 0 && f.BrandID == 0 && len(f.Atribut) == 0 {
+		q = q.Select("COUNT(*) AS jumlah_transaksi, COALESCE(SUM(h.total), 0) AS total")
+	} else {
+		q = q.Joins("JOIN " + src.detail + " d ON d." + src.headerFK + " = h.id").
+			Joins("JOIN master_barang b ON b.id = d.barang_id").
+			Select("COUNT(DISTINCT h.id) AS jumlah_transaksi, COALESCE(SUM(d.subtotal), 0) AS total")
+		q = applyKlasifikasiFilter(q, f, "b")
+	}
+	err := q.Scan(&result).Error
+	return result, err
+}
+
+// StockValue menghitung total nilai stok (stok_akhir x harga_beli)
+func (r *DashboardRepository) StockValue(f models.KlasifikasiFilter) (float64, error) {
+	var total float64
+	err := r.stokQuery(f).
+		Select("COALESCE(SUM(mstok.stok_akhir * b.harga_beli), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// CountLowStock menghitung barang dengan stok di bawah atau sama dengan titik reorder
+func (r *DashboardRepository) CountLowStock(defaultThreshold int, f models.KlasifikasiFilter) (int64, error) {
+	var total int64
+	err := r.stokQuery(f).Where(lowStockCondition, defaultThreshold).Count(&total).Error
+	return total, err
+}
+
+// StockGroups menghitung jumlah barang, total stok dan nilai stok per group_by
+func (r *DashboardRepository) StockGroups(f models.ReportFilter) ([]models.StockReportGroup, error) {
+	return barangGroupTotals(r.stokQuery(f.KlasifikasiFilter), f, "mstok.stok_akhir", "mstok.stok_akhir * b.harga_beli")
+}
+
+func (r *DashboardRepository) stokQuery(f models.KlasifikasiFilter) *gorm.DB {
+	q := r.db.Table("mstok").Joins("JOIN master_barang b ON b.id = mstok.barang_id")
+	return applyKlasifikasiFilter(q, f, "b")
+}
+
+// TopSellers mengambil barang dengan nilai penjualan terbesar dalam rentang waktu
+func (r *DashboardRepository) TopSellers(from, to time.Time, limit int, f models.KlasifikasiFilter) ([]models.DashboardTopBarang, error) {
+	var rows []models.DashboardTopBarang
+	q := r.db.Table("jual_detail d").
+		Joins("JOIN jual_header h ON h.id = d.jual_header_id").
+		Joins("JOIN master_barang b ON b.id = d.barang_id").
+		Select("b.id AS barang_id, b.kode_barang, b.nama_barang, SUM(d.qty) AS total_qty, SUM(d.subtotal) AS total_nilai").
+		Where("h.created_at >= ? AND h.created_at < ?", from, to)
+	err := applyKlasifikasiFilter(q, f, "b").
+		Group("b.id, b.kode_barang, b.nama_barang").
+		Order("total_nilai DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// RecentHistory mengambil pergerakan stok terbaru
+func (r *DashboardRepository) RecentHistory(limit int, f models.KlasifikasiFilter) ([]models.HistoryStok, error) {
+	var list []models.HistoryStok
+	q := r.db.Preload("MasterBarang").Preload("Users")
+	if f.KategoriID != 0 || f.BrandID != 0 || len(f.Atribut) > 0 {
+		q = q.Where("history_stok.barang_id IN (?)", applyKlasifikasiFilter(r.db.Table("master_barang b").Select("b.id"), f, "b"))
+	}
+	err := q.Order("created_at DESC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import "gorm.io/gorm"
+
+// eachRow menjalankan query dan memanggil fn untuk setiap baris secara streaming,
+// tanpa memuat seluruh hasil ke memori. Dipakai untuk export CSV/XLSX.
+func eachRow[T any](db *gorm.DB, q *gorm.DB, fn func(T) error) error {
+	rows, err := q.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item T
+		if err := db.ScanRows(rows, &item); err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
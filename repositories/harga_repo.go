@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"warehouse-inventory-server/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrHargaNegatif dikembalikan jika perubahan harga membuat harga menjadi negatif
+var ErrHargaNegatif = errors.New("harga baru tidak boleh negatif")
+
+// GetHargaTargets mengambil barang yang dipilih untuk bulk update harga: berdasarkan barang_ids,
+// filter pencarian (sama seperti daftar barang) atau supplier (pernah dibeli dari supplier tersebut)
+func (r *BarangRepository) GetHargaTargets(req models.BulkHargaRequest) ([]models.MasterBarang, error) {
+	var list []models.MasterBarang
+	var q *gorm.DB
+
+	switch {
+	case len(req.BarangIDs) > 0:
+		q = r.db.Table("master_barang").Where("master_barang.id IN ?", req.BarangIDs)
+	case req.Filter != nil:
+		q = r.listQuery(models.BarangFilter{
+			Search:      req.Filter.Search,
+			Satuan:      req.Filter.Satuan,
+			StockStatus: req.Filter.StockStatus,
+			KlasifikasiFilter: models.KlasifikasiFilter{
+				KategoriID: req.Filter.KategoriID,
+				BrandID:    req.Filter.BrandID,
+			},
+		})
+	default:
+		q = r.db.Table("master_barang").Where(`EXISTS (
+			SELECT 1 FROM beli_detail d JOIN beli_header h ON h.id = d.beli_header_id
+			WHERE d.barang_id = master_barang.id AND h.status = ? AND LOWER(h.supplier) = ?)`,
+			models.StatusPembelianSelesai, strings.ToLower(strings.TrimSpace(req.Supplier)))
+	}
+
+	if err := q.Select("master_barang.*").Order("master_barang.kode_barang ASC").Scan(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// BulkUpdateHarga mengunci barang dan menghitung ulang harga barunya dengan compute (dari harga terkini),
+// lalu menyimpan harga dan riwayatnya dalam satu transaksi
+func (r *BarangRepository) BulkUpdateHarga(ids []uint, compute func(models.MasterBarang) models.PerubahanHarga, keterangan string, userID uint, apiKeyID *uint) ([]models.PerubahanHarga, error) {
+	var changes []models.PerubahanHarga
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var list []models.MasterBarang
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Order("kode_barang ASC").Find(&list).Error; err != nil {
+			return err
+		}
+
+		for _, barang := range list {
+			change := compute(barang)
+			if change.HargaBeliBaru < 0 || change.HargaJualBaru < 0 {
+				return ErrHargaNegatif
+			}
+			before := barang
+			barang.HargaBeli = change.HargaBeliBaru
+			barang.HargaJual = change.HargaJualBaru
+			if err := tx.Model(&barang).Updates(map[string]interface{}{
+				"harga_beli": barang.HargaBeli,
+				"harga_jual": barang.HargaJual,
+			}).Error; err != nil {
+				return err
+			}
+			if err := recordHargaChange(tx, before, barang, models.HargaSumberBulk, keterangan, userID, apiKeyID); err != nil {
+				return err
+			}
+			changes = append(changes, change)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetHargaHistory mengambil riwayat perubahan harga (terbaru dahulu) sesuai filter dan total count
+func (r *BarangRepository) GetHargaHistory(f models.HargaHistoryFilter) ([]models.HargaHistory, int64, error) {
+	var list []models.HargaHistory
+	var total int64
+
+	q := r.db.Model(&models.HargaHistory{})
+	if f.BarangID != 0 {
+		q = q.Where("barang_id = ?", f.BarangID)
+	}
+	if f.UserID != 0 {
+		q = q.Where("user_id = ?", f.UserID)
+	}
+	if f.Sumber != "" {
+		q = q.Where("sumber = ?", f.Sumber)
+	}
+	if !f.From.IsZero() {
+		q = q.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("created_at < ?", f.To)
+	}
+
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Preload("MasterBarang").Preload("User").
+		Order("created_at DESC, id DESC").
+		Limit(f.Limit).Offset(f.Offset).
+		Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// recordHargaChange mencatat riwayat harga jika harga beli atau harga jual berubah
+func recordHargaChange(tx *gorm.DB, before, after models.MasterBarang, sumber, keterangan string, userID uint, apiKeyID *uint) error {
+	if before.HargaBeli == after.HargaBeli && before.HargaJual == after.HargaJual {
+		return nil
+	}
+	history := models.HargaHistory{
+		BarangID:      after.ID,
+		HargaBeliLama: before.HargaBeli,
+		HargaBeliBaru: after.HargaBeli,
+		HargaJualLama: before.HargaJual,
+		HargaJualBaru: after.HargaJual,
+		Sumber:        sumber,
+		Keterangan:    keterangan,
+		UserID:        userID,
+		APIKeyID:      apiKeyID,
+	}
+	return tx.Create(&history).Error
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"warehouse-inventory-server/models"
+)
+
+// ABCSales mengambil total penjualan (jual_detail) per barang dalam rentang tanggal f.From - f.To, termasuk barang
+// tanpa penjualan, diurutkan dari nilai terbesar. Filter kategori/brand/atribut dan group_by diambil dari f.
+func (r *ReportRepository) ABCSales(f models.ReportFilter) ([]models.ABCReportRow, error) {
+	q := applyKlasifikasiFilter(r.db.Table("master_barang b"), f.KlasifikasiFilter, "b")
+	q, group, args := barangGroupSelect(q, f)
+	groupBy := "b.id, b.kode_barang, b.nama_barang"
+	if group != "" {
+		groupBy += ", group_key, group_label"
+	}
+
+	var rows []models.ABCReportRow
+	err := q.Select("b.id AS barang_id, b.kode_barang, b.nama_barang, COALESCE(SUM(d.qty), 0) AS total_qty, COALESCE(SUM(d.subtotal), 0) AS total_nilai"+group, args...).
+		Joins(`LEFT JOIN jual_detail d ON d.barang_id = b.id AND d.jual_header_id IN (
+			SELECT id FROM jual_header WHERE status = 'selesai' AND created_at >= ? AND created_at < ?
+		)`, f.From, f.To).
+		Group(groupBy).
+		Order("total_nilai DESC, b.kode_barang ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// DeadStock mengambil barang yang masih memiliki stok tetapi tidak ada history_stok "keluar" sejak tanggal since
+func (r *ReportRepository) DeadStock(since time.Time, f models.ReportFilter) ([]models.DeadStockRow, error) {
+	q := r.db.Table("mstok s").Joins("JOIN master_barang b ON b.id = s.barang_id")
+	q = applyKlasifikasiFilter(q, f.KlasifikasiFilter, "b")
+	q, group, args := barangGroupSelect(q, f)
+
+	var rows []models.DeadStockRow
+	err := q.Select(`b.id AS barang_id, b.kode_barang, b.nama_barang, b.satuan, s.stok_akhir,
+			s.stok_akhir * b.harga_beli AS nilai_stok, k.terakhir_keluar`+group, args...).
+		Joins(`LEFT JOIN (
+			SELECT barang_id, MAX(created_at) AS terakhir_keluar
+			FROM history_stok
+			WHERE jenis_transaksi = 'keluar'
+			GROUP BY barang_id
+		) k ON k.barang_id = s.barang_id`).
+		Where("s.stok_akhir > 0").
+		Where("k.terakhir_keluar IS NULL OR k.terakhir_keluar < ?", since).
+		Order("nilai_stok DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// StockAging mengambil barang yang masih memiliki stok beserta tanggal pembelian (beli_detail) terakhirnya
+func (r *ReportRepository) StockAging(f models.ReportFilter) ([]models.StockAgingRow, error) {
+	q := r.db.Table("mstok s").Joins("JOIN master_barang b ON b.id = s.barang_id")
+	q = applyKlasifikasiFilter(q, f.KlasifikasiFilter, "b")
+	q, group, args := barangGroupSelect(q, f)
+
+	var rows []models.StockAgingRow
+	err := q.Select(`b.id AS barang_id, b.kode_barang, b.nama_barang, b.satuan, s.stok_akhir,
+			s.stok_akhir * b.harga_beli AS nilai_stok, p.penerimaan_terakhir`+group, args...).
+		Joins(`LEFT JOIN (
+			SELECT d.barang_id, MAX(COALESCE(h.confirmed_at, h.created_at)) AS penerimaan_terakhir
+			FROM beli_detail d
+			JOIN beli_header h ON h.id = d.beli_header_id
+			WHERE h.status = 'selesai'
+			GROUP BY d.barang_id
+		) p ON p.barang_id = s.barang_id`).
+		Where("s.stok_akhir > 0").
+		Order("p.penerimaan_terakhir ASC NULLS FIRST, b.kode_barang ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// Kolom yang boleh dipakai untuk mengurutkan laporan turnover
+var turnoverSortColumns = map[string]string{
+	"kode_barang":    "b.kode_barang",
+	"hpp":            "hpp",
+	"rata_rata_stok": "rata_rata_nilai_stok",
+	"turnover":       "turnover",
+	"days_of_supply": "days_of_supply",
+}
+
+// TurnoverReport menghitung HPP, rata-rata stok tertimbang waktu, turnover dan days of supply per barang
+// pada rentang [from, to). Level stok direkonstruksi dari history_stok: level awal adalah stok_sesudah terakhir
+// sebelum from (atau stok_sebelum pertama dalam periode, atau stok_akhir jika tidak ada pergerakan),
+// kemudian setiap baris history mengubah level menjadi stok_sesudah.
+// Rentang, filter barang/kategori/brand/atribut dan group_by diambil dari f.
+func (r *ReportRepository) TurnoverReport(f models.ReportFilter, sort string, desc bool) ([]models.TurnoverReportRow, error) {
+	orderBy, ok := turnoverSortColumns[sort]
+	if !ok {
+		return nil, fmt.Errorf("sort %q tidak dikenal", sort)
+	}
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	sql := `
+WITH opening AS (
+	SELECT b.id AS barang_id, COALESCE(
+		(SELECT h.stok_sesudah FROM history_stok h WHERE h.barang_id = b.id AND h.created_at < @from ORDER BY h.created_at DESC, h.id DESC LIMIT 1),
+		(SELECT h.stok_sebelum FROM history_stok h WHERE h.barang_id = b.id AND h.created_at >= @from ORDER BY h.created_at ASC, h.id ASC LIMIT 1),
+		s.stok_akhir, 0) AS level
+	FROM master_barang b
+	LEFT JOIN mstok s ON s.barang_id = b.id
+),
+points AS (
+	SELECT barang_id, CAST(@from AS timestamp) AS t, 0 AS seq, level FROM opening
+	UNION ALL
+	SELECT barang_id, created_at, id, stok_sesudah FROM history_stok WHERE created_at >= @from AND created_at < @to
+),
+segments AS (
+	SELECT barang_id, level,
+		EXTRACT(EPOCH FROM (LEAD(t, 1, CAST(@to AS timestamp)) OVER (PARTITION BY barang_id ORDER BY t, seq) - t)) AS secs
+	FROM points
+),
+avg_stok AS (
+	SELECT barang_id, CAST(SUM(level * secs) / NULLIF(SUM(secs), 0) AS numeric) AS qty
+	FROM segments
+	GROUP BY barang_id
+),
+cogs AS (
+	SELECT d.barang_id, SUM(d.qty) AS qty, SUM(d.qty * COALESCE(d.harga_pokok, mb.harga_beli)) AS hpp
+	FROM jual_detail d
+	JOIN jual_header jh ON jh.id = d.jual_header_id
+	JOIN master_barang mb ON mb.id = d.barang_id
+	WHERE jh.status = 'selesai' AND jh.created_at >= @from AND jh.created_at < @to
+	GROUP BY d.barang_id
+)
+SELECT b.id AS barang_id, b.kode_barang, b.nama_barang, b.satuan,
+	COALESCE(c.qty, 0) AS qty_terjual,
+	COALESCE(c.hpp, 0) AS hpp,
+	ROUND(COALESCE(a.qty, 0), 2) AS rata_rata_stok,
+	ROUND(COALESCE(a.qty, 0) * b.harga_beli, 2) AS rata_rata_nilai_stok,
+	CASE WHEN a.qty * b.harga_beli > 0 THEN ROUND(COALESCE(c.hpp, 0) / (a.qty * b.harga_beli), 2) END AS turnover,
+	CASE WHEN c.hpp > 0 THEN ROUND(COALESCE(a.qty, 0) * b.harga_beli * CAST(@days AS numeric) / c.hpp, 1) END AS days_of_supply
+FROM master_barang b
+LEFT JOIN avg_stok a ON a.barang_id = b.id
+LEFT JOIN cogs c ON c.barang_id = b.id`
+
+	q := r.db.Table("(?) AS t", r.db.Raw(sql, map[string]interface{}{
+		"from": f.From,
+		"to":   f.To,
+		"days": f.To.Sub(f.From).Hours() / 24,
+	})).Joins("JOIN master_barang b ON b.id = t.barang_id")
+	if f.BarangID != 0 {
+		q = q.Where("b.id = ?", f.BarangID)
+	}
+	q = applyKlasifikasiFilter(q, f.KlasifikasiFilter, "b")
+	q, group, args := barangGroupSelect(q, f)
+
+	var rows []models.TurnoverReportRow
+	err := q.Select("t.*"+group, args...).
+		Order(orderBy + " " + direction + " NULLS LAST, b.kode_barang ASC").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"warehouse-inventory-server/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrKategoriDuplikat dikembalikan jika nama kategori sudah ada di bawah parent yang sama
+	ErrKategoriDuplikat = errors.New("kategori dengan nama yang sama sudah ada")
+	// ErrKategoriSiklus dikembalikan jika parent baru adalah kategori itu sendiri atau sub kategorinya
+	ErrKategoriSiklus = errors.New("parent tidak boleh kategori itu sendiri atau sub kategorinya")
+	// ErrKategoriDipakai dikembalikan saat menghapus kategori yang masih memiliki sub kategori atau barang
+	ErrKategoriDipakai = errors.New("kategori masih memiliki sub kategori atau barang")
+)
+
+type KategoriRepository struct {
+	db *gorm.DB
+}
+
+func NewKategoriRepository(db *gorm.DB) *KategoriRepository {
+	return &KategoriRepository{db: db}
+}
+
+// List mengambil seluruh kategori beserta jumlah barang di kategori tersebut dan sub kategorinya
+func (r *KategoriRepository) List() ([]models.KategoriWithCount, error) {
+	var list []models.KategoriWithCount
+	err := r.db.Table("kategori k").
+		Select(`k.*, (
+			SELECT COUNT(*) FROM master_barang b
+			JOIN kategori bk ON bk.id = b.kategori_id
+			WHERE bk.path LIKE k.path || '%'
+		) AS jumlah_barang`).
+		Order("k.path ASC").
+		Scan(&list).Error
+	return list, err
+}
+
+func (r *KategoriRepository) GetByID(id uint) (*models.Kategori, error) {
+	var k models.Kategori
+	if err := r.db.First(&k, id).Error; err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Create membuat kategori baru di bawah parentID (nil = kategori utama)
+func (r *KategoriRepository) Create(k *models.Kategori) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		parentPath, err := kategoriParentPath(tx, k.ParentID)
+		if err != nil {
+			return err
+		}
+		if err := checkKategoriNama(tx, k.Nama, k.ParentID, 0); err != nil {
+			return err
+		}
+		if err := tx.Create(k).Error; err != nil {
+			return err
+		}
+		k.Path = fmt.Sprintf("%s%d/", parentPath, k.ID)
+		return tx.Model(k).Update("path", k.Path).Error
+	})
+}
+
+// Update mengganti nama dan/atau parent kategori. Jika parent berubah, path kategori dan seluruh sub kategorinya ikut dipindah.
+func (r *KategoriRepository) Update(id uint, nama string, parentID *uint) (*models.Kategori, error) {
+	var k models.Kategori
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&k, id).Error; err != nil {
+			return err
+		}
+		parentPath, err := kategoriParentPath(tx, parentID)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(parentPath, k.Path) {
+			return ErrKategoriSiklus
+		}
+		if err := checkKategoriNama(tx, nama, parentID, k.ID); err != nil {
+			return err
+		}
+
+		newPath := fmt.Sprintf("%s%d/", parentPath, k.ID)
+		if newPath != k.Path {
+			err := tx.Model(&models.Kategori{}).
+				Where("path LIKE ?", k.Path+"%").
+				Update("path", gorm.Expr("? || SUBSTRING(path FROM ?)", newPath, len(k.Path)+1)).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		k.Nama = nama
+		k.ParentID = parentID
+		k.Path = newPath
+		return tx.Save(&k).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Delete menghapus kategori yang sudah tidak memiliki sub kategori maupun barang
+func (r *KategoriRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var used int64
+		err := tx.Raw(`SELECT (SELECT COUNT(*) FROM kategori WHERE parent_id = ?) + (SELECT COUNT(*) FROM master_barang WHERE kategori_id = ?)`, id, id).
+			Scan(&used).Error
+		if err != nil {
+			return err
+		}
+		if used > 0 {
+			return ErrKategoriDipakai
+		}
+
+		result := tx.Delete(&models.Kategori{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// kategoriParentPath mengambil path parent (dikunci) atau "/" untuk kategori utama
+func kategoriParentPath(tx *gorm.DB, parentID *uint) (string, error) {
+	if parentID == nil {
+		return "/", nil
+	}
+	var parent models.Kategori
+	if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).First(&parent, *parentID).Error; err != nil {
+		return "", err
+	}
+	return parent.Path, nil
+}
+
+// checkKategoriNama memastikan nama kategori unik (tidak case-sensitive) di bawah parent yang sama
+func checkKategoriNama(tx *gorm.DB, nama string, parentID *uint, exceptID uint) error {
+	var count int64
+	q := tx.Model(&models.Kategori{}).Where("LOWER(nama) = LOWER(?) AND id <> ?", nama, exceptID)
+	if parentID == nil {
+		q = q.Where("parent_id IS NULL")
+	} else {
+		q = q.Where("parent_id = ?", *parentID)
+	}
+	if err := q.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrKategoriDuplikat
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"warehouse-inventory-server/models"
+
+	"gorm.io/gorm"
+)
+
+// applyKlasifikasiFilter menerapkan filter kategori (termasuk seluruh sub kategori), brand dan atribut
+// pada tabel master_barang dengan alias tertentu
+func applyKlasifikasiFilter(q *gorm.DB, f models.KlasifikasiFilter, alias string) *gorm.DB {
+	if f.KategoriID != 0 {
+		q = q.Where(alias+".kategori_id IN (SELECT id FROM kategori WHERE path LIKE (SELECT path FROM kategori WHERE id = ?) || '%')", f.KategoriID)
+	}
+	if f.BrandID != 0 {
+		q = q.Where(alias+".brand_id = ?", f.BrandID)
+	}
+	if len(f.Atribut) > 0 {
+		raw, _ := json.Marshal(f.Atribut)
+		q = q.Where(alias+".atribut @> CAST(? AS jsonb)", string(raw))
+	}
+	return q
+}
+
+// barangGroupColumns menambahkan join untuk group_by kategori, brand atau atribut pada master_barang alias b
+// dan mengembalikan ekspresi key dan label beserta argumennya (argumen key lalu label).
+// group_by kategori menggabungkan sub kategori ke leluhurnya pada level f.KategoriLevel (1 = kategori utama);
+// barang yang kategorinya lebih dangkal dari level tersebut dikelompokkan pada kategorinya sendiri.
+func barangGroupColumns(q *gorm.DB, f models.ReportFilter) (*gorm.DB, string, string, []any) {
+	switch f.GroupBy {
+	case "kategori":
+		level := max(f.KategoriLevel, 1)
+		q = q.Joins("LEFT JOIN kategori bk ON bk.id = b.kategori_id").
+			Joins(fmt.Sprintf("LEFT JOIN kategori gk ON gk.id = CAST(COALESCE(NULLIF(split_part(bk.path, '/', %d), ''), CAST(bk.id AS text)) AS integer)", level+1))
+		return q, "COALESCE(CAST(gk.id AS text), '')", "COALESCE(gk.nama, 'Tanpa kategori')", nil
+	case "brand":
+		q = q.Joins("LEFT JOIN brand gb ON gb.id = b.brand_id")
+		return q, "COALESCE(CAST(gb.id AS text), '')", "COALESCE(gb.nama, 'Tanpa brand')", nil
+	case "atribut":
+		return q, "COALESCE(b.atribut->>?, '')", "COALESCE(b.atribut->>?, ?)", []any{f.AtributKey, f.AtributKey, "Tanpa " + f.AtributKey}
+	}
+	return q, "", "", nil
+}
+
+// barangGroupSelect menambahkan kolom group_key dan group_label untuk laporan stok per barang.
+// Mengembalikan fragmen select kosong jika tidak ada group_by.
+func barangGroupSelect(q *gorm.DB, f models.ReportFilter) (*gorm.DB, string, []any) {
+	q, key, label, args := barangGroupColumns(q, f)
+	if key == "" {
+		return q, "", nil
+	}
+	return q, ", " + key + " AS group_key, " + label + " AS group_label", args
+}
+
+// barangGroupTotals mengagregasi query (yang sudah JOIN master_barang alias b) per group_by:
+// jumlah baris, SUM(qty) dan SUM(nilai), diurutkan dari total_nilai terbesar
+func barangGroupTotals(q *gorm.DB, f models.ReportFilter, qty, nilai string) ([]models.StockReportGroup, error) {
+	q, key, label, args := barangGroupColumns(q, f)
+	if key == "" {
+		return nil, nil
+	}
+	var groups []models.StockReportGroup
+	err := q.Select(key+" AS key, "+label+" AS label, COUNT(*) AS jumlah_item, "+
+		"COALESCE(SUM("+qty+"), 0) AS total_qty, COALESCE(SUM("+nilai+"), 0) AS total_nilai", args...).
+		Group("key, label").
+		Order("total_nilai DESC").
+		Scan(&groups).Error
+	return groups, err
+}
@@ -3,12 +3,20 @@ package repositories
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"warehouse-inventory-server/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrPembelianBukanDraft dikembalikan jika konfirmasi dilakukan pada pembelian yang bukan draft
+var ErrPembelianBukanDraft = errors.New("pembelian bukan draft")
+
+// ErrPembelianHargaBerubah dikembalikan jika harga di draft tidak lagi sama dengan harga beli master barang saat konfirmasi
+var ErrPembelianHargaBerubah = errors.New("harga beli sudah berubah sejak draft dibuat")
+
 type PembelianRepository struct {
 	db *gorm.DB
 }
@@ -39,6 +47,91 @@ func (r *PembelianRepository) CreatePembelian(header *models.BeliHeader, details
 	}
 
 	// Update stok dan buat history untuk setiap detail pembelian
+	if err := applyPembelianStok(tx, header, details); err != nil {
+		tx.Rollback()
+		return err
+	}
+	for i := range details {
+		details[i].BeliHeaderID = header.ID
+	}
+	if len(details) > 0 {
+		if err := tx.Create(&details).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// Jika semua operasi berhasil, commit transaksi
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// CreateDraftPembelian menyimpan pembelian berstatus "draft" tanpa mengubah stok
+func (r *PembelianRepository) CreateDraftPembelian(header *models.BeliHeader, details []models.BeliDetail) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		header.Status = models.StatusPembelianDraft
+		if err := tx.Create(header).Error; err != nil {
+			return err
+		}
+
+		header.NoFaktur = fmt.Sprintf("BLI%03d", header.ID)
+		if err := tx.Model(header).Update("no_faktur", header.NoFaktur).Error; err != nil {
+			return err
+		}
+
+		for i := range details {
+			details[i].BeliHeaderID = header.ID
+		}
+		if len(details) > 0 {
+			if err := tx.Create(&details).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ConfirmPembelian mengubah pembelian "draft" menjadi "selesai" dan menambahkan stok.
+// user_id dan created_at draft tidak diubah; user dan waktu konfirmasi disimpan di confirmed_by / confirmed_at,
+// dan history stok dicatat atas nama user (dan API key) yang mengonfirmasi.
+func (r *PembelianRepository) ConfirmPembelian(id, userID uint, apiKeyID *uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var header models.BeliHeader
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Details.MasterBarang").First(&header, id).Error; err != nil {
+			return err
+		}
+		if header.Status != models.StatusPembelianDraft {
+			return ErrPembelianBukanDraft
+		}
+
+		// Harga draft harus tetap sama dengan harga beli master, seperti saat membuat pembelian
+		for _, d := range header.Details {
+			if d.MasterBarang != nil && d.Harga != d.MasterBarang.HargaBeli {
+				return fmt.Errorf("%w: %s (draft %.2f, harga beli saat ini %.2f)", ErrPembelianHargaBerubah, d.MasterBarang.NamaBarang, d.Harga, d.MasterBarang.HargaBeli)
+			}
+		}
+
+		confirmer := header
+		confirmer.UserID = userID
+		confirmer.APIKeyID = apiKeyID
+		if err := applyPembelianStok(tx, &confirmer, header.Details); err != nil {
+			return err
+		}
+
+		return tx.Model(&header).Updates(map[string]interface{}{
+			"status":       models.StatusPembelianSelesai,
+			"confirmed_at": time.Now(),
+			"confirmed_by": userID,
+		}).Error
+	})
+}
+
+// applyPembelianStok menambah stok dan mencatat history "masuk" untuk setiap detail pembelian
+func applyPembelianStok(tx *gorm.DB, header *models.BeliHeader, details []models.BeliDetail) error {
 	for i := range details {
 		var stok models.Mstok
 		if err := tx.Where("barang_id = ?", details[i].BarangID).First(&stok).Error; err != nil {
@@ -48,11 +141,9 @@ func (r *PembelianRepository) CreatePembelian(header *models.BeliHeader, details
 					StokAkhir: 0,
 				}
 				if errCreate := tx.Create(&stok).Error; errCreate != nil {
-					tx.Rollback()
 					return errCreate
 				}
 			} else {
-				tx.Rollback()
 				return err
 			}
 		}
@@ -62,7 +153,6 @@ func (r *PembelianRepository) CreatePembelian(header *models.BeliHeader, details
 		stokSesudah := stokSebelum + details[i].Qty
 		stok.StokAkhir = stokSesudah
 		if err := tx.Save(&stok).Error; err != nil {
-			tx.Rollback()
 			return err
 		}
 
@@ -75,38 +165,88 @@ func (r *PembelianRepository) CreatePembelian(header *models.BeliHeader, details
 			StokSebelum:    stokSebelum,
 			StokSesudah:    stokSesudah,
 			Keterangan:     "Pembelian " + header.NoFaktur,
+			APIKeyID:       header.APIKeyID,
+			RefType:        models.HistoryRefPembelian,
+			RefID:          &header.ID,
 		}
 		if err := tx.Create(&history).Error; err != nil {
-			tx.Rollback()
 			return err
 		}
 
-		// Set BeliHeaderID untuk detail
-		details[i].BeliHeaderID = header.ID
-	}
-	if len(details) > 0 {
-		if err := tx.Create(&details).Error; err != nil {
-			tx.Rollback()
+		// Tutup stock alert yang masih terbuka jika stok sudah kembali di atas titik reorder
+		if err := checkStockAlert(tx, &stok, stokSebelum, stokSesudah, history.Keterangan); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	// Jika semua operasi berhasil, commit transaksi
-	if err := tx.Commit().Error; err != nil {
-		tx.Rollback()
-		return err
+// GetAllPembelian mengambil header pembelian sesuai filter beserta total count.
+// Detail hanya dimuat jika f.WithDetails (include=details); f.Limit 0 berarti tanpa batas.
+func (r *PembelianRepository) GetAllPembelian(f models.TransaksiFilter) ([]models.BeliHeader, int64, error) {
+	var headers []models.BeliHeader
+	var total int64
+
+	q := r.listQuery(f)
+	if f.Cursor == nil {
+		if err := r.listQuery(f).Model(&models.BeliHeader{}).Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		q = q.Offset(f.Offset)
+	} else if f.Cursor.ID != 0 {
+		q = q.Where(keysetCondition("created_at", true), f.Cursor.Value, f.Cursor.ID)
 	}
 
-	return nil
+	q = q.
+		Select("beli_header.*, (SELECT COUNT(*) FROM beli_detail d WHERE d.beli_header_id = beli_header.id) AS jumlah_item").
+		Preload("User").
+		Order("created_at DESC, id DESC")
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+	if f.WithDetails {
+		q = q.Preload("Details.MasterBarang")
+	}
+	err := q.Find(&headers).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return headers, total, nil
 }
 
-// GetAllPembelian mengambil semua data pembelian beserta detailnya
-func (r *PembelianRepository) GetAllPembelian() ([]models.BeliHeader, error) {
-	var headers []models.BeliHeader
-	if err := r.db.Preload("Details.MasterBarang").Preload("User").Order("created_at desc").Find(&headers).Error; err != nil {
-		return nil, err
+// EachPembelian memanggil fn untuk setiap header pembelian yang cocok dengan filter (tanpa paginasi), terbaru dahulu
+func (r *PembelianRepository) EachPembelian(f models.TransaksiFilter, fn func(models.TransaksiExportRow) error) error {
+	q := r.db.Table("(?) AS h", r.listQuery(f).Model(&models.BeliHeader{})).
+		Select(`h.id, h.no_faktur, h.supplier AS pihak, h.total, COALESCE(h.status, '') AS status,
+			(SELECT COUNT(*) FROM beli_detail d WHERE d.beli_header_id = h.id) AS jumlah_item,
+			COALESCE(u.username, '') AS username, h.created_at`).
+		Joins("LEFT JOIN users u ON u.id = h.user_id").
+		Order("h.created_at DESC, h.id DESC")
+	return eachRow(r.db, q, fn)
+}
+
+// listQuery menerapkan filter tanggal, supplier, status, user dan no faktur
+func (r *PembelianRepository) listQuery(f models.TransaksiFilter) *gorm.DB {
+	q := r.db
+	if !f.From.IsZero() {
+		q = q.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("created_at < ?", f.To)
+	}
+	if f.Party != "" {
+		q = q.Where("supplier ILIKE ?", "%"+escapeLike(f.Party)+"%")
+	}
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
+	}
+	if f.UserID != 0 {
+		q = q.Where("user_id = ?", f.UserID)
+	}
+	if f.NoFaktur != "" {
+		q = q.Where("no_faktur ILIKE ?", "%"+escapeLike(f.NoFaktur)+"%")
 	}
-	return headers, nil
+	return q
 }
 
 // GetPembelianByID mengambil data pembelian berdasarkan ID beserta detailnya
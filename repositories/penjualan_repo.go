@@ -89,11 +89,20 @@ func (r *PenjualanRepository) CreatePenjualan(header *models.JualHeader, details
 			StokSebelum:    stokSebelum,
 			StokSesudah:    stokSesudah,
 			Keterangan:     "Penjualan " + header.NoFaktur,
+			APIKeyID:       header.APIKeyID,
+			RefType:        models.HistoryRefPenjualan,
+			RefID:          &header.ID,
 		}
 		if err := tx.Create(&history).Error; err != nil {
 			tx.Rollback()
 			return err
 		}
+
+		// Buat stock alert jika stok turun melewati titik reorder
+		if err := checkStockAlert(tx, &stok, stokSebelum, stokSesudah, history.Keterangan); err != nil {
+			tx.Rollback()
+			return err
+		}
 	}
 
 	// Buat detail penjualan
@@ -113,13 +122,72 @@ func (r *PenjualanRepository) CreatePenjualan(header *models.JualHeader, details
 	return nil
 }
 
-// GetAllPenjualan mengambil semua data penjualan beserta detailnya
-func (r *PenjualanRepository) GetAllPenjualan() ([]models.JualHeader, error) {
+// GetAllPenjualan mengambil header penjualan sesuai filter beserta total count.
+// Detail hanya dimuat jika f.WithDetails (include=details); f.Limit 0 berarti tanpa batas.
+func (r *PenjualanRepository) GetAllPenjualan(f models.TransaksiFilter) ([]models.JualHeader, int64, error) {
 	var headers []models.JualHeader
-	if err := r.db.Preload("Details.MasterBarang").Preload("User").Order("created_at desc").Find(&headers).Error; err != nil {
-		return nil, err
+	var total int64
+
+	q := r.listQuery(f)
+	if f.Cursor == nil {
+		if err := r.listQuery(f).Model(&models.JualHeader{}).Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		q = q.Offset(f.Offset)
+	} else if f.Cursor.ID != 0 {
+		q = q.Where(keysetCondition("created_at", true), f.Cursor.Value, f.Cursor.ID)
+	}
+
+	q = q.
+		Select("jual_header.*, (SELECT COUNT(*) FROM jual_detail d WHERE d.jual_header_id = jual_header.id) AS jumlah_item").
+		Preload("User").
+		Order("created_at DESC, id DESC")
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+	if f.WithDetails {
+		q = q.Preload("Details.MasterBarang")
+	}
+	err := q.Find(&headers).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return headers, total, nil
+}
+
+// EachPenjualan memanggil fn untuk setiap header penjualan yang cocok dengan filter (tanpa paginasi), terbaru dahulu
+func (r *PenjualanRepository) EachPenjualan(f models.TransaksiFilter, fn func(models.TransaksiExportRow) error) error {
+	q := r.db.Table("(?) AS h", r.listQuery(f).Model(&models.JualHeader{})).
+		Select(`h.id, h.no_faktur, h.customer AS pihak, h.total, COALESCE(h.status, '') AS status,
+			(SELECT COUNT(*) FROM jual_detail d WHERE d.jual_header_id = h.id) AS jumlah_item,
+			COALESCE(u.username, '') AS username, h.created_at`).
+		Joins("LEFT JOIN users u ON u.id = h.user_id").
+		Order("h.created_at DESC, h.id DESC")
+	return eachRow(r.db, q, fn)
+}
+
+// listQuery menerapkan filter tanggal, customer, status, user dan no faktur
+func (r *PenjualanRepository) listQuery(f models.TransaksiFilter) *gorm.DB {
+	q := r.db
+	if !f.From.IsZero() {
+		q = q.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("created_at < ?", f.To)
+	}
+	if f.Party != "" {
+		q = q.Where("customer ILIKE ?", "%"+escapeLike(f.Party)+"%")
+	}
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
+	}
+	if f.UserID != 0 {
+		q = q.Where("user_id = ?", f.UserID)
+	}
+	if f.NoFaktur != "" {
+		q = q.Where("no_faktur ILIKE ?", "%"+escapeLike(f.NoFaktur)+"%")
 	}
-	return headers, nil
+	return q
 }
 
 // GetPenjualanByID mengambil data penjualan berdasarkan ID beserta detailnya
@@ -0,0 +1,288 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"warehouse-inventory-server/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Keterangan history adjustment yang dibuat oleh rekonsiliasi
+const ledgerCorrectionNote = "Koreksi rekonsiliasi ledger"
+
+// FindLedgerBreaks mencari baris history_stok (urut created_at, id per barang) yang stok_sebelum-nya tidak sama
+// dengan stok_sesudah baris sebelumnya (0 untuk baris pertama), atau stok_sesudah-nya tidak sama dengan
+// stok_sebelum +/- jumlah
+func (r *StokRepository) FindLedgerBreaks(barangID uint) ([]models.LedgerBreakRow, error) {
+	barangFilter := ""
+	args := []interface{}{}
+	if barangID != 0 {
+		barangFilter = "WHERE h.barang_id = ?"
+		args = append(args, barangID)
+	}
+
+	var rows []models.LedgerBreakRow
+	err := r.db.Raw(`
+SELECT * FROM (
+	SELECT h.id, h.barang_id, h.created_at, h.jenis_transaksi, h.jumlah, h.stok_sebelum, h.stok_sesudah,
+		LAG(h.stok_sesudah, 1, 0) OVER w AS prev_sesudah,
+		LAG(h.created_at) OVER w AS prev_created_at
+	FROM history_stok h
+	`+barangFilter+`
+	WINDOW w AS (PARTITION BY h.barang_id ORDER BY h.created_at, h.id)
+) x
+WHERE x.prev_sesudah <> x.stok_sebelum
+	OR x.stok_sesudah <> x.stok_sebelum + CASE WHEN x.jenis_transaksi = 'keluar' THEN -x.jumlah ELSE x.jumlah END
+ORDER BY x.barang_id, x.created_at, x.id`, args...).Scan(&rows).Error
+	return rows, err
+}
+
+// FindLedgerDrift mencari barang yang mstok.stok_akhir-nya berbeda dari stok_sesudah history terakhir
+func (r *StokRepository) FindLedgerDrift(barangID uint) ([]models.LedgerDriftRow, error) {
+	q := r.db.Table("mstok s").
+		Select("s.barang_id, b.kode_barang, b.nama_barang, s.stok_akhir, COALESCE(l.stok_sesudah, 0) AS stok_ledger").
+		Joins("JOIN master_barang b ON b.id = s.barang_id").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT h.stok_sesudah FROM history_stok h
+			WHERE h.barang_id = s.barang_id
+			ORDER BY h.created_at DESC, h.id DESC LIMIT 1
+		) l ON true`).
+		Where("COALESCE(l.stok_sesudah, 0) <> s.stok_akhir").
+		Order("b.kode_barang ASC")
+	if barangID != 0 {
+		q = q.Where("s.barang_id = ?", barangID)
+	}
+
+	var rows []models.LedgerDriftRow
+	err := q.Scan(&rows).Error
+	return rows, err
+}
+
+// ReconcileLedger memeriksa ledger history_stok seluruh barang (atau satu barang jika barangID != 0).
+// Jika fix = true, dibuat history "adjustment" atas nama userID (dan apiKeyID jika lewat API key) untuk:
+//   - chain break: baris penghubung tepat sebelum baris yang putus (stok_sesudah sebelumnya -> stok_sebelum baris tersebut)
+//   - drift: baris baru yang menyamakan ledger dengan mstok.stok_akhir
+//
+// Row mismatch hanya dilaporkan karena tidak dapat diperbaiki dengan menambah baris.
+func (r *StokRepository) ReconcileLedger(barangID uint, fix bool, userID uint, apiKeyID *uint) (*models.LedgerReconcileResponse, error) {
+	response := &models.LedgerReconcileResponse{CheckedAt: time.Now(), Fix: fix}
+
+	breaks, err := r.FindLedgerBreaks(barangID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[uint]*models.LedgerReconcileResult)
+	result := func(id uint) *models.LedgerReconcileResult {
+		if res, ok := results[id]; ok {
+			return res
+		}
+		res := &models.LedgerReconcileResult{BarangID: id}
+		results[id] = res
+		return res
+	}
+
+	for _, brk := range breaks {
+		historyID, createdAt := brk.ID, brk.CreatedAt
+		res := result(brk.BarangID)
+
+		if brk.PrevSesudah != brk.StokSebelum {
+			issue := models.LedgerIssue{
+				Type:       models.LedgerIssueChainBreak,
+				HistoryID:  &historyID,
+				CreatedAt:  &createdAt,
+				Expected:   brk.PrevSesudah,
+				Actual:     brk.StokSebelum,
+				Keterangan: "stok_sebelum tidak sama dengan stok_sesudah baris sebelumnya",
+			}
+			if fix {
+				if err := r.bridgeLedgerBreak(brk, userID, apiKeyID, &issue); err != nil {
+					return nil, err
+				}
+			}
+			res.Issues = append(res.Issues, issue)
+		}
+
+		delta := brk.Jumlah
+		if brk.JenisTransaksi == "keluar" {
+			delta = -delta
+		}
+		if expected := brk.StokSebelum + delta; expected != brk.StokSesudah {
+			res.Issues = append(res.Issues, models.LedgerIssue{
+				Type:       models.LedgerIssueRowMismatch,
+				HistoryID:  &historyID,
+				CreatedAt:  &createdAt,
+				Expected:   expected,
+				Actual:     brk.StokSesudah,
+				Keterangan: "stok_sesudah tidak sama dengan stok_sebelum +/- jumlah, perlu diperiksa manual",
+			})
+		}
+	}
+
+	drifts, err := r.FindLedgerDrift(barangID)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range drifts {
+		res := result(d.BarangID)
+		issue := models.LedgerIssue{
+			Type:       models.LedgerIssueDrift,
+			Expected:   d.StokLedger,
+			Actual:     d.StokAkhir,
+			Keterangan: "mstok.stok_akhir tidak sama dengan stok_sesudah history terakhir",
+		}
+		if fix {
+			if err := r.correctLedgerDrift(d.BarangID, userID, apiKeyID, &issue); err != nil {
+				return nil, err
+			}
+		}
+		res.Issues = append(res.Issues, issue)
+	}
+
+	// Lengkapi kode & nama barang, urutkan berdasarkan barang_id
+	ids := make([]uint, 0, len(results))
+	for id := range results {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var barang []models.MasterBarang
+	if len(ids) > 0 {
+		if err := r.db.Where("id IN ?", ids).Find(&barang).Error; err != nil {
+			return nil, err
+		}
+	}
+	for _, b := range barang {
+		results[b.ID].KodeBarang = b.KodeBarang
+		results[b.ID].NamaBarang = b.NamaBarang
+	}
+
+	response.Results = []models.LedgerReconcileResult{}
+	for _, id := range ids {
+		res := results[id]
+		response.Results = append(response.Results, *res)
+		response.JumlahIssue += len(res.Issues)
+		for _, issue := range res.Issues {
+			if issue.Fixed {
+				response.JumlahFixed++
+			}
+		}
+	}
+	response.JumlahBarang = len(ids)
+
+	return response, nil
+}
+
+// bridgeLedgerBreak menyisipkan history adjustment 1 mikrodetik sebelum baris yang putus, jika masih ada
+// jarak waktu dengan baris sebelumnya. mstok dikunci dan break diperiksa ulang di dalam transaksi agar tidak
+// bentrok dengan pergerakan stok lain atau rekonsiliasi yang berjalan bersamaan. Snapshot stok barang tersebut
+// sejak baris sebelumnya dihapus karena dibuat tanpa baris penghubung; laporan as_of kembali dihitung dari
+// history dan snapshot berikutnya dibuat oleh job snapshot.
+func (r *StokRepository) bridgeLedgerBreak(brk models.LedgerBreakRow, userID uint, apiKeyID *uint, issue *models.LedgerIssue) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var stok models.Mstok
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("barang_id = ?", brk.BarangID).First(&stok).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var row models.HistoryStok
+		if err := tx.First(&row, brk.ID).Error; err != nil {
+			return err
+		}
+		prevSesudah := 0
+		var prevCreatedAt *time.Time
+		var prev models.HistoryStok
+		err = tx.Where("barang_id = ? AND (created_at, id) < (?, ?)", row.BarangID, row.CreatedAt, row.ID).
+			Order("created_at DESC, id DESC").Take(&prev).Error
+		switch {
+		case err == nil:
+			prevSesudah, prevCreatedAt = prev.StokSesudah, &prev.CreatedAt
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+		if prevSesudah == row.StokSebelum {
+			issue.Keterangan += "; sudah diperbaiki oleh proses lain"
+			return nil
+		}
+
+		at := row.CreatedAt.Add(-time.Microsecond)
+		if prevCreatedAt != nil && !prevCreatedAt.Before(at) {
+			issue.Keterangan += "; tidak dapat diperbaiki otomatis karena waktu sama dengan baris sebelumnya"
+			return nil
+		}
+
+		entry := models.HistoryStok{
+			BarangID:       row.BarangID,
+			UserID:         userID,
+			JenisTransaksi: "adjustment",
+			Jumlah:         row.StokSebelum - prevSesudah,
+			StokSebelum:    prevSesudah,
+			StokSesudah:    row.StokSebelum,
+			Keterangan:     fmt.Sprintf("%s (history #%d)", ledgerCorrectionNote, row.ID),
+			APIKeyID:       apiKeyID,
+			CreatedAt:      at,
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+
+		snapshots := tx.Where("barang_id = ?", row.BarangID)
+		if prevCreatedAt != nil {
+			snapshots = snapshots.Where("snapshot_at > ?", *prevCreatedAt)
+		}
+		if err := snapshots.Delete(&models.StockSnapshot{}).Error; err != nil {
+			return err
+		}
+
+		issue.Fixed = true
+		issue.FixID = &entry.ID
+		return nil
+	})
+}
+
+// correctLedgerDrift membuat history adjustment yang menyamakan ledger dengan stok_akhir, dengan mstok dikunci
+// agar tidak bentrok dengan transaksi yang sedang berjalan
+func (r *StokRepository) correctLedgerDrift(barangID, userID uint, apiKeyID *uint, issue *models.LedgerIssue) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var stok models.Mstok
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("barang_id = ?", barangID).First(&stok).Error; err != nil {
+			return err
+		}
+
+		ledger := 0
+		var last models.HistoryStok
+		err := tx.Where("barang_id = ?", barangID).Order("created_at DESC, id DESC").Take(&last).Error
+		switch {
+		case err == nil:
+			ledger = last.StokSesudah
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+		if ledger == stok.StokAkhir {
+			return nil
+		}
+
+		entry := models.HistoryStok{
+			BarangID:       barangID,
+			UserID:         userID,
+			JenisTransaksi: "adjustment",
+			Jumlah:         stok.StokAkhir - ledger,
+			StokSebelum:    ledger,
+			StokSesudah:    stok.StokAkhir,
+			Keterangan:     ledgerCorrectionNote,
+			APIKeyID:       apiKeyID,
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+		issue.Fixed = true
+		issue.FixID = &entry.ID
+		return nil
+	})
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"time"
+
+	"warehouse-inventory-server/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReplenishmentRepository struct {
+	db *gorm.DB
+}
+
+func NewReplenishmentRepository(db *gorm.DB) *ReplenishmentRepository {
+	return &ReplenishmentRepository{db: db}
+}
+
+// GetUsage mengambil pemakaian (history_stok "keluar") sejak tanggal from per barang, beserta stok,
+// level reorder, supplier pembelian terakhir dan lead time supplier tersebut
+func (r *ReplenishmentRepository) GetUsage(from time.Time, barangIDs []uint) ([]models.ReplenishmentUsage, error) {
+	q := r.db.Table("master_barang b").
+		Select(`b.id AS barang_id, b.kode_barang, b.nama_barang, b.satuan, b.harga_beli,
+			COALESCE(s.stok_akhir, 0) AS stok_akhir,
+			COALESCE(s.stok_minimum, 0) AS stok_minimum,
+			COALESCE(s.stok_maksimum, 0) AS stok_maksimum,
+			COALESCE(s.reorder_qty, 0) AS reorder_qty,
+			COALESCE(k.total_keluar, 0) AS total_keluar,
+			ls.supplier, lt.lead_time_days`).
+		Joins("LEFT JOIN mstok s ON s.barang_id = b.id").
+		Joins(`LEFT JOIN (
+			SELECT barang_id, SUM(jumlah) AS total_keluar
+			FROM history_stok
+			WHERE jenis_transaksi = 'keluar' AND created_at >= ?
+			GROUP BY barang_id
+		) k ON k.barang_id = b.id`, from).
+		Joins(`LEFT JOIN LATERAL (
+			SELECT h.supplier
+			FROM beli_detail d
+			JOIN beli_header h ON h.id = d.beli_header_id
+			WHERE d.barang_id = b.id
+			ORDER BY h.created_at DESC
+			LIMIT 1
+		) ls ON true`).
+		Joins("LEFT JOIN supplier_lead_times lt ON lt.supplier = ls.supplier").
+		Order("b.kode_barang ASC")
+
+	if len(barangIDs) > 0 {
+		q = q.Where("b.id IN ?", barangIDs)
+	}
+
+	var rows []models.ReplenishmentUsage
+	if err := q.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListLeadTimes mengambil seluruh lead time supplier
+func (r *ReplenishmentRepository) ListLeadTimes() ([]models.SupplierLeadTime, error) {
+	var list []models.SupplierLeadTime
+	if err := r.db.Order("supplier ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// UpsertLeadTime membuat atau memperbarui lead time untuk supplier
+func (r *ReplenishmentRepository) UpsertLeadTime(lt *models.SupplierLeadTime) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "supplier"}},
+		DoUpdates: clause.AssignmentColumns([]string{"lead_time_days", "updated_at"}),
+	}).Create(lt).Error
+}
+
+// DeleteLeadTime menghapus lead time supplier, sehingga kembali memakai lead time default
+func (r *ReplenishmentRepository) DeleteLeadTime(id uint) error {
+	result := r.db.Delete(&models.SupplierLeadTime{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"fmt"
+
+	"warehouse-inventory-server/models"
+
+	"gorm.io/gorm"
+)
+
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// transactionSource mendeskripsikan tabel header/detail untuk laporan penjualan atau pembelian
+type transactionSource struct {
+	header   string
+	detail   string
+	headerFK string
+	party    string // kolom customer / supplier di header
+	tanggal  string // tanggal transaksi di header (alias h)
+}
+
+// pembelianTanggal adalah tanggal barang diterima: waktu konfirmasi untuk pembelian draft, selain itu waktu dibuat
+const pembelianTanggal = "COALESCE(h.confirmed_at, h.created_at)"
+
+var (
+	penjualanSource = transactionSource{header: "jual_header", detail: "jual_detail", headerFK: "jual_header_id", party: "customer", tanggal: "h.created_at"}
+	pembelianSource = transactionSource{header: "beli_header", detail: "beli_detail", headerFK: "beli_header_id", party: "supplier", tanggal: pembelianTanggal}
+)
+
+// Format periode untuk group_by day/week/month: (unit date_trunc, format to_char)
+var reportPeriods = map[string][2]string{
+	"day":   {"day", "YYYY-MM-DD"},
+	"week":  {"week", `IYYY-"W"IW`},
+	"month": {"month", "YYYY-MM"},
+}
+
+// PenjualanReport mengagregasi jual_detail per periode / barang / customer / user / kategori / brand / atribut
+func (r *ReportRepository) PenjualanReport(f models.ReportFilter) ([]models.TransactionReportRow, *models.TransactionReportSummary, error) {
+	return r.transactionReport(penjualanSource, f)
+}
+
+// PembelianReport mengagregasi beli_detail per periode / barang / supplier / user / kategori / brand / atribut
+func (r *ReportRepository) PembelianReport(f models.ReportFilter) ([]models.TransactionReportRow, *models.TransactionReportSummary, error) {
+	return r.transactionReport(pembelianSource, f)
+}
+
+func (r *ReportRepository) transactionReport(src transactionSource, f models.ReportFilter) ([]models.TransactionReportRow, *models.TransactionReportSummary, error) {
+	const measures = "COUNT(DISTINCT h.id) AS jumlah_transaksi, COALESCE(SUM(d.qty), 0) AS total_qty, COALESCE(SUM(d.subtotal), 0) AS total_nilai"
+
+	q := r.transactionQuery(src, f)
+	switch f.GroupBy {
+	case "day", "week", "month":
+		period := reportPeriods[f.GroupBy]
+		key := fmt.Sprintf("to_char(date_trunc('%s', %s), '%s')", period[0], src.tanggal, period[1])
+		q = q.Select(key + " AS key, " + key + " AS label, " + measures).
+			Group("key, label").
+			Order("key ASC")
+	case "barang":
+		q = q.Select("b.kode_barang AS key, b.nama_barang AS label, " + measures).
+			Group("b.id, b.kode_barang, b.nama_barang").
+			Order("total_nilai DESC")
+	case "party":
+		q = q.Select("h." + src.party + " AS key, h." + src.party + " AS label, " + measures).
+			Group("h." + src.party).
+			Order("total_nilai DESC")
+	case "user":
+		q = q.Joins("JOIN users u ON u.id = h.user_id").
+			Select("u.username AS key, u.full_name AS label, " + measures).
+			Group("u.id, u.username, u.full_name").
+			Order("total_nilai DESC")
+	case "kategori", "brand", "atribut":
+		var key, label string
+		var args []any
+		q, key, label, args = barangGroupColumns(q, f)
+		q = q.Select(key+" AS key, "+label+" AS label, "+measures, args...).
+			Group("key, label").
+			Order("total_nilai DESC")
+	default:
+		return nil, nil, fmt.Errorf("group_by %q tidak dikenal", f.GroupBy)
+	}
+
+	var rows []models.TransactionReportRow
+	if err := q.Scan(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var summary models.TransactionReportSummary
+	if err := r.transactionQuery(src, f).Select(measures).Scan(&summary).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return rows, &summary, nil
+}
+
+// transactionQuery membangun query detail JOIN header dan master_barang (alias b) beserta filter tanggal, barang,
+// kategori/brand/atribut, user dan customer/supplier.
+// Hanya transaksi berstatus "selesai" yang dihitung (draft pembelian diabaikan).
+func (r *ReportRepository) transactionQuery(src transactionSource, f models.ReportFilter) *gorm.DB {
+	q := r.db.Table(src.detail+" d").
+		Joins("JOIN "+src.header+" h ON h.id = d."+src.headerFK).
+		Joins("JOIN master_barang b ON b.id = d.barang_id").
+		Where("h.status = ?", "selesai").
+		Where(src.tanggal+" >= ? AND "+src.tanggal+" < ?", f.From, f.To)
+
+	if f.BarangID != 0 {
+		q = q.Where("d.barang_id = ?", f.BarangID)
+	}
+	if f.UserID != 0 {
+		q = q.Where("h.user_id = ?", f.UserID)
+	}
+	if f.Party != "" {
+		q = q.Where("h."+src.party+" ILIKE ?", "%"+escapeLike(f.Party)+"%")
+	}
+	return applyKlasifikasiFilter(q, f.KlasifikasiFilter, "b")
+}
+
+// MarginReport menghitung penjualan, HPP dan margin per barang / customer / faktur (JualHeader) / kategori / brand / atribut.
+// HPP memakai harga_pokok yang disimpan saat transaksi, atau master_barang.harga_beli untuk data lama.
+func (r *ReportRepository) MarginReport(f models.ReportFilter) ([]models.MarginReportRow, *models.MarginReportSummary, error) {
+	const measures = `COALESCE(SUM(d.qty), 0) AS total_qty,
+		COALESCE(SUM(d.subtotal), 0) AS penjualan,
+		COALESCE(SUM(d.qty * COALESCE(d.harga_pokok, b.harga_beli)), 0) AS hpp,
+		COALESCE(SUM(d.subtotal - d.qty * COALESCE(d.harga_pokok, b.harga_beli)), 0) AS margin,
+		COALESCE(ROUND(SUM(d.subtotal - d.qty * COALESCE(d.harga_pokok, b.harga_beli)) * 100 / NULLIF(SUM(d.subtotal), 0), 2), 0) AS margin_persen`
+
+	q := r.transactionQuery(penjualanSource, f)
+	switch f.GroupBy {
+	case "barang":
+		q = q.Select("b.kode_barang AS key, b.nama_barang AS label, " + measures).
+			Group("b.id, b.kode_barang, b.nama_barang")
+	case "party":
+		q = q.Select("h.customer AS key, h.customer AS label, " + measures).
+			Group("h.customer")
+	case "invoice":
+		q = q.Select("h.no_faktur AS key, h.customer AS label, " + measures).
+			Group("h.id, h.no_faktur, h.customer")
+	case "kategori", "brand", "atribut":
+		var key, label string
+		var args []any
+		q, key, label, args = barangGroupColumns(q, f)
+		q = q.Select(key+" AS key, "+label+" AS label, "+measures, args...).
+			Group("key, label")
+	default:
+		return nil, nil, fmt.Errorf("group_by %q tidak dikenal", f.GroupBy)
+	}
+
+	var rows []models.MarginReportRow
+	if err := q.Order("margin DESC").Scan(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var summary models.MarginReportSummary
+	if err := r.transactionQuery(penjualanSource, f).Select(measures).Scan(&summary).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return rows, &summary, nil
+}
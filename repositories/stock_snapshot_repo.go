@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"time"
+
+	"warehouse-inventory-server/models"
+
+	"gorm.io/gorm"
+)
+
+// stokAsOfQuery merekonstruksi stok setiap barang sebelum @before:
+//  1. stok_sesudah history terakhir setelah snapshot terakhir (atau sejak awal jika belum ada snapshot)
+//  2. stok snapshot terakhir jika tidak ada pergerakan setelahnya
+//  3. stok_sebelum history pertama setelah @before jika belum ada snapshot maupun history sebelumnya
+//  4. stok_akhir jika barang tidak pernah memiliki history
+//
+// Barang yang dibuat setelah @before tidak ikut.
+const stokAsOfQuery = `
+SELECT b.id AS barang_id, b.kode_barang, b.nama_barang, b.satuan, b.harga_jual,
+	COALESCE(hh.stok_sesudah, sn.stok, nx.stok_sebelum, s.stok_akhir, 0) AS stok
+FROM master_barang b
+LEFT JOIN mstok s ON s.barang_id = b.id
+LEFT JOIN LATERAL (
+	SELECT ss.stok, ss.snapshot_at FROM stock_snapshots ss
+	WHERE ss.barang_id = b.id AND ss.snapshot_at <= @before
+	ORDER BY ss.snapshot_at DESC LIMIT 1
+) sn ON true
+LEFT JOIN LATERAL (
+	SELECT h.stok_sesudah FROM history_stok h
+	WHERE h.barang_id = b.id AND h.created_at < @before AND (sn.snapshot_at IS NULL OR h.created_at >= sn.snapshot_at)
+	ORDER BY h.created_at DESC, h.id DESC LIMIT 1
+) hh ON true
+LEFT JOIN LATERAL (
+	SELECT h.stok_sebelum FROM history_stok h
+	WHERE h.barang_id = b.id AND h.created_at >= @before
+	ORDER BY h.created_at ASC, h.id ASC LIMIT 1
+) nx ON sn.snapshot_at IS NULL AND hh.stok_sesudah IS NULL
+WHERE b.created_at < @before`
+
+// GetStokAsOf merekonstruksi stok seluruh barang (atau satu barang jika barangID != 0) sebelum waktu before,
+// dengan filter kategori/brand/atribut dan group_key/group_label jika f.GroupBy diisi
+func (r *StokRepository) GetStokAsOf(before time.Time, barangID uint, f models.ReportFilter) ([]models.StokAsOfRow, error) {
+	q := r.db.Table("(?) AS t", r.db.Raw(stokAsOfQuery, map[string]interface{}{"before": before})).
+		Joins("JOIN master_barang b ON b.id = t.barang_id")
+	if barangID != 0 {
+		q = q.Where("t.barang_id = ?", barangID)
+	}
+	q, group, args := barangGroupSelect(applyKlasifikasiFilter(q, f.KlasifikasiFilter, "b"), f)
+
+	var rows []models.StokAsOfRow
+	err := q.Select("t.*, b.harga_beli"+group, args...).
+		Order("t.kode_barang ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// CreateSnapshot menyimpan snapshot stok seluruh barang pada waktu at. Snapshot yang sudah ada tidak ditimpa.
+func (r *StokRepository) CreateSnapshot(at time.Time) (int64, error) {
+	return insertSnapshot(r.db, at)
+}
+
+// Kunci advisory PostgreSQL untuk job snapshot berkala, agar hanya satu replika yang membuat snapshot
+const stockSnapshotLockKey = 7_301_038
+
+// CreateScheduledSnapshot dipakai job snapshot berkala. Dilewati (0, nil) jika replika lain sedang membuat snapshot
+// atau sudah ada snapshot setelah at - minGap, sehingga restart proses dan banyak replika tidak membuat snapshot ganda.
+func (r *StokRepository) CreateScheduledSnapshot(at time.Time, minGap time.Duration) (int64, error) {
+	var count int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", stockSnapshotLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		var recent bool
+		if err := tx.Raw("SELECT EXISTS (SELECT 1 FROM stock_snapshots WHERE snapshot_at > ?)", at.Add(-minGap)).Scan(&recent).Error; err != nil {
+			return err
+		}
+		if recent {
+			return nil
+		}
+
+		var err error
+		count, err = insertSnapshot(tx, at)
+		return err
+	})
+	return count, err
+}
+
+func insertSnapshot(db *gorm.DB, at time.Time) (int64, error) {
+	result := db.Exec(`
+INSERT INTO stock_snapshots (barang_id, snapshot_at, stok)
+SELECT barang_id, @before, stok FROM (`+stokAsOfQuery+`) AS asof
+ON CONFLICT (barang_id, snapshot_at) DO NOTHING`, map[string]interface{}{
+		"before": at,
+	})
+	return result.RowsAffected, result.Error
+}
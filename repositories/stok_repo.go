@@ -1,11 +1,20 @@
 package repositories
 
 import (
+	"errors"
+	"strings"
+	"time"
+
 	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrStokTidakMencukupi dikembalikan jika perubahan stok membuat stok menjadi negatif
+var ErrStokTidakMencukupi = errors.New("stok tidak mencukupi")
+
 type StokRepository struct {
 	db *gorm.DB
 }
@@ -51,34 +60,314 @@ func (r *StokRepository) CreateHistory(history *models.HistoryStok) error {
 	return r.db.Create(history).Error
 }
 
-// GetAllStok mengambil semua data stok beserta relasi MasterBarang
-func (r *StokRepository) GetAllStok() ([]models.Mstok, error) {
+// GetAllStok mengambil semua data stok beserta relasi MasterBarang, dengan filter kategori/brand/atribut
+// dan group_key/group_label jika f.GroupBy diisi
+func (r *StokRepository) GetAllStok(f models.ReportFilter) ([]models.Mstok, error) {
 	var list []models.Mstok
-	if err := r.db.Preload("MasterBarang").Find(&list).Error; err != nil {
+	q, group, args := stokQuery(r.db.Model(&models.Mstok{}), f)
+	if err := q.Select("mstok.*"+group, args...).Preload("MasterBarang").Find(&list).Error; err != nil {
 		return nil, err
 	}
 	return list, nil
 }
 
-// GetHistory mengambil data history stok dan total count
-func (r *StokRepository) GetHistory(barangID uint, limit, offset int) ([]models.HistoryStok, int64, error) {
+// EachStok memanggil fn untuk setiap stok barang beserta reorder level dan harga beli, urut kode barang
+func (r *StokRepository) EachStok(f models.ReportFilter, fn func(models.StokExportRow) error) error {
+	q, group, args := stokQuery(r.db.Table("mstok"), f)
+	if group == "" {
+		group = ", '' AS group_label"
+	}
+	q = q.Select(`mstok.barang_id, b.kode_barang, b.nama_barang, b.satuan, mstok.stok_akhir,
+			mstok.stok_minimum, mstok.stok_maksimum, mstok.reorder_qty, b.harga_beli, mstok.updated_at`+group, args...).
+		Order("b.kode_barang ASC")
+	return eachRow(r.db, q, fn)
+}
+
+// StokGroups menghitung jumlah barang, total stok dan nilai stok per group_by
+func (r *StokRepository) StokGroups(f models.ReportFilter) ([]models.StockReportGroup, error) {
+	q, _, _ := stokQuery(r.db.Table("mstok"), models.ReportFilter{KlasifikasiFilter: f.KlasifikasiFilter})
+	return barangGroupTotals(q, f, "mstok.stok_akhir", "mstok.stok_akhir * b.harga_beli")
+}
+
+// stokQuery menambahkan JOIN master_barang (alias b), filter kategori/brand/atribut dan kolom group
+// pada query tabel mstok. Mengembalikan fragmen select group (kosong jika tanpa group_by) beserta argumennya.
+func stokQuery(q *gorm.DB, f models.ReportFilter) (*gorm.DB, string, []any) {
+	q = applyKlasifikasiFilter(q.Joins("JOIN master_barang b ON b.id = mstok.barang_id"), f.KlasifikasiFilter, "b")
+	return barangGroupSelect(q, f)
+}
+
+// GetKartuStok mengambil pergerakan stok barang pada rentang [from, to) beserta dokumen pembelian/penjualan
+// yang direferensikan history (ref_type / ref_id)
+func (r *StokRepository) GetKartuStok(barangID uint, from, to time.Time) ([]models.KartuStokRow, error) {
+	var rows []models.KartuStokRow
+	err := r.db.Table("history_stok h").
+		Select(`h.id AS history_id, h.created_at AS tanggal, h.jenis_transaksi, h.jumlah, h.keterangan,
+			COALESCE(u.username, '') AS username,
+			CASE WHEN bh.id IS NOT NULL THEN 'pembelian' WHEN jh.id IS NOT NULL THEN 'penjualan' ELSE '' END AS jenis_dokumen,
+			COALESCE(bh.id, jh.id) AS dokumen_id,
+			COALESCE(bh.no_faktur, jh.no_faktur, '') AS no_dokumen,
+			COALESCE(bh.supplier, jh.customer, '') AS pihak`).
+		Joins("LEFT JOIN users u ON u.id = h.user_id").
+		Joins("LEFT JOIN beli_header bh ON h.ref_type = ? AND bh.id = h.ref_id", models.HistoryRefPembelian).
+		Joins("LEFT JOIN jual_header jh ON h.ref_type = ? AND jh.id = h.ref_id", models.HistoryRefPenjualan).
+		Where("h.barang_id = ? AND h.created_at >= ? AND h.created_at < ?", barangID, from, to).
+		Order("h.created_at ASC, h.id ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// Kolom yang boleh dipakai untuk mengurutkan history stok
+var historySortColumns = map[string]string{
+	"created_at":      "created_at",
+	"jumlah":          "jumlah",
+	"jenis_transaksi": "jenis_transaksi",
+	"barang_id":       "barang_id",
+}
+
+// GetHistory mengambil data history stok sesuai filter dan total count
+func (r *StokRepository) GetHistory(f models.HistoryStokFilter) ([]models.HistoryStok, int64, error) {
 	var list []models.HistoryStok
 	var total int64
 
-	base := r.db.Model(&models.HistoryStok{})
-	if barangID != 0 {
-		base = base.Where("barang_id = ?", barangID)
+	column, ok := historySortColumns[f.Sort]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "DESC"
+	if !f.Desc {
+		direction = "ASC"
+	}
+
+	q := r.historyQuery(f).Preload("MasterBarang").Preload("Users").
+		Order(column + " " + direction).
+		Order("id " + direction)
+
+	if f.Cursor == nil {
+		if err := r.historyQuery(f).Model(&models.HistoryStok{}).Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		q = q.Offset(f.Offset)
+	} else if f.Cursor.ID != 0 {
+		q = q.Where(keysetCondition("created_at", f.Desc), f.Cursor.Value, f.Cursor.ID)
 	}
-	if err := base.Count(&total).Error; err != nil {
+
+	if err := q.Limit(f.Limit).Find(&list).Error; err != nil {
 		return nil, 0, err
 	}
+	return list, total, nil
+}
+
+// EachHistory memanggil fn untuk setiap history stok yang cocok dengan filter (tanpa paginasi)
+func (r *StokRepository) EachHistory(f models.HistoryStokFilter, fn func(models.HistoryStokExportRow) error) error {
+	column, ok := historySortColumns[f.Sort]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "DESC"
+	if !f.Desc {
+		direction = "ASC"
+	}
+
+	q := r.db.Table("(?) AS h", r.historyQuery(f).Model(&models.HistoryStok{})).
+		Select(`h.id, h.created_at, b.kode_barang, b.nama_barang, h.jenis_transaksi, h.jumlah,
+			h.stok_sebelum, h.stok_sesudah, COALESCE(h.keterangan, '') AS keterangan, COALESCE(u.username, '') AS username`).
+		Joins("JOIN master_barang b ON b.id = h.barang_id").
+		Joins("LEFT JOIN users u ON u.id = h.user_id").
+		Order("h." + column + " " + direction).
+		Order("h.id " + direction)
+	return eachRow(r.db, q, fn)
+}
+
+// keysetCondition membuat kondisi keyset pagination (column, id) setelah cursor sesuai arah urutan.
+// Nilai cursor untuk kolom waktu disimpan dengan utils.CursorTimeLayout.
+func keysetCondition(column string, desc bool) string {
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	if column == "created_at" {
+		return "(created_at, id) " + op + " (CAST(? AS timestamp), ?)"
+	}
+	return "(" + column + ", id) " + op + " (?, ?)"
+}
+
+// historyQuery menerapkan filter barang, jenis transaksi, user, rentang tanggal dan pencarian keterangan
+func (r *StokRepository) historyQuery(f models.HistoryStokFilter) *gorm.DB {
+	q := r.db
+	if f.BarangID != 0 {
+		q = q.Where("barang_id = ?", f.BarangID)
+	}
+	if len(f.JenisTransaksi) > 0 {
+		q = q.Where("jenis_transaksi IN ?", f.JenisTransaksi)
+	}
+	if f.UserID != 0 {
+		q = q.Where("user_id = ?", f.UserID)
+	}
+	if !f.From.IsZero() {
+		q = q.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("created_at < ?", f.To)
+	}
+	if f.Search != "" {
+		q = q.Where("keterangan ILIKE ?", "%"+escapeLike(f.Search)+"%")
+	}
+	return q
+}
+
+// likeEscaper meng-escape wildcard LIKE agar % dan _ dari input dicari sebagai karakter biasa
+// (backslash adalah karakter escape default LIKE di PostgreSQL)
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// lowStockCondition: stok_akhir sudah mencapai titik reorder (stok_minimum, atau threshold default jika belum diset)
+const lowStockCondition = "mstok.stok_akhir <= CASE WHEN mstok.stok_minimum > 0 THEN mstok.stok_minimum ELSE ? END"
+
+// UpdateReorderLevel memperbarui stok minimum, maksimum dan reorder qty untuk barangID
+func (r *StokRepository) UpdateReorderLevel(barangID uint, req models.ReorderLevelRequest) error {
+	result := r.db.Model(&models.Mstok{}).Where("barang_id = ?", barangID).Updates(map[string]interface{}{
+		"stok_minimum":  req.StokMinimum,
+		"stok_maksimum": req.StokMaksimum,
+		"reorder_qty":   req.ReorderQty,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetLowStock mengambil barang yang stoknya sudah mencapai atau di bawah titik reorder,
+// dengan filter kategori/brand/atribut dan group_key/group_label jika f.GroupBy diisi
+func (r *StokRepository) GetLowStock(defaultThreshold int, f models.ReportFilter) ([]models.Mstok, error) {
+	var list []models.Mstok
+	q, group, args := stokQuery(r.db.Model(&models.Mstok{}), f)
+	if err := q.Select("mstok.*"+group, args...).
+		Preload("MasterBarang").
+		Where(lowStockCondition, defaultThreshold).
+		Order("mstok.stok_akhir ASC").
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// AdjustStok mengubah stok secara manual (stock opname / koreksi) dan mencatat history "adjustment"
+func (r *StokRepository) AdjustStok(barangID, userID uint, apiKeyID *uint, jumlah int, keterangan string) (*models.HistoryStok, error) {
+	var history models.HistoryStok
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var stok models.Mstok
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("barang_id = ?", barangID).First(&stok).Error; err != nil {
+			return err
+		}
+
+		stokSebelum := stok.StokAkhir
+		stokSesudah := stokSebelum + jumlah
+		if stokSesudah < 0 {
+			return ErrStokTidakMencukupi
+		}
+		stok.StokAkhir = stokSesudah
+		if err := tx.Save(&stok).Error; err != nil {
+			return err
+		}
+
+		history = models.HistoryStok{
+			BarangID:       barangID,
+			UserID:         userID,
+			JenisTransaksi: "adjustment",
+			Jumlah:         jumlah,
+			StokSebelum:    stokSebelum,
+			StokSesudah:    stokSesudah,
+			Keterangan:     keterangan,
+			APIKeyID:       apiKeyID,
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return err
+		}
 
-	q := r.db.Preload("MasterBarang").Preload("Users").Order("created_at DESC")
-	if barangID != 0 {
-		q = q.Where("barang_id = ?", barangID)
+		return checkStockAlert(tx, &stok, stokSebelum, stokSesudah, keterangan)
+	})
+	if err != nil {
+		return nil, err
 	}
-	if err := q.Order("created_at DESC").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+	return &history, nil
+}
+
+// GetAlerts mengambil stock alert (terbaru dahulu), opsional hanya yang belum di-resolve,
+// dengan filter kategori/brand/atribut dan group_key/group_label jika f.GroupBy diisi
+func (r *StokRepository) GetAlerts(openOnly bool, f models.ReportFilter, limit, offset int) ([]models.StockAlert, int64, error) {
+	var list []models.StockAlert
+	var total int64
+
+	if err := r.alertQuery(openOnly, f.KlasifikasiFilter).Model(&models.StockAlert{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	q, group, args := barangGroupSelect(r.alertQuery(openOnly, f.KlasifikasiFilter).Model(&models.StockAlert{}), f)
+	if err := q.Select("stock_alerts.*"+group, args...).
+		Preload("MasterBarang").
+		Order("stock_alerts.created_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&list).Error; err != nil {
 		return nil, 0, err
 	}
 	return list, total, nil
 }
+
+// AlertGroups menghitung jumlah alert, total stok saat alert dan nilainya per group_by
+func (r *StokRepository) AlertGroups(openOnly bool, f models.ReportFilter) ([]models.StockReportGroup, error) {
+	q := r.alertQuery(openOnly, f.KlasifikasiFilter).Table("stock_alerts")
+	return barangGroupTotals(q, f, "stock_alerts.stok_sesudah", "stock_alerts.stok_sesudah * b.harga_beli")
+}
+
+// alertQuery menerapkan JOIN master_barang (alias b), filter status dan filter kategori/brand/atribut pada stock alert
+func (r *StokRepository) alertQuery(openOnly bool, f models.KlasifikasiFilter) *gorm.DB {
+	q := r.db.Joins("JOIN master_barang b ON b.id = stock_alerts.barang_id")
+	if openOnly {
+		q = q.Where("stock_alerts.resolved_at IS NULL")
+	}
+	return applyKlasifikasiFilter(q, f, "b")
+}
+
+// ResolveAlert menandai stock alert sebagai sudah ditangani
+func (r *StokRepository) ResolveAlert(id uint) error {
+	result := r.db.Model(&models.StockAlert{}).
+		Where("id = ? AND resolved_at IS NULL", id).
+		Update("resolved_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// checkStockAlert membuat stock alert ketika stok turun melewati titik reorder,
+// dan menutup alert yang masih terbuka ketika stok kembali di atas titik reorder
+func checkStockAlert(tx *gorm.DB, stok *models.Mstok, stokSebelum, stokSesudah int, keterangan string) error {
+	reorderPoint := stok.ReorderPoint(utils.LowStockThreshold())
+
+	if stokSebelum > reorderPoint && stokSesudah <= reorderPoint {
+		alert := models.StockAlert{
+			BarangID:     stok.BarangID,
+			StokSebelum:  stokSebelum,
+			StokSesudah:  stokSesudah,
+			ReorderPoint: reorderPoint,
+			Keterangan:   keterangan,
+		}
+		return tx.Create(&alert).Error
+	}
+
+	if stokSesudah > reorderPoint {
+		return tx.Model(&models.StockAlert{}).
+			Where("barang_id = ? AND resolved_at IS NULL", stok.BarangID).
+			Update("resolved_at", time.Now()).Error
+	}
+
+	return nil
+}
@@ -1,11 +1,23 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"warehouse-inventory-server/models"
+	"warehouse-inventory-server/utils"
 
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrInvalidResetToken dikembalikan jika token reset password tidak ditemukan, sudah dipakai atau kedaluwarsa
+var ErrInvalidResetToken = errors.New("token reset password tidak valid atau sudah kedaluwarsa")
+
+// ErrResetTokenRecent dikembalikan jika user baru saja meminta token reset password yang masih aktif
+var ErrResetTokenRecent = errors.New("token reset password baru saja dikirim")
+
 type UserRepository struct {
 	db *gorm.DB
 }
@@ -33,3 +45,230 @@ func (r *UserRepository) FindByUsername(username string) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+func (r *UserRepository) FindByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// RegisterFailedLogin menaikkan counter gagal login dan mengunci akun jika sudah mencapai batas policy.
+// Baris user dikunci agar gagal login yang bersamaan tetap terhitung semua.
+func (r *UserRepository) RegisterFailedLogin(id uint, policy utils.LoginPolicy) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "failed_login_count", "locked_until").First(&user, id).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		count, lockedUntil := policy.RegisterFailure(user.FailedLoginCount, user.LockedUntil, now)
+		return tx.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"failed_login_count":   count,
+			"last_failed_login_at": now,
+			"locked_until":         lockedUntil,
+		}).Error
+	})
+}
+
+// ResetFailedLogin mengosongkan counter gagal login dan status lockout (dipakai saat login sukses dan unlock oleh admin)
+func (r *UserRepository) ResetFailedLogin(id uint) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"failed_login_count":   0,
+		"last_failed_login_at": nil,
+		"locked_until":         nil,
+	}).Error
+}
+
+// CreateLoginAudit mencatat percobaan login (sukses maupun gagal)
+func (r *UserRepository) CreateLoginAudit(audit *models.LoginAudit) error {
+	return r.db.Create(audit).Error
+}
+
+// Alasan gagal login yang dihitung untuk blokir IP. Percobaan yang ditolak karena throttle
+// (ip_blocked, locked, too_fast) tidak dihitung agar blokir tidak terus diperpanjang.
+var ipFailureReasons = []string{"invalid_credentials", "invalid_2fa"}
+
+// CountFailedLoginsByIP menghitung gagal login (password atau kode 2FA salah) dari satu IP sejak waktu tertentu
+func (r *UserRepository) CountFailedLoginsByIP(ip string, since time.Time) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.LoginAudit{}).
+		Where("ip_address = ? AND success = ? AND reason IN ? AND created_at >= ?", ip, false, ipFailureReasons, since).
+		Count(&total).Error
+	return total, err
+}
+
+// GetLoginAudit mengambil data login audit (terbaru dahulu) dengan filter email opsional
+func (r *UserRepository) GetLoginAudit(email string, limit, offset int) ([]models.LoginAudit, int64, error) {
+	var list []models.LoginAudit
+	var total int64
+
+	q := r.db.Model(&models.LoginAudit{})
+	if email != "" {
+		q = q.Where("email = ?", email)
+	}
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Order("created_at DESC").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// SaveTOTPSecret menyimpan secret TOTP yang belum aktif (menunggu verifikasi kode pertama)
+func (r *UserRepository) SaveTOTPSecret(id uint, secret string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"totp_secret":    secret,
+		"totp_enabled":   false,
+		"totp_last_step": 0,
+	}).Error
+}
+
+// EnableTOTP mengaktifkan 2FA dan mengganti seluruh backup code lama dengan yang baru
+func (r *UserRepository) EnableTOTP(id uint, step int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"totp_enabled":   true,
+			"totp_last_step": step,
+		}).Error; err != nil {
+			return err
+		}
+		return replaceBackupCodes(tx, id, codeHashes)
+	})
+}
+
+// DisableTOTP menonaktifkan 2FA dan menghapus secret serta backup code
+func (r *UserRepository) DisableTOTP(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"totp_secret":    nil,
+			"totp_enabled":   false,
+			"totp_last_step": 0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", id).Delete(&models.UserBackupCode{}).Error
+	})
+}
+
+// UpdateTOTPLastStep menyimpan step TOTP terakhir yang dipakai agar kode yang sama tidak bisa dipakai ulang.
+// Update bersyarat sehingga dua request bersamaan dengan kode yang sama hanya satu yang berhasil (false = kode sudah dipakai).
+func (r *UserRepository) UpdateTOTPLastStep(id uint, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+// SetTOTPRequired mengatur apakah user wajib memakai 2FA
+func (r *UserRepository) SetTOTPRequired(id uint, required bool) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("totp_required", required).Error
+}
+
+// ReplaceBackupCodes mengganti seluruh backup code user
+func (r *UserRepository) ReplaceBackupCodes(id uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceBackupCodes(tx, id, codeHashes)
+	})
+}
+
+// ConsumeBackupCode mencocokkan backup code dengan hash yang belum terpakai lalu menandainya sebagai terpakai
+func (r *UserRepository) ConsumeBackupCode(userID uint, code string) (bool, error) {
+	var codes []models.UserBackupCode
+	if err := r.db.Where("user_id = ? AND used_at IS NULL", userID).Find(&codes).Error; err != nil {
+		return false, err
+	}
+	for _, bc := range codes {
+		if bcrypt.CompareHashAndPassword([]byte(bc.CodeHash), []byte(code)) != nil {
+			continue
+		}
+		// Update bersyarat agar backup code yang sama tidak bisa dipakai dua kali secara bersamaan
+		result := r.db.Model(&models.UserBackupCode{}).
+			Where("id = ? AND used_at IS NULL", bc.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return false, result.Error
+		}
+		return result.RowsAffected == 1, nil
+	}
+	return false, nil
+}
+
+func replaceBackupCodes(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.UserBackupCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]models.UserBackupCode, len(codeHashes))
+	for i, h := range codeHashes {
+		codes[i] = models.UserBackupCode{UserID: userID, CodeHash: h}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
+
+// CreatePasswordResetToken membatalkan token reset yang masih aktif milik user lalu menyimpan token baru.
+// Mengembalikan ErrResetTokenRecent tanpa membuat token jika user masih memiliki token aktif yang dibuat sejak since,
+// agar permintaan berulang tidak membanjiri inbox user.
+func (r *UserRepository) CreatePasswordResetToken(token *models.PasswordResetToken, since time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Kunci baris user agar permintaan bersamaan untuk user yang sama diproses bergantian
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, token.UserID).Error; err != nil {
+			return err
+		}
+
+		var recent int64
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL AND expires_at > ? AND created_at >= ?", token.UserID, time.Now(), since).
+			Count(&recent).Error; err != nil {
+			return err
+		}
+		if recent > 0 {
+			return ErrResetTokenRecent
+		}
+
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// ResetPassword memakai token reset (sekali pakai), mengganti password dan membuka lockout akun
+func (r *UserRepository) ResetPassword(tokenHash, passwordHash string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var token models.PasswordResetToken
+		if err := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+			First(&token).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+
+		// Update bersyarat agar token yang sama tidak bisa dipakai dua kali secara bersamaan
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidResetToken
+		}
+
+		return tx.Model(&models.User{}).Where("id = ?", token.UserID).Updates(map[string]interface{}{
+			"password":             passwordHash,
+			"failed_login_count":   0,
+			"last_failed_login_at": nil,
+			"locked_until":         nil,
+		}).Error
+	})
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"warehouse-inventory-server/config"
+	"warehouse-inventory-server/repositories"
+
+	"github.com/joho/godotenv"
+)
+
+// Tool to reconcile mstok with history_stok from the command line (same check as GET/POST /api/stok/reconcile)
+func main() {
+	fix := flag.Bool("fix", false, "post corrective adjustment entries for chain breaks and drift")
+	barangID := flag.Uint("barang", 0, "only check this barang_id")
+	userID := flag.Uint("user", 0, "user_id recorded on corrective entries (required with -fix)")
+	flag.Parse()
+
+	if *fix && *userID == 0 {
+		fmt.Println("Usage: go run ./tools/reconcile_stock [-barang <id>] [-fix -user <user_id>]")
+		os.Exit(2)
+	}
+
+	_ = godotenv.Load()
+	db, err := config.InitDB()
+	if err != nil {
+		fmt.Printf("Error connecting database: %v\n", err)
+		os.Exit(1)
+	}
+
+	result, err := repositories.NewStokRepository(db).ReconcileLedger(*barangID, *fix, *userID, nil)
+	if err != nil {
+		fmt.Printf("Error reconciling stock ledger: %v\n", err)
+		os.Exit(1)
+	}
+
+	out, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(out))
+
+	// Exit code 3 jika masih ada masalah yang belum diperbaiki (berguna untuk cron / CI)
+	if result.JumlahIssue > result.JumlahFixed {
+		os.Exit(3)
+	}
+}
+
+/* Usage:
+   1. go run ./tools/reconcile_stock                   (report only)
+   2. go run ./tools/reconcile_stock -fix -user 1      (post corrective adjustments as user 1)
+*/
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Format API key: wh_<prefix 8 hex>_<secret 48 hex>. Prefix disimpan apa adanya untuk lookup & ditampilkan,
+// sedangkan key lengkap hanya disimpan dalam bentuk hash SHA-256.
+const apiKeyTag = "wh_"
+
+// GenerateAPIKey membuat API key baru dan mengembalikan key lengkap, prefix dan hash-nya
+func GenerateAPIKey() (raw, prefix, hash string, err error) {
+	buf := make([]byte, 28)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+	encoded := hex.EncodeToString(buf)
+	prefix = apiKeyTag + encoded[:8]
+	raw = prefix + "_" + encoded[8:]
+	return raw, prefix, HashAPIKey(raw), nil
+}
+
+// APIKeyPrefix mengambil prefix dari API key lengkap
+func APIKeyPrefix(raw string) (string, bool) {
+	if !strings.HasPrefix(raw, apiKeyTag) {
+		return "", false
+	}
+	idx := strings.Index(raw[len(apiKeyTag):], "_")
+	if idx <= 0 {
+		return "", false
+	}
+	return raw[:len(apiKeyTag)+idx], true
+}
+
+func HashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
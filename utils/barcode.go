@@ -0,0 +1,50 @@
+package utils
+
+import "strings"
+
+// ValidGTIN memeriksa barcode EAN-8, UPC-A (12 digit), EAN-13 atau GTIN-14:
+// hanya angka dan digit terakhir sesuai check digit GS1 (mod 10)
+func ValidGTIN(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		// Dari kanan (tanpa check digit), posisi ganjil berbobot 3
+		if (len(code)-2-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+
+	check := code[len(code)-1]
+	if check < '0' || check > '9' {
+		return false
+	}
+	return int(check-'0') == (10-sum%10)%10
+}
+
+// NormalizeGTIN mengubah barcode EAN-8, UPC-A, EAN-13 atau GTIN-14 yang valid menjadi GTIN-14 (diawali nol),
+// sehingga UPC-A 0123... dan EAN-13 00123... dianggap barcode yang sama. Kode lain dikembalikan apa adanya.
+func NormalizeGTIN(code string) string {
+	if !ValidGTIN(code) {
+		return code
+	}
+	return strings.Repeat("0", 14-len(code)) + code
+}
+
+// EAN13FromGTIN mengembalikan bentuk EAN-13 dari GTIN-14 yang diawali nol (termasuk UPC-A dan EAN-8 yang dinormalisasi)
+func EAN13FromGTIN(code string) (string, bool) {
+	if len(code) != 14 || code[0] != '0' || !ValidGTIN(code) {
+		return "", false
+	}
+	return code[1:], true
+}
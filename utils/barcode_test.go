@@ -0,0 +1,70 @@
+package utils
+
+import "testing"
+
+func TestValidGTIN(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"96385074", true},       // EAN-8
+		{"036000291452", true},   // UPC-A
+		{"4006381333931", true},  // EAN-13
+		{"10012345678902", true}, // GTIN-14
+		{"00012345678905", true}, // UPC-A yang dinormalisasi
+		{"4006381333932", false}, // check digit salah
+		{"036000291453", false},
+		{"0360002914", false},     // panjang tidak didukung
+		{"03600029145a", false},   // bukan angka
+		{"a36000291452", false},   // bukan angka di digit pertama
+		{"4006381333931 ", false}, // spasi di akhir
+		{"BRG0000000001", false},  // kode_barang
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ValidGTIN(tt.code); got != tt.want {
+			t.Errorf("ValidGTIN(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeGTIN(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"96385074", "00000096385074"},
+		{"036000291452", "00036000291452"},
+		{"0036000291452", "00036000291452"}, // EAN-13 dari UPC-A yang sama
+		{"4006381333931", "04006381333931"},
+		{"10012345678902", "10012345678902"},
+		{"4006381333932", "4006381333932"}, // tidak valid, apa adanya
+		{"BRG001", "BRG001"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeGTIN(tt.code); got != tt.want {
+			t.Errorf("NormalizeGTIN(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestEAN13FromGTIN(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+		ok   bool
+	}{
+		{"04006381333931", "4006381333931", true},
+		{"00036000291452", "0036000291452", true},
+		{"00000096385074", "0000096385074", true},
+		{"10012345678902", "", false}, // indikator kemasan bukan 0
+		{"4006381333931", "", false},  // belum dinormalisasi
+		{"04006381333932", "", false}, // check digit salah
+	}
+	for _, tt := range tests {
+		got, ok := EAN13FromGTIN(tt.code)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("EAN13FromGTIN(%q) = (%q, %v), want (%q, %v)", tt.code, got, ok, tt.want, tt.ok)
+		}
+	}
+}
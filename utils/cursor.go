@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"warehouse-inventory-server/models"
+)
+
+// CursorTimeLayout dipakai untuk menyimpan created_at (timestamp tanpa zona waktu) di cursor
+const CursorTimeLayout = "2006-01-02 15:04:05.999999"
+
+// ErrInvalidCursor dikembalikan jika cursor tidak dapat dibaca
+var ErrInvalidCursor = errors.New("cursor tidak valid")
+
+// EncodeCursor mengubah cursor menjadi string opaque (base64url JSON)
+func EncodeCursor(value string, id uint) string {
+	raw, _ := json.Marshal(models.Cursor{Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// EncodeTimeCursor membuat cursor dari created_at dan ID baris terakhir
+func EncodeTimeCursor(t time.Time, id uint) string {
+	return EncodeCursor(t.Format(CursorTimeLayout), id)
+}
+
+// DecodeCursor membaca cursor dari EncodeCursor. String kosong berarti halaman pertama.
+func DecodeCursor(s string) (*models.Cursor, error) {
+	if s == "" {
+		return &models.Cursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c models.Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		id    uint
+	}{
+		{"waktu", "2025-06-01 10:00:00.123456", 42},
+		{"kode barang", "BRG001", 1},
+		{"karakter khusus", `a"b\c/+=`, 7},
+		{"value kosong", "", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := DecodeCursor(EncodeCursor(tt.value, tt.id))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Value != tt.value || c.ID != tt.id {
+				t.Errorf("DecodeCursor = %+v, want {%q %d}", *c, tt.value, tt.id)
+			}
+		})
+	}
+}
+
+func TestEncodeTimeCursor(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2025, 6, 1, 10, 0, 0, 123456789, time.UTC), "2025-06-01 10:00:00.123456"},
+		{time.Date(2025, 6, 1, 10, 0, 0, 500000000, time.UTC), "2025-06-01 10:00:00.5"},
+		{time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), "2025-06-01 10:00:00"},
+	}
+	for _, tt := range tests {
+		c, err := DecodeCursor(EncodeTimeCursor(tt.t, 9))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Value != tt.want || c.ID != 9 {
+			t.Errorf("EncodeTimeCursor(%v) = {%q %d}, want {%q 9}", tt.t, c.Value, c.ID, tt.want)
+		}
+		parsed, err := time.Parse(CursorTimeLayout, c.Value)
+		if err != nil || !parsed.Equal(tt.t.Truncate(time.Microsecond)) {
+			t.Errorf("cursor %q tidak kembali ke %v", c.Value, tt.t)
+		}
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	c, err := DecodeCursor("")
+	if err != nil || c.Value != "" || c.ID != 0 {
+		t.Errorf("DecodeCursor(\"\") = (%+v, %v), want halaman pertama", c, err)
+	}
+
+	invalid := map[string]string{
+		"bukan base64":   "!!!",
+		"base64 padding": base64.URLEncoding.EncodeToString([]byte(`{"v":"x","id":1}`)),
+		"bukan json":     encode("not json"),
+		"id nol":         encode(`{"v":"x","id":0}`),
+		"id tidak ada":   encode(`{"v":"x"}`),
+		"id negatif":     encode(`{"v":"x","id":-1}`),
+		"id bukan angka": encode(`{"v":"x","id":"1"}`),
+	}
+	for name, s := range invalid {
+		if _, err := DecodeCursor(s); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: DecodeCursor(%q) error = %v, want ErrInvalidCursor", name, s, err)
+		}
+	}
+}
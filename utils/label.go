@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+
+	"warehouse-inventory-server/models"
+)
+
+// Template label bawaan. Template tambahan (atau pengganti dengan nama yang sama) dapat dibaca dari
+// file JSON berisi array LabelTemplate yang ditunjuk env LABEL_TEMPLATES_FILE.
+var defaultLabelTemplates = []models.LabelTemplate{
+	// A4 3 x 8 (70 x 37 mm), 24 label per lembar
+	{Name: "a4-3x8", Format: "pdf", PageWidth: 210, PageHeight: 297, Columns: 3, Rows: 8, LabelWidth: 70, LabelHeight: 37,
+		MarginTop: 0.5, ShowName: true, ShowPrice: true, FontSize: 9},
+	// A4 4 x 10 (52.5 x 29.7 mm), 40 label per lembar
+	{Name: "a4-4x10", Format: "pdf", PageWidth: 210, PageHeight: 297, Columns: 4, Rows: 10, LabelWidth: 52.5, LabelHeight: 29.7,
+		ShowName: true, ShowPrice: false, FontSize: 7},
+	// A4 2 x 7 (99.1 x 38.1 mm), label rak dengan harga besar
+	{Name: "a4-2x7-rak", Format: "pdf", PageWidth: 210, PageHeight: 297, Columns: 2, Rows: 7, LabelWidth: 99.1, LabelHeight: 38.1,
+		MarginLeft: 4.65, MarginTop: 15.15, GapX: 2.5, ShowName: true, ShowPrice: true, FontSize: 12},
+	// Printer thermal 203 dpi
+	{Name: "zpl-50x30", Format: "zpl", LabelWidth: 50, LabelHeight: 30, DPI: 203, ShowName: true, ShowPrice: true, FontSize: 8},
+	{Name: "zpl-100x50", Format: "zpl", LabelWidth: 100, LabelHeight: 50, DPI: 203, ShowName: true, ShowPrice: true, FontSize: 14},
+}
+
+var (
+	labelTemplatesOnce sync.Once
+	labelTemplates     []models.LabelTemplate
+)
+
+// LabelTemplates mengembalikan template bawaan digabung dengan template dari LABEL_TEMPLATES_FILE (dibaca sekali)
+func LabelTemplates() []models.LabelTemplate {
+	labelTemplatesOnce.Do(func() {
+		labelTemplates = append(labelTemplates, defaultLabelTemplates...)
+
+		path := os.Getenv("LABEL_TEMPLATES_FILE")
+		if path == "" {
+			return
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			log.Println("Error reading LABEL_TEMPLATES_FILE:", err.Error())
+			return
+		}
+		var custom []models.LabelTemplate
+		if err := json.Unmarshal(raw, &custom); err != nil {
+			log.Println("Error parsing LABEL_TEMPLATES_FILE:", err.Error())
+			return
+		}
+		for _, t := range custom {
+			if reason := labelTemplateError(t); reason != "" {
+				log.Println("Skipping invalid label template:", t.Name, reason)
+				continue
+			}
+			replaced := false
+			for i := range labelTemplates {
+				if labelTemplates[i].Name == t.Name {
+					labelTemplates[i] = t
+					replaced = true
+				}
+			}
+			if !replaced {
+				labelTemplates = append(labelTemplates, t)
+			}
+		}
+	})
+	return labelTemplates
+}
+
+// FindLabelTemplate mencari template label berdasarkan nama
+func FindLabelTemplate(name string) (models.LabelTemplate, bool) {
+	for _, t := range LabelTemplates() {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return models.LabelTemplate{}, false
+}
+
+// labelTemplateError mengembalikan alasan template tidak valid, atau string kosong jika valid.
+// font_size harus lebih dari 1 pt karena teks kecil di label PDF memakai font_size - 1,
+// dan grid label PDF harus muat di dalam halaman.
+func labelTemplateError(t models.LabelTemplate) string {
+	switch {
+	case t.Name == "":
+		return "name kosong"
+	case t.Format != "pdf" && t.Format != "zpl":
+		return "format harus pdf atau zpl"
+	case t.LabelWidth <= 0 || t.LabelHeight <= 0:
+		return "label_width dan label_height harus lebih dari 0"
+	case t.FontSize <= 1:
+		return "font_size harus lebih dari 1"
+	case t.MarginLeft < 0 || t.MarginTop < 0 || t.GapX < 0 || t.GapY < 0:
+		return "margin dan gap tidak boleh negatif"
+	case t.DPI < 0:
+		return "dpi tidak boleh negatif"
+	}
+	if t.Format == "zpl" {
+		return ""
+	}
+
+	const tolerance = 0.01 // pembulatan float, mis. 10 x 29.7 mm pada A4
+	switch {
+	case t.PageWidth <= 0 || t.PageHeight <= 0:
+		return "page_width dan page_height harus lebih dari 0"
+	case t.Columns <= 0 || t.Rows <= 0:
+		return "columns dan rows harus lebih dari 0"
+	case t.MarginLeft+float64(t.Columns)*t.LabelWidth+float64(t.Columns-1)*t.GapX > t.PageWidth+tolerance:
+		return "lebar grid label melebihi page_width"
+	case t.MarginTop+float64(t.Rows)*t.LabelHeight+float64(t.Rows-1)*t.GapY > t.PageHeight+tolerance:
+		return "tinggi grid label melebihi page_height"
+	}
+	return ""
+}
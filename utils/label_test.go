@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+
+	"warehouse-inventory-server/models"
+)
+
+func TestLabelTemplateError(t *testing.T) {
+	for _, tpl := range defaultLabelTemplates {
+		if reason := labelTemplateError(tpl); reason != "" {
+			t.Errorf("template bawaan %s tidak valid: %s", tpl.Name, reason)
+		}
+	}
+
+	pdf := models.LabelTemplate{Name: "custom", Format: "pdf", PageWidth: 210, PageHeight: 297, Columns: 3, Rows: 8,
+		LabelWidth: 70, LabelHeight: 37, FontSize: 9}
+	tests := []struct {
+		name   string
+		modify func(*models.LabelTemplate)
+		valid  bool
+	}{
+		{"valid", func(*models.LabelTemplate) {}, true},
+		{"zpl tanpa halaman", func(t *models.LabelTemplate) { t.Format, t.PageWidth, t.Columns, t.Rows = "zpl", 0, 0, 0 }, true},
+		{"tanpa nama", func(t *models.LabelTemplate) { t.Name = "" }, false},
+		{"format tidak dikenal", func(t *models.LabelTemplate) { t.Format = "png" }, false},
+		{"label_width 0", func(t *models.LabelTemplate) { t.LabelWidth = 0 }, false},
+		{"font_size 1", func(t *models.LabelTemplate) { t.FontSize = 1 }, false},
+		{"font_size 0 zpl", func(t *models.LabelTemplate) { t.Format, t.FontSize = "zpl", 0 }, false},
+		{"margin negatif", func(t *models.LabelTemplate) { t.MarginLeft = -1 }, false},
+		{"dpi negatif", func(t *models.LabelTemplate) { t.DPI = -203 }, false},
+		{"page_width 0", func(t *models.LabelTemplate) { t.PageWidth = 0 }, false},
+		{"columns 0", func(t *models.LabelTemplate) { t.Columns = 0 }, false},
+		{"rows 0", func(t *models.LabelTemplate) { t.Rows = 0 }, false},
+		{"grid melebihi lebar", func(t *models.LabelTemplate) { t.GapX = 1 }, false},
+		{"grid melebihi tinggi", func(t *models.LabelTemplate) { t.MarginTop = 2 }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl := pdf
+			tt.modify(&tpl)
+			if reason := labelTemplateError(tpl); (reason == "") != tt.valid {
+				t.Errorf("labelTemplateError = %q, want valid %v", reason, tt.valid)
+			}
+		})
+	}
+}
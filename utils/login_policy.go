@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// LoginPolicy menyimpan aturan proteksi brute-force untuk endpoint login
+type LoginPolicy struct {
+	MaxAttempts     int           // jumlah gagal login per akun sebelum akun dikunci
+	LockoutDuration time.Duration // lama akun terkunci
+	IPMaxAttempts   int           // jumlah gagal login per IP dalam IPWindow sebelum IP diblokir
+	IPWindow        time.Duration // jendela waktu perhitungan gagal login per IP
+	BaseDelay       time.Duration // jeda minimal setelah gagal login pertama
+	MaxDelay        time.Duration // batas atas jeda progresif
+}
+
+// LoadLoginPolicy membaca konfigurasi LoginPolicy dari environment variable, dengan nilai default jika tidak diset
+func LoadLoginPolicy() LoginPolicy {
+	return LoginPolicy{
+		MaxAttempts:     envInt("LOGIN_MAX_ATTEMPTS", 5),
+		LockoutDuration: time.Duration(envInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+		IPMaxAttempts:   envInt("LOGIN_IP_MAX_ATTEMPTS", 20),
+		IPWindow:        time.Duration(envInt("LOGIN_IP_WINDOW_MINUTES", 15)) * time.Minute,
+		BaseDelay:       time.Second,
+		MaxDelay:        30 * time.Second,
+	}
+}
+
+// Delay mengembalikan jeda yang wajib ditunggu setelah sejumlah gagal login berturut-turut.
+// Jeda berlipat dua setiap kegagalan (1s, 2s, 4s, ...) hingga MaxDelay.
+func (p LoginPolicy) Delay(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
+
+// RegisterFailure mengembalikan failed_login_count dan locked_until baru setelah satu kali gagal login.
+// Jika lockout sebelumnya sudah berakhir, counter dimulai lagi dari 1 agar satu kali salah password
+// setelah lockout tidak langsung mengunci akun kembali.
+func (p LoginPolicy) RegisterFailure(count int, lockedUntil *time.Time, now time.Time) (int, *time.Time) {
+	if lockedUntil != nil && !lockedUntil.After(now) {
+		count, lockedUntil = 0, nil
+	}
+	count++
+	if count >= p.MaxAttempts {
+		until := now.Add(p.LockoutDuration)
+		return count, &until
+	}
+	return count, lockedUntil
+}
+
+func envInt(key string, fallback int) int {
+	val, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || val <= 0 {
+		return fallback
+	}
+	return val
+}
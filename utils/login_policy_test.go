@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginPolicyDelay(t *testing.T) {
+	p := LoginPolicy{BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{5, 16 * time.Second},
+		{6, 30 * time.Second}, // 32s dibatasi MaxDelay
+		{1000, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.Delay(tt.failures); got != tt.want {
+			t.Errorf("Delay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestLoginPolicyRegisterFailure(t *testing.T) {
+	p := LoginPolicy{MaxAttempts: 5, LockoutDuration: 15 * time.Minute}
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+
+	tests := []struct {
+		name        string
+		count       int
+		lockedUntil *time.Time
+		wantCount   int
+		wantLocked  *time.Time
+	}{
+		{"gagal pertama", 0, nil, 1, nil},
+		{"belum mencapai batas", 3, nil, 4, nil},
+		{"mencapai batas dikunci", 4, nil, 5, at(15 * time.Minute)},
+		{"lockout berakhir, gagal sekali lagi", 5, at(-time.Minute), 1, nil},
+		{"lockout berakhir tepat sekarang", 5, at(0), 1, nil},
+		{"masih terkunci diperpanjang", 5, at(time.Minute), 6, at(15 * time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, locked := p.RegisterFailure(tt.count, tt.lockedUntil, now)
+			if count != tt.wantCount {
+				t.Errorf("count = %d, want %d", count, tt.wantCount)
+			}
+			switch {
+			case (locked == nil) != (tt.wantLocked == nil):
+				t.Errorf("locked_until = %v, want %v", locked, tt.wantLocked)
+			case locked != nil && !locked.Equal(*tt.wantLocked):
+				t.Errorf("locked_until = %v, want %v", *locked, *tt.wantLocked)
+			}
+		})
+	}
+}
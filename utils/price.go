@@ -0,0 +1,30 @@
+package utils
+
+import "math"
+
+// ApplyPriceChange menghitung harga baru: jenis "persen" (nilai 8 = naik 8%) atau "nominal" (ditambah nilai)
+func ApplyPriceChange(harga float64, jenis string, nilai float64) float64 {
+	if jenis == "persen" {
+		return harga * (1 + nilai/100)
+	}
+	return harga + nilai
+}
+
+// RoundPrice membulatkan harga ke kelipatan tertentu ke arah "terdekat", "atas" atau "bawah".
+// Kelipatan 0 membulatkan ke 2 desimal (sesuai kolom DECIMAL(15,2)).
+func RoundPrice(harga, kelipatan float64, arah string) float64 {
+	if kelipatan <= 0 {
+		kelipatan = 0.01
+	}
+	// Pembagian float (mis. 107.99999999) dikoreksi dulu agar tidak bergeser satu kelipatan
+	n := math.Round(harga/kelipatan*1e6) / 1e6
+	switch arah {
+	case "atas":
+		n = math.Ceil(n)
+	case "bawah":
+		n = math.Floor(n)
+	default:
+		n = math.Round(n)
+	}
+	return math.Round(n*kelipatan*100) / 100
+}
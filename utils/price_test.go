@@ -0,0 +1,52 @@
+package utils
+
+import "testing"
+
+func TestRoundPrice(t *testing.T) {
+	tests := []struct {
+		name      string
+		harga     float64
+		kelipatan float64
+		arah      string
+		want      float64
+	}{
+		{"terdekat ke atas", 17499, 500, "terdekat", 17500},
+		{"terdekat setengah ke atas", 17250, 500, "terdekat", 17500},
+		{"terdekat ke bawah", 17249, 500, "terdekat", 17000},
+		{"arah kosong sama dengan terdekat", 17249, 500, "", 17000},
+		{"atas", 17001, 500, "atas", 17500},
+		{"bawah", 17499, 500, "bawah", 17000},
+		{"sudah kelipatan tetap", 17500, 500, "atas", 17500},
+		{"galat float tidak naik satu kelipatan", 100 * 1.08, 1, "atas", 108},
+		{"galat float tidak turun satu kelipatan", 1.1 * 3, 0.1, "bawah", 3.3},
+		{"kelipatan 0 ke 2 desimal", 12.341, 0, "terdekat", 12.34},
+		{"kelipatan 0 setengah sen", 12.345, 0, "terdekat", 12.35},
+		{"kelipatan negatif ke 2 desimal", 9.999, -5, "bawah", 9.99},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoundPrice(tt.harga, tt.kelipatan, tt.arah); got != tt.want {
+				t.Errorf("RoundPrice(%v, %v, %q) = %v, want %v", tt.harga, tt.kelipatan, tt.arah, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPriceChange(t *testing.T) {
+	tests := []struct {
+		harga float64
+		jenis string
+		nilai float64
+		want  float64
+	}{
+		{10000, "persen", 10, 11000},
+		{10000, "persen", -25, 7500},
+		{10000, "nominal", 500, 10500},
+		{10000, "nominal", -500, 9500},
+	}
+	for _, tt := range tests {
+		if got := RoundPrice(ApplyPriceChange(tt.harga, tt.jenis, tt.nilai), 0, "terdekat"); got != tt.want {
+			t.Errorf("ApplyPriceChange(%v, %q, %v) = %v, want %v", tt.harga, tt.jenis, tt.nilai, got, tt.want)
+		}
+	}
+}
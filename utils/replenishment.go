@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"math"
+
+	"warehouse-inventory-server/models"
+)
+
+// LoadReplenishmentParams membaca parameter default saran pembelian dari environment variable
+func LoadReplenishmentParams() models.ReplenishmentParams {
+	return models.ReplenishmentParams{
+		WindowDays:      envInt("REPLENISHMENT_WINDOW_DAYS", 30),
+		SafetyDays:      envInt("REPLENISHMENT_SAFETY_DAYS", 3),
+		CoverDays:       envInt("REPLENISHMENT_COVER_DAYS", 14),
+		DefaultLeadTime: envInt("REPLENISHMENT_LEAD_TIME_DAYS", 7),
+	}
+}
+
+// SuggestReplenishment menghitung saran pembelian satu barang:
+//
+//	rata-rata harian = total keluar / window
+//	safety stock     = max(rata-rata x safety days, stok minimum)
+//	reorder point    = rata-rata x lead time + safety stock
+//	target stok      = rata-rata x (lead time + cover days) + safety stock, dibatasi stok maksimum
+//
+// Saran qty = target - stok akhir (minimal reorder qty) jika stok akhir <= reorder point, selain itu 0.
+func SuggestReplenishment(u models.ReplenishmentUsage, p models.ReplenishmentParams) models.ReplenishmentSuggestion {
+	avg := float64(u.TotalKeluar) / float64(p.WindowDays)
+
+	leadTime := p.DefaultLeadTime
+	if u.LeadTimeDays != nil {
+		leadTime = *u.LeadTimeDays
+	}
+	supplier := ""
+	if u.Supplier != nil {
+		supplier = *u.Supplier
+	}
+
+	safetyStock := max(int(math.Ceil(avg*float64(p.SafetyDays))), u.StokMinimum)
+	reorderPoint := int(math.Ceil(avg*float64(leadTime))) + safetyStock
+	target := int(math.Ceil(avg*float64(leadTime+p.CoverDays))) + safetyStock
+	if u.StokMaksimum > 0 && target > u.StokMaksimum {
+		target = max(u.StokMaksimum, reorderPoint)
+	}
+
+	qty := 0
+	if u.StokAkhir <= reorderPoint && target > u.StokAkhir {
+		qty = max(target-u.StokAkhir, u.ReorderQty)
+	}
+
+	return models.ReplenishmentSuggestion{
+		BarangID:       u.BarangID,
+		KodeBarang:     u.KodeBarang,
+		NamaBarang:     u.NamaBarang,
+		Satuan:         u.Satuan,
+		Supplier:       supplier,
+		StokAkhir:      u.StokAkhir,
+		TotalKeluar:    u.TotalKeluar,
+		RataRataHarian: math.Round(avg*100) / 100,
+		LeadTimeDays:   leadTime,
+		SafetyStock:    safetyStock,
+		ReorderPoint:   reorderPoint,
+		TargetStok:     target,
+		SaranQty:       qty,
+		HargaBeli:      u.HargaBeli,
+		Subtotal:       float64(qty) * u.HargaBeli,
+	}
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+
+	"warehouse-inventory-server/models"
+)
+
+func TestSuggestReplenishment(t *testing.T) {
+	params := models.ReplenishmentParams{WindowDays: 30, SafetyDays: 3, CoverDays: 14, DefaultLeadTime: 7}
+	zero := 0
+
+	// Pemakaian 60 dalam 30 hari = 2/hari: safety 6, reorder point 2x7+6 = 20, target 2x21+6 = 48
+	tests := []struct {
+		name         string
+		usage        models.ReplenishmentUsage
+		safety       int
+		reorderPoint int
+		target       int
+		qty          int
+	}{
+		{"di bawah reorder point", models.ReplenishmentUsage{TotalKeluar: 60, StokAkhir: 10}, 6, 20, 48, 38},
+		{"tepat di reorder point", models.ReplenishmentUsage{TotalKeluar: 60, StokAkhir: 20}, 6, 20, 48, 28},
+		{"di atas reorder point", models.ReplenishmentUsage{TotalKeluar: 60, StokAkhir: 21}, 6, 20, 48, 0},
+		{"minimal reorder qty", models.ReplenishmentUsage{TotalKeluar: 60, StokAkhir: 10, ReorderQty: 50}, 6, 20, 48, 50},
+		{"stok minimum sebagai safety stock", models.ReplenishmentUsage{TotalKeluar: 60, StokAkhir: 10, StokMinimum: 10}, 10, 24, 52, 42},
+		{"dibatasi stok maksimum", models.ReplenishmentUsage{TotalKeluar: 60, StokAkhir: 10, StokMaksimum: 30}, 6, 20, 30, 20},
+		{"stok maksimum di bawah reorder point", models.ReplenishmentUsage{TotalKeluar: 60, StokAkhir: 10, StokMaksimum: 15}, 6, 20, 20, 10},
+		{"lead time supplier 0", models.ReplenishmentUsage{TotalKeluar: 60, StokAkhir: 10, LeadTimeDays: &zero}, 6, 6, 34, 0},
+		{"tanpa pemakaian", models.ReplenishmentUsage{StokAkhir: 0}, 0, 0, 0, 0},
+		{"pemakaian pecahan dibulatkan ke atas", models.ReplenishmentUsage{TotalKeluar: 1, StokAkhir: 0}, 1, 2, 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SuggestReplenishment(tt.usage, params)
+			if got.SafetyStock != tt.safety || got.ReorderPoint != tt.reorderPoint || got.TargetStok != tt.target || got.SaranQty != tt.qty {
+				t.Errorf("SuggestReplenishment = safety %d, reorder point %d, target %d, qty %d; want %d, %d, %d, %d",
+					got.SafetyStock, got.ReorderPoint, got.TargetStok, got.SaranQty, tt.safety, tt.reorderPoint, tt.target, tt.qty)
+			}
+		})
+	}
+}
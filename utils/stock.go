@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// LowStockThreshold membaca titik reorder default dari env LOW_STOCK_THRESHOLD (default 5),
+// dipakai untuk barang yang belum memiliki stok_minimum
+func LowStockThreshold() int {
+	threshold, err := strconv.Atoi(os.Getenv("LOW_STOCK_THRESHOLD"))
+	if err != nil || threshold < 0 {
+		return 5
+	}
+	return threshold
+}
+
+// StockSnapshotInterval membaca interval snapshot stok otomatis dari env STOCK_SNAPSHOT_INTERVAL_HOURS (default 24)
+func StockSnapshotInterval() time.Duration {
+	return time.Duration(envInt("STOCK_SNAPSHOT_INTERVAL_HOURS", 24)) * time.Hour
+}
+
+// StockSnapshotTime mengembalikan waktu snapshot yang aman untuk waktu now: mundur satu menit agar transaksi
+// yang sedang berjalan sudah ter-commit, dibulatkan ke bawah ke awal jam
+func StockSnapshotTime(now time.Time) time.Time {
+	return now.Add(-time.Minute).Truncate(time.Hour)
+}
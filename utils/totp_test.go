@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// Secret ASCII "12345678901234567890" dari test vector RFC 6238 (SHA1), dalam base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTPVectors(t *testing.T) {
+	// 6 digit terakhir dari kode 8 digit di RFC 6238 appendix B
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		step, ok := ValidateTOTP(rfc6238Secret, tt.code, time.Unix(tt.unix, 0))
+		if !ok || step != tt.unix/totpPeriod {
+			t.Errorf("ValidateTOTP(%q at %d) = (%d, %v), want (%d, true)", tt.code, tt.unix, step, ok, tt.unix/totpPeriod)
+		}
+	}
+}
+
+func TestValidateTOTPWindow(t *testing.T) {
+	const issued = 1111111109 // kode 081804 berlaku untuk step 37037036
+	const step = issued / totpPeriod
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		offset time.Duration
+		ok     bool
+	}{
+		{"step yang sama", rfc6238Secret, "081804", 0, true},
+		{"satu step sesudahnya", rfc6238Secret, "081804", 30 * time.Second, true},
+		{"satu step sebelumnya", rfc6238Secret, "081804", -30 * time.Second, true},
+		{"dua step sesudahnya", rfc6238Secret, "081804", 60 * time.Second, false},
+		{"dua step sebelumnya", rfc6238Secret, "081804", -60 * time.Second, false},
+		{"spasi di sekitar kode", rfc6238Secret, " 081804 ", 0, true},
+		{"secret huruf kecil", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "081804", 0, true},
+		{"kode salah", rfc6238Secret, "081805", 0, false},
+		{"kode terlalu pendek", rfc6238Secret, "81804", 0, false},
+		{"secret bukan base32", "not-base32!", "081804", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ValidateTOTP(tt.secret, tt.code, time.Unix(issued, 0).Add(tt.offset))
+			if ok != tt.ok {
+				t.Fatalf("ValidateTOTP ok = %v, want %v", ok, tt.ok)
+			}
+			// Step yang dikembalikan adalah step kode, bukan step waktu validasi, sehingga pemanggil
+			// yang menyimpan step terakhir (step <= last ditolak) menolak kode yang dipakai ulang
+			if ok && got != step {
+				t.Errorf("ValidateTOTP step = %d, want %d", got, step)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecretRoundTrip(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("secret %q tidak dapat dibaca sebagai 160-bit base32", secret)
+	}
+	now := time.Now()
+	if _, ok := ValidateTOTP(secret, totpCode(key, now.Unix()/totpPeriod), now); !ok {
+		t.Error("kode dari secret baru tidak valid")
+	}
+}